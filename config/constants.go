@@ -14,6 +14,8 @@ const (
 	SecurityParallelismKey = "security.parallelism"
 	SecuritySaltLengthKey  = "security.salt.length"
 	SecurityKeyLengthKey   = "security.key.length"
+	SecurityTotpIssuerKey  = "security.totp.issuer"
+	SecurityTotpKeyKey     = "security.totp.key"
 	BasicAuthUserKey       = "basic.auth.user"
 	BasicAuthPasswordKey   = "basic.auth.password"
 	PostgresHostKey        = "postgres.host"
@@ -38,6 +40,14 @@ const (
 	SmtpPasswordKey        = "smtp.password"
 )
 
+// Login lockout keys
+const (
+	SecurityLoginMaxAttemptsKey   = "security.login.max_attempts"
+	SecurityLoginIPMaxAttemptsKey = "security.login.ip_max_attempts"
+	SecurityLoginLockoutKey       = "security.login.lockout"
+	SecurityLoginMaxLockoutKey    = "security.login.max_lockout"
+)
+
 const (
 	Development = "development"
 	Test        = "test"
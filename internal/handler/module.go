@@ -3,11 +3,25 @@ package handler
 import (
 	handlerV1 "github.com/abdivasiyev/project_template/internal/handler/v1"
 	appV1 "github.com/abdivasiyev/project_template/internal/handler/v1/app"
+	assignmentV1 "github.com/abdivasiyev/project_template/internal/handler/v1/assignment"
 	authV1 "github.com/abdivasiyev/project_template/internal/handler/v1/auth"
+	carV1 "github.com/abdivasiyev/project_template/internal/handler/v1/car"
+	commentV1 "github.com/abdivasiyev/project_template/internal/handler/v1/comment"
+	companyV1 "github.com/abdivasiyev/project_template/internal/handler/v1/company"
+	dashboardV1 "github.com/abdivasiyev/project_template/internal/handler/v1/dashboard"
+	departmentV1 "github.com/abdivasiyev/project_template/internal/handler/v1/department"
 	docV1 "github.com/abdivasiyev/project_template/internal/handler/v1/doc"
+	driverV1 "github.com/abdivasiyev/project_template/internal/handler/v1/driver"
+	entityV1 "github.com/abdivasiyev/project_template/internal/handler/v1/entity"
 	fileV1 "github.com/abdivasiyev/project_template/internal/handler/v1/file"
+	formV1 "github.com/abdivasiyev/project_template/internal/handler/v1/form"
 	pprofV1 "github.com/abdivasiyev/project_template/internal/handler/v1/pprof"
 	roleV1 "github.com/abdivasiyev/project_template/internal/handler/v1/role"
+	sessionV1 "github.com/abdivasiyev/project_template/internal/handler/v1/session"
+	statusV1 "github.com/abdivasiyev/project_template/internal/handler/v1/status"
+	stepV1 "github.com/abdivasiyev/project_template/internal/handler/v1/step"
+	trailerV1 "github.com/abdivasiyev/project_template/internal/handler/v1/trailer"
+	truckV1 "github.com/abdivasiyev/project_template/internal/handler/v1/truck"
 	userV1 "github.com/abdivasiyev/project_template/internal/handler/v1/user"
 	"go.uber.org/fx"
 )
@@ -20,5 +34,19 @@ var Module = fx.Options(
 	roleV1.Module,
 	userV1.Module,
 	appV1.Module,
+	truckV1.Module,
+	trailerV1.Module,
+	carV1.Module,
+	driverV1.Module,
+	formV1.Module,
+	stepV1.Module,
+	departmentV1.Module,
+	companyV1.Module,
+	dashboardV1.Module,
+	entityV1.Module,
+	assignmentV1.Module,
+	statusV1.Module,
+	commentV1.Module,
+	sessionV1.Module,
 	handlerV1.Module,
 )
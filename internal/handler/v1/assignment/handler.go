@@ -0,0 +1,171 @@
+package assignment
+
+import (
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"github.com/abdivasiyev/project_template/config"
+	serviceV1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/response"
+
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+var Module = fx.Provide(NewHandler)
+
+type Handler struct {
+	environment string
+	log         logger.Logger
+	service     serviceV1.AssignmentServiceV1
+}
+
+type Params struct {
+	fx.In
+	Config  config.Config
+	Log     logger.Logger
+	Service serviceV1.AssignmentServiceV1
+}
+
+func NewHandler(params Params) *Handler {
+	return &Handler{
+		environment: params.Config.GetString(config.EnvironmentKey),
+		log:         params.Log,
+		service:     params.Service,
+	}
+}
+
+// Assign godoc
+// @Security ApiKeyAuth
+// @Summary Assigns driver to truck or trailer
+// @Description Driver has one active truck and one active trailer, previous assignments of driver and entity are closed
+// @Accept  json
+// @Produce  json
+// @Param assignDriver body models.AssignDriverRequest true "Assign driver request"
+// @Success 201 {object} models.GetAssignmentResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags assignment
+// @Router /v1/assignment [post]
+func (h *Handler) Assign() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.AssignDriverRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		user, _ := c.Get("user")
+
+		request.AssignedBy = (user.(models.GetUserResponse)).ID
+
+		assignment, err := h.service.Assign(c, request)
+		if err != nil {
+			h.log.Errorf("could not assign driver: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not assign driver",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    assignment,
+			StatusCode: http.StatusCreated,
+		})
+	}
+}
+
+// Unassign godoc
+// @Security ApiKeyAuth
+// @Summary Closes active assignment of truck or trailer
+// @Description Closed assignment stays in history
+// @Accept  json
+// @Produce  json
+// @Param entity_type path string true "Entity type: truck or trailer"
+// @Param entity_id path string true "Entity id"
+// @Success 204 {object} models.SuccessResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags assignment
+// @Router /v1/assignment/{entity_type}/{entity_id} [delete]
+func (h *Handler) Unassign() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.UnassignDriverRequest
+
+		if err := c.ShouldBindUri(&request); err != nil {
+			h.log.Errorf("could not bind uri params: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not bind uri params",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		if err := h.service.Unassign(c, request); err != nil {
+			h.log.Errorf("could not unassign driver: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not unassign driver",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    models.SuccessResponse{Ok: true},
+			StatusCode: http.StatusNoContent,
+		})
+	}
+}
+
+// GetHistory godoc
+// @Security ApiKeyAuth
+// @Summary Returns history of driver assignments
+// @Description Filters by driver, entity type and entity, active returns only current assignments
+// @Accept  json
+// @Produce  json
+// @Param filter query models.GetAssignmentHistoryRequest false "Filter params"
+// @Success 200 {object} models.GetAssignmentHistoryResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags assignment
+// @Router /v1/assignment [get]
+func (h *Handler) GetHistory() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.GetAssignmentHistoryRequest
+
+		if err := c.ShouldBindQuery(&request); err != nil {
+			h.log.Errorf("could not bind query: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not bind query",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		history, err := h.service.GetHistory(c, request)
+		if err != nil {
+			h.log.Errorf("could not get assignment history: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get assignment history",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    history,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
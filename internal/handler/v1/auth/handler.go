@@ -1,9 +1,12 @@
 package auth
 
 import (
+	"errors"
 	"github.com/abdivasiyev/project_template/config"
 	"go.uber.org/fx"
+	"io"
 	"net/http"
+	"strings"
 
 	serviceV1 "github.com/abdivasiyev/project_template/internal/services/v1"
 	"github.com/abdivasiyev/project_template/pkg/logger"
@@ -105,6 +108,8 @@ func (h *Handler) Refresh() gin.HandlerFunc {
 			return
 		}
 
+		request.UserAgent, request.IP = c.Request.UserAgent(), c.ClientIP()
+
 		resp, err := h.service.Refresh(c, request)
 		if err != nil {
 			h.log.Errorf("could not login user: %v", err)
@@ -149,6 +154,8 @@ func (h *Handler) Login() gin.HandlerFunc {
 			return
 		}
 
+		request.UserAgent, request.IP = c.Request.UserAgent(), c.ClientIP()
+
 		resp, err := h.service.Login(c, request)
 		if err != nil {
 			h.log.Errorf("could not login user: %v", err)
@@ -167,3 +174,310 @@ func (h *Handler) Login() gin.HandlerFunc {
 		})
 	}
 }
+
+// Logout godoc
+// @Security ApiKeyAuth
+// @Summary Logout revokes current access token and given refresh token
+// @Description Returns ok if success
+// @Accept  json
+// @Produce  json
+// @Param logoutRequest body models.LogoutRequest false "Refresh token"
+// @Success 200 {object} models.SuccessResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags auth
+// @Router /v1/auth/logout [post]
+func (h *Handler) Logout() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.LogoutRequest
+
+		// refresh token is optional, so empty body is allowed
+		if err := c.ShouldBindJSON(&request); err != nil && !errors.Is(err, io.EOF) {
+			h.log.Errorf("could not unmarshal json request: %v", err)
+
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not unmarshal json body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		if tokens := strings.Split(strings.TrimSpace(c.GetHeader("Authorization")), " "); len(tokens) == 2 {
+			request.AccessToken = tokens[1]
+		}
+
+		resp, err := h.service.Logout(c, request)
+		if err != nil {
+			h.log.Errorf("could not logout user: %v", err)
+
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not logout",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// LogoutAll godoc
+// @Security ApiKeyAuth
+// @Summary LogoutAll revokes all tokens of current user
+// @Description Returns ok if success
+// @Produce  json
+// @Success 200 {object} models.SuccessResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags auth
+// @Router /v1/auth/logout/all [post]
+func (h *Handler) LogoutAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, _ := c.Get("user")
+
+		resp, err := h.service.LogoutAll(c, (user.(models.GetUserResponse)).ID)
+		if err != nil {
+			h.log.Errorf("could not logout user sessions: %v", err)
+
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not logout",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// VerifyTwoFactor godoc
+// @Summary Completes login with two-factor authentication code
+// @Description Accepts totp code or recovery code for challenge returned by login, returns access token response
+// @Accept  json
+// @Produce  json
+// @Param verifyTwoFactorRequest body models.VerifyTwoFactorRequest true "Two-factor code"
+// @Success 200 {object} models.AuthenticationResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags auth
+// @Router /v1/auth/login/2fa [post]
+func (h *Handler) VerifyTwoFactor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.VerifyTwoFactorRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not unmarshal json request: %v", err)
+
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not unmarshal json body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		request.UserAgent, request.IP = c.Request.UserAgent(), c.ClientIP()
+
+		resp, err := h.service.VerifyTwoFactor(c, request)
+		if err != nil {
+			h.log.Errorf("could not verify two factor code: %v", err)
+
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not generate token",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// SetupTwoFactor godoc
+// @Security ApiKeyAuth
+// @Summary Starts two-factor authentication setup
+// @Description Returns totp secret and provisioning uri to show as QR code, setup must be confirmed with code
+// @Produce  json
+// @Success 200 {object} models.SetupTwoFactorResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags auth
+// @Router /v1/auth/2fa/setup [post]
+func (h *Handler) SetupTwoFactor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, _ := c.Get("user")
+
+		resp, err := h.service.SetupTwoFactor(c, (user.(models.GetUserResponse)).ID)
+		if err != nil {
+			h.log.Errorf("could not setup two factor: %v", err)
+
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not setup two factor",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// ConfirmTwoFactor godoc
+// @Security ApiKeyAuth
+// @Summary Enables two-factor authentication
+// @Description Returns recovery codes, they are shown only once
+// @Accept  json
+// @Produce  json
+// @Param twoFactorCodeRequest body models.TwoFactorCodeRequest true "Totp code"
+// @Success 200 {object} models.RecoveryCodesResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags auth
+// @Router /v1/auth/2fa/confirm [post]
+func (h *Handler) ConfirmTwoFactor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.TwoFactorCodeRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not unmarshal json request: %v", err)
+
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not unmarshal json body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		user, _ := c.Get("user")
+
+		request.UserID = (user.(models.GetUserResponse)).ID
+
+		resp, err := h.service.ConfirmTwoFactor(c, request)
+		if err != nil {
+			h.log.Errorf("could not confirm two factor: %v", err)
+
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not confirm two factor",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// DisableTwoFactor godoc
+// @Security ApiKeyAuth
+// @Summary Disables two-factor authentication
+// @Description Requires totp code or recovery code
+// @Accept  json
+// @Produce  json
+// @Param twoFactorCodeRequest body models.TwoFactorCodeRequest true "Totp or recovery code"
+// @Success 200 {object} models.SuccessResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags auth
+// @Router /v1/auth/2fa/disable [post]
+func (h *Handler) DisableTwoFactor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.TwoFactorCodeRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not unmarshal json request: %v", err)
+
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not unmarshal json body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		user, _ := c.Get("user")
+
+		request.UserID = (user.(models.GetUserResponse)).ID
+
+		resp, err := h.service.DisableTwoFactor(c, request)
+		if err != nil {
+			h.log.Errorf("could not disable two factor: %v", err)
+
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not disable two factor",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// Unlock godoc
+// @Security ApiKeyAuth
+// @Summary Unlocks login locked after failed attempts
+// @Description Resets failed login counters of user or ip address
+// @Accept  json
+// @Produce  json
+// @Param unlockLoginRequest body models.UnlockLoginRequest true "User id or ip address"
+// @Success 200 {object} models.SuccessResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags auth
+// @Router /v1/auth/unlock [post]
+func (h *Handler) Unlock() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.UnlockLoginRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not unmarshal json request: %v", err)
+
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not unmarshal json body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		user, _ := c.Get("user")
+
+		request.UnlockedBy = (user.(models.GetUserResponse)).ID
+
+		resp, err := h.service.Unlock(c, request)
+		if err != nil {
+			h.log.Errorf("could not unlock login: %v", err)
+
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not unlock login",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
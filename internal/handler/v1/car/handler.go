@@ -0,0 +1,328 @@
+package car
+
+import (
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"github.com/abdivasiyev/project_template/config"
+	serviceV1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/response"
+
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+var Module = fx.Provide(NewHandler)
+
+type Handler struct {
+	environment string
+	log         logger.Logger
+	service     serviceV1.CarServiceV1
+}
+
+type Params struct {
+	fx.In
+	Config  config.Config
+	Log     logger.Logger
+	Service serviceV1.CarServiceV1
+}
+
+func NewHandler(params Params) *Handler {
+	return &Handler{
+		environment: params.Config.GetString(config.EnvironmentKey),
+		log:         params.Log,
+		service:     params.Service,
+	}
+}
+
+// Create godoc
+// @Security ApiKeyAuth
+// @Summary Creates new car
+// @Description Returns created car
+// @Accept  json
+// @Produce  json
+// @Param createCar body models.CreateCarRequest true "Create car request"
+// @Success 201 {object} models.GetCarResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags car
+// @Router /v1/car [post]
+func (h *Handler) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateCarRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		resp, err := h.service.Create(c, request)
+		if err != nil {
+			h.log.Errorf("could not create car: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not create car",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusCreated,
+		})
+	}
+}
+
+// Update godoc
+// @Security ApiKeyAuth
+// @Summary Updates car
+// @Description Returns updated car
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Car id"
+// @Param updateCar body models.CreateCarRequest true "Update car request"
+// @Success 200 {object} models.GetCarResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags car
+// @Router /v1/car/{id} [put]
+func (h *Handler) Update() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateCarRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		request.ID = c.Param("id")
+
+		resp, err := h.service.Update(c, request)
+		if err != nil {
+			h.log.Errorf("could not update car: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not update car",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// Delete godoc
+// @Security ApiKeyAuth
+// @Summary Deletes car
+// @Description Deletes requested car
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Car id"
+// @Success 204 {object} models.SuccessResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags car
+// @Router /v1/car/{id} [delete]
+func (h *Handler) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := h.service.Delete(c, c.Param("id")); err != nil {
+			h.log.Errorf("could not delete car: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not delete car",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj: models.SuccessResponse{
+				Ok: true,
+			},
+			StatusCode: http.StatusNoContent,
+		})
+	}
+}
+
+// Get godoc
+// @Security ApiKeyAuth
+// @Summary Gets car
+// @Description Returns car with its booking status
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Car id"
+// @Success 200 {object} models.GetCarResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags car
+// @Router /v1/car/{id} [get]
+func (h *Handler) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		car, err := h.service.Get(c, c.Param("id"))
+		if err != nil {
+			h.log.Errorf("could not get car: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get car",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    car,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// GetAll godoc
+// @Security ApiKeyAuth
+// @Summary Returns all cars
+// @Description Returns cars filtered by search
+// @Accept  json
+// @Produce  json
+// @Param filter query models.GetAllCarsRequest false "Filter params"
+// @Success 200 {object} models.GetAllCarsResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags car
+// @Router /v1/car [get]
+func (h *Handler) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.GetAllCarsRequest
+
+		if err := c.ShouldBindQuery(&request); err != nil {
+			h.log.Errorf("could not bind query: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not bind query",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		cars, err := h.service.GetAll(c, request)
+		if err != nil {
+			h.log.Errorf("could not get cars: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get cars",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    cars,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// CreatePickupDropOff godoc
+// @Security ApiKeyAuth
+// @Summary Records car key handover
+// @Description Records pickup or drop-off of car, pickup of already booked car is rejected
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Car id"
+// @Param handover body models.CarPickupDropOffRequest true "Car pickup/drop-off request"
+// @Success 201 {object} models.GetCarPickupDropOffResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags car
+// @Router /v1/car/{id}/handover [post]
+func (h *Handler) CreatePickupDropOff() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CarPickupDropOffRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		user, _ := c.Get("user")
+
+		request.CarID = c.Param("id")
+		request.KeyGivenByID = (user.(models.GetUserResponse)).ID
+
+		resp, err := h.service.CreatePickupDropOff(c, request)
+		if err != nil {
+			h.log.Errorf("could not create car pickup/drop-off: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not create car pickup/drop-off",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusCreated,
+		})
+	}
+}
+
+// GetPickupDropOffHistory godoc
+// @Security ApiKeyAuth
+// @Summary Returns car pickup and drop-off history
+// @Description Returns paged key handovers of car
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Car id"
+// @Param filter query models.GetCarPickupDropOffHistoryRequest false "Filter params"
+// @Success 200 {object} models.GetCarPickupDropOffHistoryResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags car
+// @Router /v1/car/{id}/history [get]
+func (h *Handler) GetPickupDropOffHistory() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.GetCarPickupDropOffHistoryRequest
+
+		if err := c.ShouldBindQuery(&request); err != nil {
+			h.log.Errorf("could not bind query: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not bind query",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		request.CarID = c.Param("id")
+
+		history, err := h.service.GetPickupDropOffHistory(c, request)
+		if err != nil {
+			h.log.Errorf("could not get car history: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get car history",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    history,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
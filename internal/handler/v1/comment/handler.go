@@ -0,0 +1,248 @@
+package comment
+
+import (
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"github.com/abdivasiyev/project_template/config"
+	serviceV1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/response"
+
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+var Module = fx.Provide(NewHandler)
+
+type Handler struct {
+	environment string
+	log         logger.Logger
+	service     serviceV1.CommentServiceV1
+}
+
+type Params struct {
+	fx.In
+	Config  config.Config
+	Log     logger.Logger
+	Service serviceV1.CommentServiceV1
+}
+
+func NewHandler(params Params) *Handler {
+	return &Handler{
+		environment: params.Config.GetString(config.EnvironmentKey),
+		log:         params.Log,
+		service:     params.Service,
+	}
+}
+
+// Create godoc
+// @Security ApiKeyAuth
+// @Summary Creates new comment
+// @Description Attaches comment to entity, driver_id is required for driver_step comments
+// @Accept  json
+// @Produce  json
+// @Param createComment body models.CreateCommentRequest true "Create comment request"
+// @Success 201 {object} models.GetCommentResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags comment
+// @Router /v1/comment [post]
+func (h *Handler) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateCommentRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		user, _ := c.Get("user")
+
+		request.CreatedBy = (user.(models.GetUserResponse)).ID
+
+		resp, err := h.service.Create(c, request)
+		if err != nil {
+			h.log.Errorf("could not create comment: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not create comment",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusCreated,
+		})
+	}
+}
+
+// Update godoc
+// @Security ApiKeyAuth
+// @Summary Updates comment
+// @Description Updates text and mentions of comment, only author can update comment
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Comment id"
+// @Param updateComment body models.UpdateCommentRequest true "Update comment request"
+// @Success 200 {object} models.GetCommentResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags comment
+// @Router /v1/comment/{id} [put]
+func (h *Handler) Update() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.UpdateCommentRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		user, _ := c.Get("user")
+
+		request.ID = c.Param("id")
+		request.UpdatedBy = (user.(models.GetUserResponse)).ID
+
+		resp, err := h.service.Update(c, request)
+		if err != nil {
+			h.log.Errorf("could not update comment: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not update comment",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// Delete godoc
+// @Security ApiKeyAuth
+// @Summary Deletes comment
+// @Description Only author can delete comment
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Comment id"
+// @Success 204 {object} models.SuccessResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags comment
+// @Router /v1/comment/{id} [delete]
+func (h *Handler) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, _ := c.Get("user")
+
+		if err := h.service.Delete(c, c.Param("id"), (user.(models.GetUserResponse)).ID); err != nil {
+			h.log.Errorf("could not delete comment: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not delete comment",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj: models.SuccessResponse{
+				Ok: true,
+			},
+			StatusCode: http.StatusNoContent,
+		})
+	}
+}
+
+// Get godoc
+// @Security ApiKeyAuth
+// @Summary Gets comment
+// @Description Returns comment with mentioned users
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Comment id"
+// @Success 200 {object} models.GetCommentResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags comment
+// @Router /v1/comment/{id} [get]
+func (h *Handler) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, _ := c.Get("user")
+
+		comment, err := h.service.Get(c, c.Param("id"), (user.(models.GetUserResponse)).ID)
+		if err != nil {
+			h.log.Errorf("could not get comment: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get comment",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    comment,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// GetAll godoc
+// @Security ApiKeyAuth
+// @Summary Returns comments of entity
+// @Description Returns comments of entity ordered by creation time, driver_id is required for driver_step comments
+// @Accept  json
+// @Produce  json
+// @Param filter query models.GetAllCommentsRequest true "Filter params"
+// @Success 200 {object} models.GetAllCommentsResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags comment
+// @Router /v1/comment [get]
+func (h *Handler) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.GetAllCommentsRequest
+
+		if err := c.ShouldBindQuery(&request); err != nil {
+			h.log.Errorf("could not bind query: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not bind query",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		user, _ := c.Get("user")
+
+		request.UserID = (user.(models.GetUserResponse)).ID
+
+		comments, err := h.service.GetAll(c, request)
+		if err != nil {
+			h.log.Errorf("could not get comments: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get comments",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    comments,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package company
+
+import (
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"github.com/abdivasiyev/project_template/config"
+	serviceV1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/response"
+
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+var Module = fx.Provide(NewHandler)
+
+type Handler struct {
+	environment string
+	log         logger.Logger
+	service     serviceV1.CompanyServiceV1
+}
+
+type Params struct {
+	fx.In
+	Config  config.Config
+	Log     logger.Logger
+	Service serviceV1.CompanyServiceV1
+}
+
+func NewHandler(params Params) *Handler {
+	return &Handler{
+		environment: params.Config.GetString(config.EnvironmentKey),
+		log:         params.Log,
+		service:     params.Service,
+	}
+}
+
+// Create godoc
+// @Security ApiKeyAuth
+// @Summary Creates new company
+// @Description Returns created company
+// @Accept  json
+// @Produce  json
+// @Param createCompany body models.CreateCompanyRequest true "Create company request"
+// @Success 201 {object} models.GetCompanyResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags company
+// @Router /v1/company [post]
+func (h *Handler) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateCompanyRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		resp, err := h.service.Create(c, request)
+		if err != nil {
+			h.log.Errorf("could not create company: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not create company",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusCreated,
+		})
+	}
+}
+
+// Update godoc
+// @Security ApiKeyAuth
+// @Summary Updates company
+// @Description Returns updated company
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Company id"
+// @Param updateCompany body models.UpdateCompanyRequest true "Update company request"
+// @Success 200 {object} models.GetCompanyResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags company
+// @Router /v1/company/{id} [put]
+func (h *Handler) Update() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.UpdateCompanyRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		request.ID = c.Param("id")
+
+		resp, err := h.service.Update(c, request)
+		if err != nil {
+			h.log.Errorf("could not update company: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not update company",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// Delete godoc
+// @Security ApiKeyAuth
+// @Summary Deletes company
+// @Description Deletes requested company
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Company id"
+// @Success 204 {object} models.SuccessResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags company
+// @Router /v1/company/{id} [delete]
+func (h *Handler) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := h.service.Delete(c, c.Param("id")); err != nil {
+			h.log.Errorf("could not delete company: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not delete company",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj: models.SuccessResponse{
+				Ok: true,
+			},
+			StatusCode: http.StatusNoContent,
+		})
+	}
+}
+
+// Get godoc
+// @Security ApiKeyAuth
+// @Summary Gets company
+// @Description Returns company
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Company id"
+// @Success 200 {object} models.GetCompanyResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags company
+// @Router /v1/company/{id} [get]
+func (h *Handler) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		company, err := h.service.Get(c, c.Param("id"))
+		if err != nil {
+			h.log.Errorf("could not get company: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get company",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    company,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// GetAll godoc
+// @Security ApiKeyAuth
+// @Summary Returns companies
+// @Description Returns companies ordered by name
+// @Accept  json
+// @Produce  json
+// @Param filter query models.GetAllCompaniesRequest true "Filter params"
+// @Success 200 {object} models.GetAllCompaniesResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags company
+// @Router /v1/company [get]
+func (h *Handler) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.GetAllCompaniesRequest
+
+		if err := c.ShouldBindQuery(&request); err != nil {
+			h.log.Errorf("could not bind query: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not bind query",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		companies, err := h.service.GetAll(c, request)
+		if err != nil {
+			h.log.Errorf("could not get companies: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get companies",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    companies,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
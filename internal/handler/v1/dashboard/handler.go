@@ -0,0 +1,67 @@
+package dashboard
+
+import (
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"github.com/abdivasiyev/project_template/config"
+	serviceV1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+var Module = fx.Provide(NewHandler)
+
+type Handler struct {
+	environment string
+	log         logger.Logger
+	service     serviceV1.DashboardServiceV1
+}
+
+type Params struct {
+	fx.In
+	Config  config.Config
+	Log     logger.Logger
+	Service serviceV1.DashboardServiceV1
+}
+
+func NewHandler(params Params) *Handler {
+	return &Handler{
+		environment: params.Config.GetString(config.EnvironmentKey),
+		log:         params.Log,
+		service:     params.Service,
+	}
+}
+
+// Get godoc
+// @Security ApiKeyAuth
+// @Summary Returns dashboard statistics
+// @Description Returns driver, truck, trailer and car counts with growth against previous 30 days
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} models.GetDashboardResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags dashboard
+// @Router /v1/dashboard [get]
+func (h *Handler) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dashboard, err := h.service.Get(c)
+		if err != nil {
+			h.log.Errorf("could not get dashboard: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get dashboard",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    dashboard,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package department
+
+import (
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"github.com/abdivasiyev/project_template/config"
+	serviceV1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/response"
+
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+var Module = fx.Provide(NewHandler)
+
+type Handler struct {
+	environment string
+	log         logger.Logger
+	service     serviceV1.DepartmentServiceV1
+}
+
+type Params struct {
+	fx.In
+	Config  config.Config
+	Log     logger.Logger
+	Service serviceV1.DepartmentServiceV1
+}
+
+func NewHandler(params Params) *Handler {
+	return &Handler{
+		environment: params.Config.GetString(config.EnvironmentKey),
+		log:         params.Log,
+		service:     params.Service,
+	}
+}
+
+// Create godoc
+// @Security ApiKeyAuth
+// @Summary Creates new department
+// @Description Inserts department into requested position and returns it
+// @Accept  json
+// @Produce  json
+// @Param createDepartment body models.CreateDepartmentRequest true "Create department request"
+// @Success 201 {object} models.GetDepartmentResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags department
+// @Router /v1/department [post]
+func (h *Handler) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateDepartmentRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		resp, err := h.service.Create(c, request)
+		if err != nil {
+			h.log.Errorf("could not create department: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not create department",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusCreated,
+		})
+	}
+}
+
+// Update godoc
+// @Security ApiKeyAuth
+// @Summary Updates department
+// @Description Updates department, moves it into requested position and returns it
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Department id"
+// @Param updateDepartment body models.CreateDepartmentRequest true "Update department request"
+// @Success 200 {object} models.GetDepartmentResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags department
+// @Router /v1/department/{id} [put]
+func (h *Handler) Update() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateDepartmentRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		request.ID = c.Param("id")
+
+		resp, err := h.service.Update(c, request)
+		if err != nil {
+			h.log.Errorf("could not update department: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not update department",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// Delete godoc
+// @Security ApiKeyAuth
+// @Summary Deletes department
+// @Description Deletes requested department and closes the gap in sequences
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Department id"
+// @Success 204 {object} models.SuccessResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags department
+// @Router /v1/department/{id} [delete]
+func (h *Handler) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := h.service.Delete(c, c.Param("id")); err != nil {
+			h.log.Errorf("could not delete department: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not delete department",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj: models.SuccessResponse{
+				Ok: true,
+			},
+			StatusCode: http.StatusNoContent,
+		})
+	}
+}
+
+// Reorder godoc
+// @Security ApiKeyAuth
+// @Summary Reorders departments
+// @Description Puts given departments first in requested order, renumbers all departments without gaps
+// @Accept  json
+// @Produce  json
+// @Param reorderDepartments body models.ReorderDepartmentsRequest true "Reorder departments request"
+// @Success 200 {object} models.GetAllDepartmentsResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags department
+// @Router /v1/department/reorder [put]
+func (h *Handler) Reorder() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.ReorderDepartmentsRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		resp, err := h.service.Reorder(c, request)
+		if err != nil {
+			h.log.Errorf("could not reorder departments: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not reorder departments",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// Get godoc
+// @Security ApiKeyAuth
+// @Summary Gets department
+// @Description Returns department
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Department id"
+// @Success 200 {object} models.GetDepartmentResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags department
+// @Router /v1/department/{id} [get]
+func (h *Handler) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		department, err := h.service.Get(c, c.Param("id"))
+		if err != nil {
+			h.log.Errorf("could not get department: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get department",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    department,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// GetAll godoc
+// @Security ApiKeyAuth
+// @Summary Returns departments
+// @Description Returns departments ordered by sequence
+// @Accept  json
+// @Produce  json
+// @Param filter query models.GetAllDepartmentsRequest true "Filter params"
+// @Success 200 {object} models.GetAllDepartmentsResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags department
+// @Router /v1/department [get]
+func (h *Handler) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.GetAllDepartmentsRequest
+
+		if err := c.ShouldBindQuery(&request); err != nil {
+			h.log.Errorf("could not bind query: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not bind query",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		departments, err := h.service.GetAll(c, request)
+		if err != nil {
+			h.log.Errorf("could not get departments: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get departments",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    departments,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package driver
+
+import (
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"github.com/abdivasiyev/project_template/config"
+	serviceV1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/response"
+
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+var Module = fx.Provide(NewHandler)
+
+type Handler struct {
+	environment string
+	log         logger.Logger
+	service     serviceV1.DriverServiceV1
+}
+
+type Params struct {
+	fx.In
+	Config  config.Config
+	Log     logger.Logger
+	Service serviceV1.DriverServiceV1
+}
+
+func NewHandler(params Params) *Handler {
+	return &Handler{
+		environment: params.Config.GetString(config.EnvironmentKey),
+		log:         params.Log,
+		service:     params.Service,
+	}
+}
+
+// Create godoc
+// @Security ApiKeyAuth
+// @Summary Creates new driver
+// @Description Returns created driver
+// @Accept  json
+// @Produce  json
+// @Param createDriver body models.CreateDriverRequest true "Create driver request"
+// @Success 201 {object} models.GetDriverResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags driver
+// @Router /v1/driver [post]
+func (h *Handler) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateDriverRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		resp, err := h.service.Create(c, request)
+		if err != nil {
+			h.log.Errorf("could not create driver: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not create driver",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusCreated,
+		})
+	}
+}
+
+// Update godoc
+// @Security ApiKeyAuth
+// @Summary Updates driver
+// @Description Returns updated driver
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Driver id"
+// @Param updateDriver body models.CreateDriverRequest true "Update driver request"
+// @Success 200 {object} models.GetDriverResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags driver
+// @Router /v1/driver/{id} [put]
+func (h *Handler) Update() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateDriverRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		request.ID = c.Param("id")
+
+		resp, err := h.service.Update(c, request)
+		if err != nil {
+			h.log.Errorf("could not update driver: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not update driver",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// Get godoc
+// @Security ApiKeyAuth
+// @Summary Gets driver
+// @Description Returns driver with onboarding progress by departments
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Driver id"
+// @Success 200 {object} models.GetDriverResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags driver
+// @Router /v1/driver/{id} [get]
+func (h *Handler) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		driver, err := h.service.Get(c, c.Param("id"))
+		if err != nil {
+			h.log.Errorf("could not get driver: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get driver",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    driver,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// GetAll godoc
+// @Security ApiKeyAuth
+// @Summary Returns all drivers
+// @Description Returns drivers filtered by search
+// @Accept  json
+// @Produce  json
+// @Param filter query models.GetAllDriversRequest false "Filter params"
+// @Success 200 {object} models.GetAllDriversResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags driver
+// @Router /v1/driver [get]
+func (h *Handler) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.GetAllDriversRequest
+
+		if err := c.ShouldBindQuery(&request); err != nil {
+			h.log.Errorf("could not bind query: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not bind query",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		drivers, err := h.service.GetAll(c, request)
+		if err != nil {
+			h.log.Errorf("could not get drivers: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get drivers",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    drivers,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
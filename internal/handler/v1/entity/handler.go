@@ -0,0 +1,128 @@
+package entity
+
+import (
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"github.com/abdivasiyev/project_template/config"
+	serviceV1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/response"
+
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+var Module = fx.Provide(NewHandler)
+
+type Handler struct {
+	environment string
+	log         logger.Logger
+	service     serviceV1.EntityServiceV1
+}
+
+type Params struct {
+	fx.In
+	Config  config.Config
+	Log     logger.Logger
+	Service serviceV1.EntityServiceV1
+}
+
+func NewHandler(params Params) *Handler {
+	return &Handler{
+		environment: params.Config.GetString(config.EnvironmentKey),
+		log:         params.Log,
+		service:     params.Service,
+	}
+}
+
+// Create godoc
+// @Security ApiKeyAuth
+// @Summary Creates new lookup item
+// @Description Adds value to entity type which allows adding items, e.g. driver_type
+// @Accept  json
+// @Produce  json
+// @Param createEntity body models.CreateEntityRequest true "Create entity request"
+// @Success 201 {object} models.GetEntityResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags entity
+// @Router /v1/entity [post]
+func (h *Handler) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateEntityRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		user, _ := c.Get("user")
+
+		request.CreatedBy = (user.(models.GetUserResponse)).ID
+
+		resp, err := h.service.Create(c, request)
+		if err != nil {
+			h.log.Errorf("could not create entity: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not create entity",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusCreated,
+		})
+	}
+}
+
+// GetAll godoc
+// @Security ApiKeyAuth
+// @Summary Returns entities for select fields
+// @Description Returns id/name pairs of entity type: recruiter, safety, company, truck, trailer, driver, driver_type, drug_test_type, fuel_card_status, road_test_status, incab_devices, external_devices, need_repair_devices
+// @Accept  json
+// @Produce  json
+// @Param filter query models.GetEntitiesRequest true "Filter params"
+// @Success 200 {object} models.GetEntitiesResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags entity
+// @Router /v1/entity [get]
+func (h *Handler) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.GetEntitiesRequest
+
+		if err := c.ShouldBindQuery(&request); err != nil {
+			h.log.Errorf("could not bind query: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not bind query",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		entities, err := h.service.GetAll(c, request)
+		if err != nil {
+			h.log.Errorf("could not get entities: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get entities",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    entities,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
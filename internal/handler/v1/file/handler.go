@@ -141,3 +141,89 @@ func (h *Handler) Get() gin.HandlerFunc {
 		c.File(filePath)
 	}
 }
+
+// GenerateZip godoc
+// @Security ApiKeyAuth
+// @Summary Starts generation of zip archive with all files of entity
+// @Description Files are grouped into category folders, returned request id is used to poll status of generation
+// @Accept  json
+// @Produce  json
+// @Param generateZip body models.GenerateZipFileRequest true "Generate zip request"
+// @Success 202 {object} models.GenerateZipFileResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags file
+// @Router /v1/file/zip [post]
+func (h *Handler) GenerateZip() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.GenerateZipFileRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		generateResponse, err := h.service.GenerateZipFile(c, request)
+		if err != nil {
+			h.log.Errorf("could not generate zip file: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not generate zip file",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    generateResponse,
+			StatusCode: http.StatusAccepted,
+		})
+	}
+}
+
+// GetZip godoc
+// @Security ApiKeyAuth
+// @Summary Returns status of zip archive generation
+// @Description Status is one of init, in_process, error, finish; file id and url are returned when status is finish
+// @Accept  json
+// @Produce  json
+// @Param request_id path string true "Request id"
+// @Success 200 {object} models.GetFileResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags file
+// @Router /v1/file/zip/{request_id} [get]
+func (h *Handler) GetZip() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.GetZipFileRequest
+
+		if err := c.ShouldBindUri(&request); err != nil {
+			h.log.Errorf("could not bind uri params: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not bind uri params",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		zipResponse, err := h.service.GetZipFile(c, request.RequestID)
+		if err != nil {
+			h.log.Errorf("could not get zip file: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get zip file",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    zipResponse,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
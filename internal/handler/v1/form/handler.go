@@ -0,0 +1,540 @@
+package form
+
+import (
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"github.com/abdivasiyev/project_template/config"
+	serviceV1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/response"
+
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+var Module = fx.Provide(NewHandler)
+
+type Handler struct {
+	environment string
+	log         logger.Logger
+	service     serviceV1.FormServiceV1
+}
+
+type Params struct {
+	fx.In
+	Config  config.Config
+	Log     logger.Logger
+	Service serviceV1.FormServiceV1
+}
+
+func NewHandler(params Params) *Handler {
+	return &Handler{
+		environment: params.Config.GetString(config.EnvironmentKey),
+		log:         params.Log,
+		service:     params.Service,
+	}
+}
+
+// Create godoc
+// @Security ApiKeyAuth
+// @Summary Creates new form
+// @Description Returns created form
+// @Accept  json
+// @Produce  json
+// @Param createForm body models.CreateFormRequest true "Create form request"
+// @Success 201 {object} models.GetFormResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags form
+// @Router /v1/form [post]
+func (h *Handler) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateFormRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		resp, err := h.service.Create(c, request)
+		if err != nil {
+			h.log.Errorf("could not create form: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not create form",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusCreated,
+		})
+	}
+}
+
+// Update godoc
+// @Security ApiKeyAuth
+// @Summary Updates form
+// @Description Returns updated form, every update creates new version
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Form id"
+// @Param updateForm body models.CreateFormRequest true "Update form request"
+// @Success 200 {object} models.GetFormResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags form
+// @Router /v1/form/{id} [put]
+func (h *Handler) Update() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateFormRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		request.ID = c.Param("id")
+
+		resp, err := h.service.Update(c, request)
+		if err != nil {
+			h.log.Errorf("could not update form: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not update form",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// Delete godoc
+// @Security ApiKeyAuth
+// @Summary Deletes form
+// @Description Deletes requested form
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Form id"
+// @Success 204 {object} models.SuccessResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags form
+// @Router /v1/form/{id} [delete]
+func (h *Handler) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := h.service.Delete(c, c.Param("id")); err != nil {
+			h.log.Errorf("could not delete form: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not delete form",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj: models.SuccessResponse{
+				Ok: true,
+			},
+			StatusCode: http.StatusNoContent,
+		})
+	}
+}
+
+// Get godoc
+// @Security ApiKeyAuth
+// @Summary Gets form
+// @Description Returns form with groups and fields, previous version is returned when version is given
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Form id"
+// @Param filter query models.GetFormRequest false "Filter params"
+// @Success 200 {object} models.GetFormResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags form
+// @Router /v1/form/{id} [get]
+func (h *Handler) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.GetFormRequest
+
+		if err := c.ShouldBindQuery(&request); err != nil {
+			h.log.Errorf("could not bind query: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not bind query",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		request.ID = c.Param("id")
+
+		form, err := h.service.Get(c, request)
+		if err != nil {
+			h.log.Errorf("could not get form: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get form",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    form,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// GetAll godoc
+// @Security ApiKeyAuth
+// @Summary Returns all forms
+// @Description Returns forms filtered by title
+// @Accept  json
+// @Produce  json
+// @Param filter query models.GetAllFormRequest false "Filter params"
+// @Success 200 {object} models.GetAllFormResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags form
+// @Router /v1/form [get]
+func (h *Handler) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.GetAllFormRequest
+
+		if err := c.ShouldBindQuery(&request); err != nil {
+			h.log.Errorf("could not bind query: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not bind query",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		forms, err := h.service.GetAll(c, request)
+		if err != nil {
+			h.log.Errorf("could not get forms: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get forms",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    forms,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// GetVersions godoc
+// @Security ApiKeyAuth
+// @Summary Returns form versions
+// @Description Returns saved versions of form, latest first
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Form id"
+// @Success 200 {object} models.GetAllFormVersionsResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags form
+// @Router /v1/form/{id}/versions [get]
+func (h *Handler) GetVersions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		versions, err := h.service.GetVersions(c, c.Param("id"))
+		if err != nil {
+			h.log.Errorf("could not get form versions: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get form versions",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    versions,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// CreateGroup godoc
+// @Security ApiKeyAuth
+// @Summary Creates new form group
+// @Description Returns created form group with its fields
+// @Accept  json
+// @Produce  json
+// @Param createFormGroup body models.CreateFormGroupRequest true "Create form group request"
+// @Success 201 {object} models.GetFormGroupResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags form
+// @Router /v1/form/group [post]
+func (h *Handler) CreateGroup() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateFormGroupRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		resp, err := h.service.CreateGroup(c, request)
+		if err != nil {
+			h.log.Errorf("could not create form group: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not create form group",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusCreated,
+		})
+	}
+}
+
+// UpdateGroup godoc
+// @Security ApiKeyAuth
+// @Summary Updates form group
+// @Description Replaces fields of group, fields without id are created, missing fields are deleted. Creates new version of forms using the group
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Form group id"
+// @Param updateFormGroup body models.CreateFormGroupRequest true "Update form group request"
+// @Success 200 {object} models.GetFormGroupResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags form
+// @Router /v1/form/group/{id} [put]
+func (h *Handler) UpdateGroup() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateFormGroupRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		request.ID = c.Param("id")
+
+		resp, err := h.service.UpdateGroup(c, request)
+		if err != nil {
+			h.log.Errorf("could not update form group: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not update form group",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// GetGroup godoc
+// @Security ApiKeyAuth
+// @Summary Gets form group
+// @Description Returns form group with its field tree
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Form group id"
+// @Success 200 {object} models.GetFormGroupResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags form
+// @Router /v1/form/group/{id} [get]
+func (h *Handler) GetGroup() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		group, err := h.service.GetGroup(c, c.Param("id"))
+		if err != nil {
+			h.log.Errorf("could not get form group: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get form group",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    group,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// SetFieldValues godoc
+// @Security ApiKeyAuth
+// @Summary Sets values of form fields for driver step
+// @Description Validates every value against rules of its field and saves them, errors are returned per field id
+// @Accept  json
+// @Produce  json
+// @Param setFieldValues body models.SetFormFieldValue true "Set form field values request"
+// @Success 200 {object} models.SuccessResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags form
+// @Router /v1/form/value [post]
+func (h *Handler) SetFieldValues() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.SetFormFieldValue
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		user, _ := c.Get("user")
+
+		request.CreatedBy = (user.(models.GetUserResponse)).ID
+
+		resp, err := h.service.SetFieldValues(c, request)
+		if err != nil {
+			h.log.Errorf("could not set field values: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not set field values",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// GetFieldValueHistory godoc
+// @Security ApiKeyAuth
+// @Summary Returns value history of driver form field
+// @Description Returns paged revisions of field values with author and time, latest first
+// @Accept  json
+// @Produce  json
+// @Param filter query models.GetFormFieldValueHistoryRequest true "Filter params"
+// @Success 200 {object} models.GetFormFieldValueHistoryResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags form
+// @Router /v1/form/value/history [get]
+func (h *Handler) GetFieldValueHistory() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.GetFormFieldValueHistoryRequest
+
+		if err := c.ShouldBindQuery(&request); err != nil {
+			h.log.Errorf("could not bind query: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not bind query",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		user, _ := c.Get("user")
+
+		request.UserID = (user.(models.GetUserResponse)).ID
+
+		history, err := h.service.GetFieldValueHistory(c, request)
+		if err != nil {
+			h.log.Errorf("could not get field value history: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get field value history",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    history,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// RevertFieldValue godoc
+// @Security ApiKeyAuth
+// @Summary Reverts driver form field to earlier value
+// @Description Creates new revision with values of given revision
+// @Accept  json
+// @Produce  json
+// @Param revertFieldValue body models.RevertFormFieldValueRequest true "Revert form field value request"
+// @Success 200 {object} models.SuccessResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags form
+// @Router /v1/form/value/revert [post]
+func (h *Handler) RevertFieldValue() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.RevertFormFieldValueRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		user, _ := c.Get("user")
+
+		request.CreatedBy = (user.(models.GetUserResponse)).ID
+
+		resp, err := h.service.RevertFieldValue(c, request)
+		if err != nil {
+			h.log.Errorf("could not revert field value: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not revert field value",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
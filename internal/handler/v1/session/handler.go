@@ -0,0 +1,220 @@
+package session
+
+import (
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"github.com/abdivasiyev/project_template/config"
+	serviceV1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/response"
+
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+var Module = fx.Provide(NewHandler)
+
+type Handler struct {
+	environment string
+	log         logger.Logger
+	service     serviceV1.SessionServiceV1
+}
+
+type Params struct {
+	fx.In
+	Config  config.Config
+	Log     logger.Logger
+	Service serviceV1.SessionServiceV1
+}
+
+func NewHandler(params Params) *Handler {
+	return &Handler{
+		environment: params.Config.GetString(config.EnvironmentKey),
+		log:         params.Log,
+		service:     params.Service,
+	}
+}
+
+// GetOwn godoc
+// @Security ApiKeyAuth
+// @Summary Returns sessions of current user
+// @Description Returns active sessions of current user ordered by last activity
+// @Accept  json
+// @Produce  json
+// @Param filter query models.PageRequest true "Pagination params"
+// @Success 200 {object} models.GetAllSessionsResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags session
+// @Router /v1/session/me [get]
+func (h *Handler) GetOwn() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.GetAllSessionsRequest
+
+		if err := c.ShouldBindQuery(&request.PageRequest); err != nil {
+			h.log.Errorf("could not bind query: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not bind query",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		user, _ := c.Get("user")
+
+		request.UserID = (user.(models.GetUserResponse)).ID
+
+		sessions, err := h.service.GetAll(c, request)
+		if err != nil {
+			h.log.Errorf("could not get sessions: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get sessions",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    sessions,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// RevokeOwn godoc
+// @Security ApiKeyAuth
+// @Summary Revokes session of current user
+// @Description Revokes all tokens of session
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Session id"
+// @Success 204 {object} models.SuccessResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags session
+// @Router /v1/session/me/{id} [delete]
+func (h *Handler) RevokeOwn() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.RevokeSessionRequest
+
+		if err := c.ShouldBindUri(&request); err != nil {
+			h.log.Errorf("could not bind uri: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not bind uri",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		user, _ := c.Get("user")
+
+		request.UserID = (user.(models.GetUserResponse)).ID
+
+		if err := h.service.Revoke(c, request); err != nil {
+			h.log.Errorf("could not revoke session: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not revoke session",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj: models.SuccessResponse{
+				Ok: true,
+			},
+			StatusCode: http.StatusNoContent,
+		})
+	}
+}
+
+// GetAll godoc
+// @Security ApiKeyAuth
+// @Summary Returns sessions of users
+// @Description Returns active sessions of company users, can be filtered by user
+// @Accept  json
+// @Produce  json
+// @Param filter query models.GetAllSessionsRequest true "Filter params"
+// @Success 200 {object} models.GetAllSessionsResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags session
+// @Router /v1/session [get]
+func (h *Handler) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.GetAllSessionsRequest
+
+		if err := c.ShouldBindQuery(&request); err != nil {
+			h.log.Errorf("could not bind query: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not bind query",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		sessions, err := h.service.GetAll(c, request)
+		if err != nil {
+			h.log.Errorf("could not get sessions: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get sessions",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    sessions,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// Revoke godoc
+// @Security ApiKeyAuth
+// @Summary Revokes session of any user
+// @Description Revokes all tokens of session
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Session id"
+// @Success 204 {object} models.SuccessResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags session
+// @Router /v1/session/{id} [delete]
+func (h *Handler) Revoke() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.RevokeSessionRequest
+
+		if err := c.ShouldBindUri(&request); err != nil {
+			h.log.Errorf("could not bind uri: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not bind uri",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		if err := h.service.Revoke(c, request); err != nil {
+			h.log.Errorf("could not revoke session: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not revoke session",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj: models.SuccessResponse{
+				Ok: true,
+			},
+			StatusCode: http.StatusNoContent,
+		})
+	}
+}
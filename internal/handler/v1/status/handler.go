@@ -0,0 +1,276 @@
+package status
+
+import (
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"github.com/abdivasiyev/project_template/config"
+	serviceV1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/response"
+
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+var Module = fx.Provide(NewHandler)
+
+type Handler struct {
+	environment string
+	log         logger.Logger
+	service     serviceV1.StatusServiceV1
+}
+
+type Params struct {
+	fx.In
+	Config  config.Config
+	Log     logger.Logger
+	Service serviceV1.StatusServiceV1
+}
+
+func NewHandler(params Params) *Handler {
+	return &Handler{
+		environment: params.Config.GetString(config.EnvironmentKey),
+		log:         params.Log,
+		service:     params.Service,
+	}
+}
+
+// Create godoc
+// @Security ApiKeyAuth
+// @Summary Creates new status
+// @Description Inserts status into requested position among statuses of entity type and returns it
+// @Accept  json
+// @Produce  json
+// @Param createStatus body models.CreateStatusRequest true "Create status request"
+// @Success 201 {object} models.GetStatusResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags status
+// @Router /v1/status [post]
+func (h *Handler) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateStatusRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		resp, err := h.service.Create(c, request)
+		if err != nil {
+			h.log.Errorf("could not create status: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not create status",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusCreated,
+		})
+	}
+}
+
+// Update godoc
+// @Security ApiKeyAuth
+// @Summary Updates status
+// @Description Updates status, moves it into requested position and returns it
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Status id"
+// @Param updateStatus body models.UpdateStatusRequest true "Update status request"
+// @Success 200 {object} models.GetStatusResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags status
+// @Router /v1/status/{id} [put]
+func (h *Handler) Update() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.UpdateStatusRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		request.ID = c.Param("id")
+
+		resp, err := h.service.Update(c, request)
+		if err != nil {
+			h.log.Errorf("could not update status: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not update status",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// Delete godoc
+// @Security ApiKeyAuth
+// @Summary Deletes status
+// @Description Deletes requested status and closes the gap in sequences, system statuses and statuses in use can not be deleted
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Status id"
+// @Success 204 {object} models.SuccessResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags status
+// @Router /v1/status/{id} [delete]
+func (h *Handler) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := h.service.Delete(c, c.Param("id")); err != nil {
+			h.log.Errorf("could not delete status: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not delete status",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj: models.SuccessResponse{
+				Ok: true,
+			},
+			StatusCode: http.StatusNoContent,
+		})
+	}
+}
+
+// Reorder godoc
+// @Security ApiKeyAuth
+// @Summary Reorders statuses
+// @Description Puts given statuses first in requested order, renumbers all statuses without gaps
+// @Accept  json
+// @Produce  json
+// @Param reorderStatuses body models.ReorderStatusesRequest true "Reorder statuses request"
+// @Success 200 {object} models.GetAllStatusesResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags status
+// @Router /v1/status/reorder [put]
+func (h *Handler) Reorder() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.ReorderStatusesRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		resp, err := h.service.Reorder(c, request)
+		if err != nil {
+			h.log.Errorf("could not reorder statuses: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not reorder statuses",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// Get godoc
+// @Security ApiKeyAuth
+// @Summary Gets status
+// @Description Returns status
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Status id"
+// @Success 200 {object} models.GetStatusResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags status
+// @Router /v1/status/{id} [get]
+func (h *Handler) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status, err := h.service.Get(c, c.Param("id"))
+		if err != nil {
+			h.log.Errorf("could not get status: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get status",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    status,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// GetAll godoc
+// @Security ApiKeyAuth
+// @Summary Returns statuses
+// @Description Returns statuses of entity type ordered by sequence
+// @Accept  json
+// @Produce  json
+// @Param filter query models.GetAllStatusesRequest true "Filter params"
+// @Success 200 {object} models.GetAllStatusesResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags status
+// @Router /v1/status [get]
+func (h *Handler) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.GetAllStatusesRequest
+
+		if err := c.ShouldBindQuery(&request); err != nil {
+			h.log.Errorf("could not bind query: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not bind query",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		statuses, err := h.service.GetAll(c, request)
+		if err != nil {
+			h.log.Errorf("could not get statuses: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get statuses",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    statuses,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
@@ -0,0 +1,556 @@
+package step
+
+import (
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"github.com/abdivasiyev/project_template/config"
+	serviceV1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/response"
+
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+var Module = fx.Provide(NewHandler)
+
+type Handler struct {
+	environment string
+	log         logger.Logger
+	service     serviceV1.StepServiceV1
+}
+
+type Params struct {
+	fx.In
+	Config  config.Config
+	Log     logger.Logger
+	Service serviceV1.StepServiceV1
+}
+
+func NewHandler(params Params) *Handler {
+	return &Handler{
+		environment: params.Config.GetString(config.EnvironmentKey),
+		log:         params.Log,
+		service:     params.Service,
+	}
+}
+
+// Create godoc
+// @Security ApiKeyAuth
+// @Summary Creates new step
+// @Description Returns created step
+// @Accept  json
+// @Produce  json
+// @Param createStep body models.CreateStepRequest true "Create step request"
+// @Success 201 {object} models.GetStepResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags step
+// @Router /v1/step [post]
+func (h *Handler) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateStepRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		user, _ := c.Get("user")
+
+		request.UserID = (user.(models.GetUserResponse)).ID
+
+		resp, err := h.service.Create(c, request)
+		if err != nil {
+			h.log.Errorf("could not create step: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not create step",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusCreated,
+		})
+	}
+}
+
+// Update godoc
+// @Security ApiKeyAuth
+// @Summary Updates step
+// @Description Returns updated step
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Step id"
+// @Param updateStep body models.CreateStepRequest true "Update step request"
+// @Success 200 {object} models.GetStepResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags step
+// @Router /v1/step/{id} [put]
+func (h *Handler) Update() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateStepRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		user, _ := c.Get("user")
+
+		request.ID = c.Param("id")
+		request.UserID = (user.(models.GetUserResponse)).ID
+
+		resp, err := h.service.Update(c, request)
+		if err != nil {
+			h.log.Errorf("could not update step: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not update step",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// Delete godoc
+// @Security ApiKeyAuth
+// @Summary Deletes step
+// @Description Deletes requested step
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Step id"
+// @Success 204 {object} models.SuccessResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags step
+// @Router /v1/step/{id} [delete]
+func (h *Handler) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, _ := c.Get("user")
+
+		if err := h.service.Delete(c, c.Param("id"), (user.(models.GetUserResponse)).ID); err != nil {
+			h.log.Errorf("could not delete step: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not delete step",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj: models.SuccessResponse{
+				Ok: true,
+			},
+			StatusCode: http.StatusNoContent,
+		})
+	}
+}
+
+// Get godoc
+// @Security ApiKeyAuth
+// @Summary Gets step
+// @Description Returns step definition
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Step id"
+// @Success 200 {object} models.GetStepResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags step
+// @Router /v1/step/{id} [get]
+func (h *Handler) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, _ := c.Get("user")
+
+		step, err := h.service.Get(c, c.Param("id"), (user.(models.GetUserResponse)).ID)
+		if err != nil {
+			h.log.Errorf("could not get step: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get step",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    step,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// GetAll godoc
+// @Security ApiKeyAuth
+// @Summary Returns steps of department
+// @Description Returns step definitions of department ordered by sequence
+// @Accept  json
+// @Produce  json
+// @Param department_id path string true "Department id"
+// @Success 200 {object} models.GetAllStepsResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags step
+// @Router /v1/step/department/{department_id} [get]
+func (h *Handler) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, _ := c.Get("user")
+
+		steps, err := h.service.GetAll(c, c.Param("department_id"), (user.(models.GetUserResponse)).ID)
+		if err != nil {
+			h.log.Errorf("could not get steps: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get steps",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    steps,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// GetAllGroupByStatus godoc
+// @Security ApiKeyAuth
+// @Summary Returns driver steps grouped by status
+// @Description Returns kanban view of department steps of driver with current field values
+// @Accept  json
+// @Produce  json
+// @Param department_id path string true "Department id"
+// @Param driver_id path string true "Driver id"
+// @Success 200 {object} models.GetAllStepsGroupByStatusResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags step
+// @Router /v1/step/department/{department_id}/driver/{driver_id} [get]
+func (h *Handler) GetAllGroupByStatus() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.GetAllStepsRequest
+
+		if err := c.ShouldBindUri(&request); err != nil {
+			h.log.Errorf("could not bind uri: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not bind uri",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		user, _ := c.Get("user")
+
+		request.UserID = (user.(models.GetUserResponse)).ID
+
+		steps, err := h.service.GetAllGroupByStatus(c, request)
+		if err != nil {
+			h.log.Errorf("could not get driver steps: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get driver steps",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    steps,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// UpdateStatus godoc
+// @Security ApiKeyAuth
+// @Summary Updates status of driver step
+// @Description Moves driver step into given status if transition is allowed and recalculates onboarding status of driver
+// @Accept  json
+// @Produce  json
+// @Param updateStepStatus body models.UpdateStepStatusRequest true "Update step status request"
+// @Success 200 {object} models.SuccessResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags step
+// @Router /v1/step/status [put]
+func (h *Handler) UpdateStatus() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.UpdateStepStatusRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		user, _ := c.Get("user")
+
+		request.UpdatedBy = (user.(models.GetUserResponse)).ID
+
+		resp, err := h.service.UpdateStatus(c, request)
+		if err != nil {
+			h.log.Errorf("could not update step status: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not update step status",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// CreateTransition godoc
+// @Security ApiKeyAuth
+// @Summary Creates allowed step status transition
+// @Description Allows moving driver step from one status to another, transition with role is allowed only for users with that role
+// @Accept  json
+// @Produce  json
+// @Param createTransition body models.CreateStatusTransitionRequest true "Create status transition request"
+// @Success 201 {object} models.GetStatusTransitionResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags step
+// @Router /v1/step/transition [post]
+func (h *Handler) CreateTransition() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateStatusTransitionRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		resp, err := h.service.CreateTransition(c, request)
+		if err != nil {
+			h.log.Errorf("could not create status transition: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not create status transition",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusCreated,
+		})
+	}
+}
+
+// DeleteTransition godoc
+// @Security ApiKeyAuth
+// @Summary Deletes step status transition
+// @Description Deletes requested status transition
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Transition id"
+// @Success 204 {object} models.SuccessResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags step
+// @Router /v1/step/transition/{id} [delete]
+func (h *Handler) DeleteTransition() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := h.service.DeleteTransition(c, c.Param("id")); err != nil {
+			h.log.Errorf("could not delete status transition: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not delete status transition",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj: models.SuccessResponse{
+				Ok: true,
+			},
+			StatusCode: http.StatusNoContent,
+		})
+	}
+}
+
+// GetTransitions godoc
+// @Security ApiKeyAuth
+// @Summary Returns step status transitions
+// @Description Returns allowed status transitions of steps
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} models.GetAllStatusTransitionsResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags step
+// @Router /v1/step/transition [get]
+func (h *Handler) GetTransitions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		transitions, err := h.service.GetTransitions(c)
+		if err != nil {
+			h.log.Errorf("could not get status transitions: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get status transitions",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    transitions,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// HasAccess godoc
+// @Security ApiKeyAuth
+// @Summary Returns access of current user to driver step
+// @Description Returns view, edit, create and delete permissions of current user roles for department of step
+// @Accept  json
+// @Produce  json
+// @Param step_id query string true "Step id"
+// @Param driver_id query string true "Driver id"
+// @Success 200 {object} models.HasAccessResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags step
+// @Router /v1/step/access [get]
+func (h *Handler) HasAccess() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.HasAccessRequest
+
+		if err := c.ShouldBindQuery(&request); err != nil {
+			h.log.Errorf("could not bind query: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not bind query",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		user, _ := c.Get("user")
+
+		request.UserID = (user.(models.GetUserResponse)).ID
+
+		access, err := h.service.HasAccess(c, request)
+		if err != nil {
+			h.log.Errorf("could not get step access: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get step access",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    access,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// SetDepartmentAccess godoc
+// @Security ApiKeyAuth
+// @Summary Sets access of role to department steps
+// @Description Creates or replaces view, edit, create and delete permissions of role for steps of department
+// @Accept  json
+// @Produce  json
+// @Param setDepartmentAccess body models.SetDepartmentAccessRequest true "Set department access request"
+// @Success 200 {object} models.GetAllDepartmentAccessResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags step
+// @Router /v1/step/access/department [put]
+func (h *Handler) SetDepartmentAccess() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.SetDepartmentAccessRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		resp, err := h.service.SetDepartmentAccess(c, request)
+		if err != nil {
+			h.log.Errorf("could not set department access: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not set department access",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// GetDepartmentAccesses godoc
+// @Security ApiKeyAuth
+// @Summary Returns role accesses of department
+// @Description Returns view, edit, create and delete permissions of roles for steps of department
+// @Accept  json
+// @Produce  json
+// @Param department_id path string true "Department id"
+// @Success 200 {object} models.GetAllDepartmentAccessResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags step
+// @Router /v1/step/access/department/{department_id} [get]
+func (h *Handler) GetDepartmentAccesses() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accesses, err := h.service.GetDepartmentAccesses(c, c.Param("department_id"))
+		if err != nil {
+			h.log.Errorf("could not get department accesses: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get department accesses",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    accesses,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
@@ -0,0 +1,361 @@
+package trailer
+
+import (
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"github.com/abdivasiyev/project_template/config"
+	serviceV1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/response"
+
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+var Module = fx.Provide(NewHandler)
+
+type Handler struct {
+	environment string
+	log         logger.Logger
+	service     serviceV1.TrailerServiceV1
+}
+
+type Params struct {
+	fx.In
+	Config  config.Config
+	Log     logger.Logger
+	Service serviceV1.TrailerServiceV1
+}
+
+func NewHandler(params Params) *Handler {
+	return &Handler{
+		environment: params.Config.GetString(config.EnvironmentKey),
+		log:         params.Log,
+		service:     params.Service,
+	}
+}
+
+// Create godoc
+// @Security ApiKeyAuth
+// @Summary Creates new trailer
+// @Description Returns created trailer
+// @Accept  json
+// @Produce  json
+// @Param createTrailer body models.CreateTrailerRequest true "Create trailer request"
+// @Success 201 {object} models.GetTrailerResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags trailer
+// @Router /v1/trailer [post]
+func (h *Handler) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateTrailerRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		resp, err := h.service.Create(c, request)
+		if err != nil {
+			h.log.Errorf("could not create trailer: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not create trailer",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusCreated,
+		})
+	}
+}
+
+// Update godoc
+// @Security ApiKeyAuth
+// @Summary Updates trailer
+// @Description Returns updated trailer
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Trailer id"
+// @Param updateTrailer body models.CreateTrailerRequest true "Update trailer request"
+// @Success 200 {object} models.GetTrailerResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags trailer
+// @Router /v1/trailer/{id} [put]
+func (h *Handler) Update() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateTrailerRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		request.ID = c.Param("id")
+
+		resp, err := h.service.Update(c, request)
+		if err != nil {
+			h.log.Errorf("could not update trailer: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not update trailer",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// Delete godoc
+// @Security ApiKeyAuth
+// @Summary Deletes trailer
+// @Description Deletes requested trailer
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Trailer id"
+// @Success 204 {object} models.SuccessResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags trailer
+// @Router /v1/trailer/{id} [delete]
+func (h *Handler) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := h.service.Delete(c, c.Param("id")); err != nil {
+			h.log.Errorf("could not delete trailer: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not delete trailer",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj: models.SuccessResponse{
+				Ok: true,
+			},
+			StatusCode: http.StatusNoContent,
+		})
+	}
+}
+
+// Get godoc
+// @Security ApiKeyAuth
+// @Summary Gets trailer
+// @Description Returns trailer with its status and assigned driver
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Trailer id"
+// @Success 200 {object} models.GetTrailerResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags trailer
+// @Router /v1/trailer/{id} [get]
+func (h *Handler) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		trailer, err := h.service.Get(c, c.Param("id"))
+		if err != nil {
+			h.log.Errorf("could not get trailer: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get trailer",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    trailer,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// GetAll godoc
+// @Security ApiKeyAuth
+// @Summary Returns all trailers
+// @Description Returns trailers filtered by search and status
+// @Accept  json
+// @Produce  json
+// @Param filter query models.GetAllTrailersRequest false "Filter params"
+// @Success 200 {object} models.GetAllTrailersResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags trailer
+// @Router /v1/trailer [get]
+func (h *Handler) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.GetAllTrailersRequest
+
+		if err := c.ShouldBindQuery(&request); err != nil {
+			h.log.Errorf("could not bind query: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not bind query",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		trailers, err := h.service.GetAll(c, request)
+		if err != nil {
+			h.log.Errorf("could not get trailers: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get trailers",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    trailers,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// CreateInspection godoc
+// @Security ApiKeyAuth
+// @Summary Records trailer pickup or drop-off inspection
+// @Description Returns created inspection
+// @Accept  json
+// @Produce  json
+// @Param createInspection body models.CreateTrailerInspectionRequest true "Create trailer inspection request"
+// @Success 201 {object} models.GetTrailerInspectionResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags trailer
+// @Router /v1/trailer/inspection [post]
+func (h *Handler) CreateInspection() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateTrailerInspectionRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		user, _ := c.Get("user")
+
+		request.CreatedBy = (user.(models.GetUserResponse)).ID
+
+		resp, err := h.service.CreateInspection(c, request)
+		if err != nil {
+			h.log.Errorf("could not create trailer inspection: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not create trailer inspection",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusCreated,
+		})
+	}
+}
+
+// GetLatestInspection godoc
+// @Security ApiKeyAuth
+// @Summary Gets latest trailer inspection
+// @Description Returns the most recent pickup or drop-off inspection of trailer
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Trailer id"
+// @Success 200 {object} models.GetTrailerInspectionResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags trailer
+// @Router /v1/trailer/{id}/inspection [get]
+func (h *Handler) GetLatestInspection() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		request := models.GetTrailerInspectionRequest{
+			TrailerID: c.Param("id"),
+		}
+
+		inspection, err := h.service.GetLatestInspection(c, request)
+		if err != nil {
+			h.log.Errorf("could not get trailer inspection: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get trailer inspection",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    inspection,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// GetPickUpDropOffHistory godoc
+// @Security ApiKeyAuth
+// @Summary Returns trailer pickup and drop-off history
+// @Description Returns paged inspections of trailer
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Trailer id"
+// @Param filter query models.GetTrailerPickUpDropOffHistoryRequest false "Filter params"
+// @Success 200 {object} models.GetTrailerPickUpDropOffHistoryResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags trailer
+// @Router /v1/trailer/{id}/history [get]
+func (h *Handler) GetPickUpDropOffHistory() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.GetTrailerPickUpDropOffHistoryRequest
+
+		if err := c.ShouldBindQuery(&request); err != nil {
+			h.log.Errorf("could not bind query: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not bind query",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		request.TrailerID = c.Param("id")
+
+		history, err := h.service.GetPickUpDropOffHistory(c, request)
+		if err != nil {
+			h.log.Errorf("could not get trailer history: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get trailer history",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    history,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
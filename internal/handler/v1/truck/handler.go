@@ -0,0 +1,315 @@
+package truck
+
+import (
+	"net/http"
+
+	"go.uber.org/fx"
+
+	"github.com/abdivasiyev/project_template/config"
+	serviceV1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/response"
+
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+var Module = fx.Provide(NewHandler)
+
+type Handler struct {
+	environment string
+	log         logger.Logger
+	service     serviceV1.TruckServiceV1
+}
+
+type Params struct {
+	fx.In
+	Config  config.Config
+	Log     logger.Logger
+	Service serviceV1.TruckServiceV1
+}
+
+func NewHandler(params Params) *Handler {
+	return &Handler{
+		environment: params.Config.GetString(config.EnvironmentKey),
+		log:         params.Log,
+		service:     params.Service,
+	}
+}
+
+// Create godoc
+// @Security ApiKeyAuth
+// @Summary Creates new truck
+// @Description Returns created truck
+// @Accept  json
+// @Produce  json
+// @Param createTruck body models.CreateTruckRequest true "Create truck request"
+// @Success 201 {object} models.GetTruckResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags truck
+// @Router /v1/truck [post]
+func (h *Handler) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateTruckRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		resp, err := h.service.Create(c, request)
+		if err != nil {
+			h.log.Errorf("could not create truck: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not create truck",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusCreated,
+		})
+	}
+}
+
+// Update godoc
+// @Security ApiKeyAuth
+// @Summary Updates truck
+// @Description Returns updated truck
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Truck id"
+// @Param updateTruck body models.CreateTruckRequest true "Update truck request"
+// @Success 200 {object} models.GetTruckResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags truck
+// @Router /v1/truck/{id} [put]
+func (h *Handler) Update() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateTruckRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		request.ID = c.Param("id")
+
+		resp, err := h.service.Update(c, request)
+		if err != nil {
+			h.log.Errorf("could not update truck: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not update truck",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// Delete godoc
+// @Security ApiKeyAuth
+// @Summary Deletes truck
+// @Description Deletes requested truck
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Truck id"
+// @Success 204 {object} models.SuccessResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags truck
+// @Router /v1/truck/{id} [delete]
+func (h *Handler) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := h.service.Delete(c, c.Param("id")); err != nil {
+			h.log.Errorf("could not delete truck: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not delete truck",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj: models.SuccessResponse{
+				Ok: true,
+			},
+			StatusCode: http.StatusNoContent,
+		})
+	}
+}
+
+// Get godoc
+// @Security ApiKeyAuth
+// @Summary Gets truck
+// @Description Returns truck with its status and assigned driver
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Truck id"
+// @Success 200 {object} models.GetTruckResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags truck
+// @Router /v1/truck/{id} [get]
+func (h *Handler) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		truck, err := h.service.Get(c, c.Param("id"))
+		if err != nil {
+			h.log.Errorf("could not get truck: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get truck",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    truck,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// GetAll godoc
+// @Security ApiKeyAuth
+// @Summary Returns all trucks
+// @Description Returns trucks filtered by search and status
+// @Accept  json
+// @Produce  json
+// @Param filter query models.GetAllTrucksRequest false "Filter params"
+// @Success 200 {object} models.GetAllTrucksResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags truck
+// @Router /v1/truck [get]
+func (h *Handler) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.GetAllTrucksRequest
+
+		if err := c.ShouldBindQuery(&request); err != nil {
+			h.log.Errorf("could not bind query: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not bind query",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		trucks, err := h.service.GetAll(c, request)
+		if err != nil {
+			h.log.Errorf("could not get trucks: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get trucks",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    trucks,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
+
+// CreateInspection godoc
+// @Security ApiKeyAuth
+// @Summary Records truck pickup or drop-off inspection
+// @Description Returns created inspection
+// @Accept  json
+// @Produce  json
+// @Param createInspection body models.CreateTruckInspectionRequest true "Create truck inspection request"
+// @Success 201 {object} models.GetTruckInspectionResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags truck
+// @Router /v1/truck/inspection [post]
+func (h *Handler) CreateInspection() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateTruckInspectionRequest
+
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.log.Errorf("could not bind json: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not parse request body",
+				StatusCode: http.StatusBadRequest,
+			})
+			return
+		}
+
+		user, _ := c.Get("user")
+
+		request.CreatedBy = (user.(models.GetUserResponse)).ID
+
+		resp, err := h.service.CreateInspection(c, request)
+		if err != nil {
+			h.log.Errorf("could not create truck inspection: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not create truck inspection",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    resp,
+			StatusCode: http.StatusCreated,
+		})
+	}
+}
+
+// GetLatestInspections godoc
+// @Security ApiKeyAuth
+// @Summary Gets latest truck inspections
+// @Description Returns the most recent pickup and drop-off inspections of truck
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Truck id"
+// @Success 200 {object} models.GetTruckLatestInspectionsResponse
+// @Failure default {object} models.ErrorResponse
+// @Tags truck
+// @Router /v1/truck/{id}/inspection [get]
+func (h *Handler) GetLatestInspections() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		request := models.GetTruckInspectionRequest{
+			TruckID: c.Param("id"),
+		}
+
+		inspections, err := h.service.GetLatestInspections(c, request)
+		if err != nil {
+			h.log.Errorf("could not get truck inspections: %v", err)
+			response.JSON(c, response.Params{
+				Err:        err,
+				Message:    "could not get truck inspections",
+				StatusCode: http.StatusInternalServerError,
+			})
+			return
+		}
+
+		response.JSON(c, response.Params{
+			JsonObj:    inspections,
+			StatusCode: http.StatusOK,
+		})
+	}
+}
@@ -12,11 +12,25 @@ import (
 	"context"
 	"github.com/abdivasiyev/project_template/config"
 	"github.com/abdivasiyev/project_template/internal/handler/v1/app"
+	"github.com/abdivasiyev/project_template/internal/handler/v1/assignment"
 	"github.com/abdivasiyev/project_template/internal/handler/v1/auth"
+	"github.com/abdivasiyev/project_template/internal/handler/v1/car"
+	"github.com/abdivasiyev/project_template/internal/handler/v1/comment"
+	"github.com/abdivasiyev/project_template/internal/handler/v1/company"
+	"github.com/abdivasiyev/project_template/internal/handler/v1/dashboard"
+	"github.com/abdivasiyev/project_template/internal/handler/v1/department"
 	"github.com/abdivasiyev/project_template/internal/handler/v1/doc"
+	"github.com/abdivasiyev/project_template/internal/handler/v1/driver"
+	"github.com/abdivasiyev/project_template/internal/handler/v1/entity"
 	"github.com/abdivasiyev/project_template/internal/handler/v1/file"
+	"github.com/abdivasiyev/project_template/internal/handler/v1/form"
 	"github.com/abdivasiyev/project_template/internal/handler/v1/pprof"
 	"github.com/abdivasiyev/project_template/internal/handler/v1/role"
+	"github.com/abdivasiyev/project_template/internal/handler/v1/session"
+	"github.com/abdivasiyev/project_template/internal/handler/v1/status"
+	"github.com/abdivasiyev/project_template/internal/handler/v1/step"
+	"github.com/abdivasiyev/project_template/internal/handler/v1/trailer"
+	"github.com/abdivasiyev/project_template/internal/handler/v1/truck"
 	"github.com/abdivasiyev/project_template/internal/handler/v1/user"
 	"github.com/abdivasiyev/project_template/internal/middleware"
 	"github.com/abdivasiyev/project_template/pkg/logger"
@@ -44,6 +58,20 @@ type Params struct {
 	Doc        *doc.Handler
 	App        *app.Handler
 	Pprof      *pprof.Handler
+	Truck      *truck.Handler
+	Trailer    *trailer.Handler
+	Car        *car.Handler
+	Driver     *driver.Handler
+	Form       *form.Handler
+	Step       *step.Handler
+	Department *department.Handler
+	Company    *company.Handler
+	Dashboard  *dashboard.Handler
+	Entity     *entity.Handler
+	Assignment *assignment.Handler
+	Status     *status.Handler
+	Comment    *comment.Handler
+	Session    *session.Handler
 }
 
 type Handler struct {
@@ -57,6 +85,20 @@ type Handler struct {
 	doc               *doc.Handler
 	pprof             *pprof.Handler
 	app               *app.Handler
+	truck             *truck.Handler
+	trailer           *trailer.Handler
+	car               *car.Handler
+	driver            *driver.Handler
+	form              *form.Handler
+	step              *step.Handler
+	department        *department.Handler
+	company           *company.Handler
+	dashboard         *dashboard.Handler
+	entity            *entity.Handler
+	assignment        *assignment.Handler
+	status            *status.Handler
+	comment           *comment.Handler
+	session           *session.Handler
 	basicAuthUser     string
 	basicAuthPassword string
 	swaggerPath       string
@@ -77,6 +119,20 @@ func New(params Params) {
 		doc:               params.Doc,
 		swaggerPath:       params.Config.GetString(config.SpecPath),
 		app:               params.App,
+		session:           params.Session,
+		comment:           params.Comment,
+		status:            params.Status,
+		assignment:        params.Assignment,
+		entity:            params.Entity,
+		dashboard:         params.Dashboard,
+		company:           params.Company,
+		department:        params.Department,
+		step:              params.Step,
+		form:              params.Form,
+		driver:            params.Driver,
+		car:               params.Car,
+		trailer:           params.Trailer,
+		truck:             params.Truck,
 	}
 
 	params.Lifecycle.Append(
@@ -100,6 +156,7 @@ func (h *Handler) registerRoutes(router gin.IRouter) {
 	authRequired := apiV1.Group("/")
 	authRequired.Use(
 		h.middleware.BearerAuth(),
+		h.middleware.Tenant(),
 		h.middleware.HasAccess(),
 	)
 
@@ -112,6 +169,20 @@ func (h *Handler) registerRoutes(router gin.IRouter) {
 	h.registerUser(authRequired)
 	h.registerRole(authRequired)
 	h.registerFile(authRequired)
+	h.registerTruck(authRequired)
+	h.registerTrailer(authRequired)
+	h.registerCar(authRequired)
+	h.registerDriver(authRequired)
+	h.registerForm(authRequired)
+	h.registerStep(authRequired)
+	h.registerDepartment(authRequired)
+	h.registerCompany(authRequired)
+	h.registerDashboard(authRequired)
+	h.registerEntity(authRequired)
+	h.registerAssignment(authRequired)
+	h.registerStatus(authRequired)
+	h.registerComment(authRequired)
+	h.registerSession(apiV1)
 	h.registerPprof(apiV1)
 }
 
@@ -152,6 +223,165 @@ func (h *Handler) registerFile(group gin.IRouter) {
 	{
 		routerGroup.POST("/", h.file.Upload())
 		routerGroup.GET("/:id", h.file.Get())
+		routerGroup.POST("/zip", h.file.GenerateZip())
+		routerGroup.GET("/zip/:request_id", h.file.GetZip())
+	}
+}
+
+func (h *Handler) registerTruck(group gin.IRouter) {
+	routerGroup := group.Group("/truck")
+	{
+		routerGroup.POST("/", h.truck.Create())
+		routerGroup.PUT("/:id", h.truck.Update())
+		routerGroup.DELETE("/:id", h.truck.Delete())
+		routerGroup.GET("/", h.truck.GetAll())
+		routerGroup.GET("/:id", h.truck.Get())
+		routerGroup.POST("/inspection", h.truck.CreateInspection())
+		routerGroup.GET("/:id/inspection", h.truck.GetLatestInspections())
+	}
+}
+
+func (h *Handler) registerTrailer(group gin.IRouter) {
+	routerGroup := group.Group("/trailer")
+	{
+		routerGroup.POST("/", h.trailer.Create())
+		routerGroup.PUT("/:id", h.trailer.Update())
+		routerGroup.DELETE("/:id", h.trailer.Delete())
+		routerGroup.GET("/", h.trailer.GetAll())
+		routerGroup.GET("/:id", h.trailer.Get())
+		routerGroup.POST("/inspection", h.trailer.CreateInspection())
+		routerGroup.GET("/:id/inspection", h.trailer.GetLatestInspection())
+		routerGroup.GET("/:id/history", h.trailer.GetPickUpDropOffHistory())
+	}
+}
+
+func (h *Handler) registerCar(group gin.IRouter) {
+	routerGroup := group.Group("/car")
+	{
+		routerGroup.POST("/", h.car.Create())
+		routerGroup.PUT("/:id", h.car.Update())
+		routerGroup.DELETE("/:id", h.car.Delete())
+		routerGroup.GET("/", h.car.GetAll())
+		routerGroup.GET("/:id", h.car.Get())
+		routerGroup.POST("/:id/handover", h.car.CreatePickupDropOff())
+		routerGroup.GET("/:id/history", h.car.GetPickupDropOffHistory())
+	}
+}
+
+func (h *Handler) registerDriver(group gin.IRouter) {
+	routerGroup := group.Group("/driver")
+	{
+		routerGroup.POST("/", h.driver.Create())
+		routerGroup.PUT("/:id", h.driver.Update())
+		routerGroup.GET("/", h.driver.GetAll())
+		routerGroup.GET("/:id", h.driver.Get())
+	}
+}
+
+func (h *Handler) registerForm(group gin.IRouter) {
+	routerGroup := group.Group("/form")
+	{
+		routerGroup.POST("/", h.form.Create())
+		routerGroup.PUT("/:id", h.form.Update())
+		routerGroup.DELETE("/:id", h.form.Delete())
+		routerGroup.GET("/", h.form.GetAll())
+		routerGroup.GET("/:id", h.form.Get())
+		routerGroup.GET("/:id/versions", h.form.GetVersions())
+		routerGroup.POST("/group", h.form.CreateGroup())
+		routerGroup.PUT("/group/:id", h.form.UpdateGroup())
+		routerGroup.GET("/group/:id", h.form.GetGroup())
+		routerGroup.POST("/value", h.form.SetFieldValues())
+		routerGroup.GET("/value/history", h.form.GetFieldValueHistory())
+		routerGroup.POST("/value/revert", h.form.RevertFieldValue())
+	}
+}
+
+func (h *Handler) registerStep(group gin.IRouter) {
+	routerGroup := group.Group("/step")
+	{
+		routerGroup.POST("/", h.step.Create())
+		routerGroup.PUT("/:id", h.step.Update())
+		routerGroup.DELETE("/:id", h.step.Delete())
+		routerGroup.GET("/:id", h.step.Get())
+		routerGroup.GET("/department/:department_id", h.step.GetAll())
+		routerGroup.GET("/department/:department_id/driver/:driver_id", h.step.GetAllGroupByStatus())
+		routerGroup.PUT("/status", h.step.UpdateStatus())
+		routerGroup.GET("/transition", h.step.GetTransitions())
+		routerGroup.POST("/transition", h.step.CreateTransition())
+		routerGroup.DELETE("/transition/:id", h.step.DeleteTransition())
+		routerGroup.GET("/access", h.step.HasAccess())
+		routerGroup.PUT("/access/department", h.step.SetDepartmentAccess())
+		routerGroup.GET("/access/department/:department_id", h.step.GetDepartmentAccesses())
+	}
+}
+
+func (h *Handler) registerDepartment(group gin.IRouter) {
+	routerGroup := group.Group("/department")
+	{
+		routerGroup.POST("/", h.department.Create())
+		routerGroup.PUT("/reorder", h.department.Reorder())
+		routerGroup.PUT("/:id", h.department.Update())
+		routerGroup.DELETE("/:id", h.department.Delete())
+		routerGroup.GET("/", h.department.GetAll())
+		routerGroup.GET("/:id", h.department.Get())
+	}
+}
+
+func (h *Handler) registerCompany(group gin.IRouter) {
+	routerGroup := group.Group("/company")
+	{
+		routerGroup.POST("/", h.company.Create())
+		routerGroup.PUT("/:id", h.company.Update())
+		routerGroup.DELETE("/:id", h.company.Delete())
+		routerGroup.GET("/", h.company.GetAll())
+		routerGroup.GET("/:id", h.company.Get())
+	}
+}
+
+func (h *Handler) registerDashboard(group gin.IRouter) {
+	routerGroup := group.Group("/dashboard")
+	{
+		routerGroup.GET("/", h.dashboard.Get())
+	}
+}
+
+func (h *Handler) registerEntity(group gin.IRouter) {
+	routerGroup := group.Group("/entity")
+	{
+		routerGroup.POST("/", h.entity.Create())
+		routerGroup.GET("/", h.entity.GetAll())
+	}
+}
+
+func (h *Handler) registerAssignment(group gin.IRouter) {
+	routerGroup := group.Group("/assignment")
+	{
+		routerGroup.POST("/", h.assignment.Assign())
+		routerGroup.DELETE("/:entity_type/:entity_id", h.assignment.Unassign())
+		routerGroup.GET("/", h.assignment.GetHistory())
+	}
+}
+
+func (h *Handler) registerStatus(group gin.IRouter) {
+	routerGroup := group.Group("/status")
+	{
+		routerGroup.POST("/", h.status.Create())
+		routerGroup.PUT("/reorder", h.status.Reorder())
+		routerGroup.PUT("/:id", h.status.Update())
+		routerGroup.DELETE("/:id", h.status.Delete())
+		routerGroup.GET("/:id", h.status.Get())
+		routerGroup.GET("/", h.status.GetAll())
+	}
+}
+
+func (h *Handler) registerComment(group gin.IRouter) {
+	routerGroup := group.Group("/comment")
+	{
+		routerGroup.POST("/", h.comment.Create())
+		routerGroup.PUT("/:id", h.comment.Update())
+		routerGroup.DELETE("/:id", h.comment.Delete())
+		routerGroup.GET("/:id", h.comment.Get())
+		routerGroup.GET("/", h.comment.GetAll())
 	}
 }
 
@@ -168,8 +398,27 @@ func (h *Handler) registerAuth(group gin.IRouter) {
 	routerGroup := group.Group("/auth")
 	{
 		routerGroup.POST("/login", h.auth.Login())
+		routerGroup.POST("/login/2fa", h.auth.VerifyTwoFactor())
 		routerGroup.POST("/refresh", h.auth.Refresh())
 		routerGroup.POST("/reset-password", h.auth.ResetPassword())
+		routerGroup.POST("/logout", h.middleware.BearerAuth(), h.middleware.Tenant(), h.auth.Logout())
+		routerGroup.POST("/logout/all", h.middleware.BearerAuth(), h.middleware.Tenant(), h.auth.LogoutAll())
+		routerGroup.POST("/2fa/setup", h.middleware.BearerAuth(), h.middleware.Tenant(), h.auth.SetupTwoFactor())
+		routerGroup.POST("/2fa/confirm", h.middleware.BearerAuth(), h.middleware.Tenant(), h.auth.ConfirmTwoFactor())
+		routerGroup.POST("/2fa/disable", h.middleware.BearerAuth(), h.middleware.Tenant(), h.auth.DisableTwoFactor())
+		routerGroup.POST("/unlock", h.middleware.BearerAuth(), h.middleware.Tenant(), h.middleware.HasAccess(), h.auth.Unlock())
+	}
+}
+
+// registerSession allows any authenticated user to manage own sessions,
+// sessions of other users are managed with access permissions
+func (h *Handler) registerSession(group gin.IRouter) {
+	routerGroup := group.Group("/session", h.middleware.BearerAuth(), h.middleware.Tenant())
+	{
+		routerGroup.GET("/me", h.session.GetOwn())
+		routerGroup.DELETE("/me/:id", h.session.RevokeOwn())
+		routerGroup.GET("/", h.middleware.HasAccess(), h.session.GetAll())
+		routerGroup.DELETE("/:id", h.middleware.HasAccess(), h.session.Revoke())
 	}
 }
 
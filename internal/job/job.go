@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/abdivasiyev/project_template/config"
 	v1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/helpers"
 	"github.com/abdivasiyev/project_template/pkg/logger"
 	"github.com/abdivasiyev/project_template/pkg/sentry"
 	"go.uber.org/fx"
@@ -55,7 +56,7 @@ func New(params Params) Provider {
 
 	params.Lifecycle.Append(fx.Hook{
 		OnStart: func(_ context.Context) error {
-			go provider.start(context.Background())
+			go provider.start(helpers.WithSystemTenant(context.Background()))
 
 			provider.registerJobs()
 
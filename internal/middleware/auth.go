@@ -77,3 +77,41 @@ func (m *middleware) BearerAuth() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// Tenant limits request to company of authenticated user,
+// admins can switch to another company with X-Company-ID header
+func (m *middleware) Tenant() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := c.Get("user")
+
+		if !ok {
+			response.JSON(c, response.Params{
+				Err:        models.ErrUnauthorized,
+				StatusCode: http.StatusUnauthorized,
+				Message:    "unauthorized",
+			})
+			return
+		}
+
+		tenant, err := m.service.GetTenant(c, user.(models.GetUserResponse), c.GetHeader("X-Company-ID"))
+		if err != nil {
+			if errors.Is(err, models.ErrForbidden) {
+				response.JSON(c, response.Params{
+					Err:        models.ErrForbidden,
+					StatusCode: http.StatusForbidden,
+					Message:    "forbidden operation",
+				})
+				return
+			}
+			response.JSON(c, response.Params{
+				Err:        err,
+				StatusCode: http.StatusInternalServerError,
+				Message:    "internal error",
+			})
+			return
+		}
+
+		c.Set(models.TenantKey, tenant)
+		c.Next()
+	}
+}
@@ -13,6 +13,7 @@ var Module = fx.Provide(New)
 type Handler interface {
 	HasAccess() gin.HandlerFunc
 	BearerAuth() gin.HandlerFunc
+	Tenant() gin.HandlerFunc
 	Log(timeFormat string, utc bool) gin.HandlerFunc
 	LogWithConfig(conf *Config) gin.HandlerFunc
 	RecoverWithLog(stack bool) gin.HandlerFunc
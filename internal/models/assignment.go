@@ -0,0 +1,41 @@
+package models
+
+type AssignDriverRequest struct {
+	ID         string `json:"id" swaggerignore:"true"`
+	DriverID   string `json:"driver_id" binding:"required,uuid4"`
+	EntityType string `json:"entity_type" binding:"required,oneof=truck trailer" example:"truck"`
+	EntityID   string `json:"entity_id" binding:"required,uuid4"`
+	AssignedBy string `json:"assigned_by" swaggerignore:"true"`
+}
+
+type UnassignDriverRequest struct {
+	EntityType string `json:"entity_type" uri:"entity_type" binding:"required,oneof=truck trailer"`
+	EntityID   string `json:"entity_id" uri:"entity_id" binding:"required,uuid4"`
+}
+
+type GetAssignmentHistoryRequest struct {
+	PageRequest
+	DriverID   string `json:"driver_id" form:"driver_id" binding:"omitempty,uuid4"`
+	EntityType string `json:"entity_type" form:"entity_type" binding:"omitempty,oneof=truck trailer"`
+	EntityID   string `json:"entity_id" form:"entity_id" binding:"omitempty,uuid4"`
+	Active     bool   `json:"active" form:"active"`
+}
+
+type GetAssignmentResponse struct {
+	ID     string `json:"id"`
+	Driver struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"driver"`
+	EntityType   string                   `json:"entity_type" example:"truck"`
+	Truck        *AssignedTruckResponse   `json:"truck,omitempty"`
+	Trailer      *AssignedTrailerResponse `json:"trailer,omitempty"`
+	AssignedBy   string                   `json:"assigned_by"`
+	AssignedAt   string                   `json:"assigned_at"`
+	UnassignedAt string                   `json:"unassigned_at"`
+}
+
+type GetAssignmentHistoryResponse struct {
+	Count       int                     `json:"count"`
+	Assignments []GetAssignmentResponse `json:"assignments"`
+}
@@ -14,23 +14,55 @@ type ResetPasswordCache struct {
 }
 
 type LoginRequest struct {
-	Username string `json:"username" binding:"required,min=3,max=30"`
-	Password string `json:"password" binding:"required,min=3,max=30"`
+	Username  string `json:"username" binding:"required,min=3,max=30"`
+	Password  string `json:"password" binding:"required,min=3,max=30"`
+	UserAgent string `json:"-" swaggerignore:"true"`
+	IP        string `json:"-" swaggerignore:"true"`
 }
 
 type RefreshTokenRequest struct {
-	Token string `json:"token" binding:"required"`
+	Token     string `json:"token" binding:"required"`
+	UserAgent string `json:"-" swaggerignore:"true"`
+	IP        string `json:"-" swaggerignore:"true"`
+}
+
+type LogoutRequest struct {
+	AccessToken  string `json:"-" swaggerignore:"true"`
+	RefreshToken string `json:"refresh_token"`
 }
 
 type AuthenticationResponse struct {
-	AccessToken  string                  `json:"access_token"`
-	RefreshToken string                  `json:"refresh_token"`
-	Permissions  []GetPermissionResponse `json:"permissions"`
+	AccessToken       string                  `json:"access_token"`
+	RefreshToken      string                  `json:"refresh_token"`
+	Permissions       []GetPermissionResponse `json:"permissions"`
+	TwoFactorRequired bool                    `json:"two_factor_required,omitempty"`
+	ChallengeID       string                  `json:"challenge_id,omitempty"`
+}
+
+type VerifyTwoFactorRequest struct {
+	ChallengeID string `json:"challenge_id" binding:"required,uuid4"`
+	Code        string `json:"code" binding:"required" example:"123456"`
+	UserAgent   string `json:"-" swaggerignore:"true"`
+	IP          string `json:"-" swaggerignore:"true"`
+}
+
+// TwoFactorChallenge keeps user between password and code steps of login
+type TwoFactorChallenge struct {
+	UserID    string `json:"user_id"`
+	Username  string `json:"username"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+type UnlockLoginRequest struct {
+	UserID     string `json:"user_id" binding:"required_without=IP,omitempty,uuid4"`
+	IP         string `json:"ip" binding:"omitempty,ip"`
+	UnlockedBy string `json:"-" swaggerignore:"true"`
 }
 
 type HasAccessRequest struct {
-	StepID   string `json:"step_id" binding:"required"`
-	DriverID string `json:"driver_id" binding:"required"`
+	StepID   string `json:"step_id" form:"step_id" binding:"required,uuid4"`
+	DriverID string `json:"driver_id" form:"driver_id" binding:"required,uuid4"`
+	UserID   string `json:"user_id" form:"-" swaggerignore:"true"`
 }
 
 type HasAccessResponse struct {
@@ -8,14 +8,13 @@ type GetCarDashboardResponse struct {
 }
 
 type CreateCarRequest struct {
-	ID          string            `json:"id" swaggerignore:"true"`
-	Make        string            `json:"make" example:"Toyota"`
-	Model       string            `json:"model" example:"Camry"`
-	Year        int               `json:"year" example:"2014"`
-	Color       string            `json:"color" example:"Black"`
-	PlateNumber string            `json:"plate_number" example:"AV82062"`
-	ImageID     string            `json:"image_id,omitempty"`
-	Status      GetStatusResponse `json:"status"`
+	ID          string `json:"id" swaggerignore:"true"`
+	Make        string `json:"make" binding:"required" example:"Toyota"`
+	Model       string `json:"model" binding:"required" example:"Camry"`
+	Year        int    `json:"year" binding:"required" example:"2014"`
+	Color       string `json:"color" binding:"required" example:"Black"`
+	PlateNumber string `json:"plate_number" binding:"required" example:"AV82062"`
+	ImageID     string `json:"image_id,omitempty" binding:"omitempty,uuid4"`
 }
 
 type GetCarResponse struct {
@@ -31,7 +30,7 @@ type GetCarResponse struct {
 
 type GetAllCarsRequest struct {
 	PageRequest
-	Search string `json:"search" example:"Nissan"`
+	Search string `json:"search" form:"search" example:"Nissan"`
 }
 
 type GetAllCarsResponse struct {
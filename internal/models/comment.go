@@ -0,0 +1,36 @@
+package models
+
+type CreateCommentRequest struct {
+	ID         string   `json:"id" swaggerignore:"true"`
+	EntityType string   `json:"entity_type" binding:"required,oneof=truck trailer driver truck_inspection trailer_inspection driver_step" example:"driver_step"`
+	EntityID   string   `json:"entity_id" binding:"required,uuid4"`
+	DriverID   string   `json:"driver_id,omitempty" binding:"required_if=EntityType driver_step,omitempty,uuid4"`
+	Text       string   `json:"text" binding:"required"`
+	MentionIDs []string `json:"mention_ids" binding:"omitempty,dive,uuid4"`
+	CreatedBy  string   `json:"created_by" swaggerignore:"true"`
+}
+
+type UpdateCommentRequest struct {
+	ID         string   `json:"id" swaggerignore:"true"`
+	Text       string   `json:"text" binding:"required"`
+	MentionIDs []string `json:"mention_ids" binding:"omitempty,dive,uuid4"`
+	UpdatedBy  string   `json:"updated_by" swaggerignore:"true"`
+}
+
+type GetAllCommentsRequest struct {
+	PageRequest
+	EntityType string `json:"entity_type" form:"entity_type" binding:"required,oneof=truck trailer driver truck_inspection trailer_inspection driver_step" example:"driver_step"`
+	EntityID   string `json:"entity_id" form:"entity_id" binding:"required,uuid4"`
+	DriverID   string `json:"driver_id" form:"driver_id" binding:"required_if=EntityType driver_step,omitempty,uuid4"`
+	UserID     string `json:"-" form:"-" swaggerignore:"true"`
+}
+
+type GetAllCommentsResponse struct {
+	Count    int                  `json:"count"`
+	Comments []GetCommentResponse `json:"comments"`
+}
+
+type CommentMentionResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
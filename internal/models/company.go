@@ -2,12 +2,12 @@ package models
 
 type CreateCompanyRequest struct {
 	ID   string `json:"id" swaggerignore:"true"`
-	Name string `json:"name"`
+	Name string `json:"name" binding:"required" example:"Acme Logistics"`
 }
 
 type UpdateCompanyRequest struct {
 	ID   string `json:"id" swaggerignore:"true"`
-	Name string `json:"name"`
+	Name string `json:"name" binding:"required" example:"Acme Logistics"`
 }
 
 type GetCompanyResponse struct {
@@ -18,9 +18,8 @@ type GetCompanyResponse struct {
 }
 
 type GetAllCompaniesRequest struct {
-	Page   int    `json:"page"`
-	Limit  int    `json:"limit"`
-	Search string `json:"search"`
+	PageRequest
+	Search string `json:"search" form:"search"`
 }
 
 type GetAllCompaniesResponse struct {
@@ -4,10 +4,14 @@ type CreateDepartmentRequest struct {
 	ID       string           `json:"id" swaggerignore:"true"`
 	Alias    string           `json:"alias" binding:"required"`
 	Name     string           `json:"name" binding:"required"`
-	Sequence int              `json:"sequence" binding:"required"`
+	Sequence int              `json:"sequence" binding:"required,min=1"`
 	Gradient GradientResponse `json:"gradient" binding:"required"`
 }
 
+type ReorderDepartmentsRequest struct {
+	IDs []string `json:"ids" binding:"required,min=1,dive,uuid4"`
+}
+
 type DepartmentStatisticResponse struct {
 	ID         string           `json:"id"`
 	Alias      string           `json:"alias"`
@@ -37,3 +41,22 @@ type GetDepartmentResponse struct {
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
 }
+
+type SetDepartmentAccessRequest struct {
+	DepartmentID string `json:"department_id" binding:"required,uuid4"`
+	RoleID       string `json:"role_id" binding:"required,uuid4"`
+	CanView      bool   `json:"can_view"`
+	CanEdit      bool   `json:"can_edit"`
+	CanCreate    bool   `json:"can_create"`
+	CanDelete    bool   `json:"can_delete"`
+}
+
+type GetDepartmentAccessResponse struct {
+	Role GetRoleResponse `json:"role"`
+	HasAccessResponse
+}
+
+type GetAllDepartmentAccessResponse struct {
+	Count    int                           `json:"count"`
+	Accesses []GetDepartmentAccessResponse `json:"accesses"`
+}
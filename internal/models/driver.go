@@ -24,7 +24,7 @@ type CreateDriverRequest struct {
 	LastName  string `json:"last_name" binding:"required"`
 	Email     string `json:"email" binding:"required,email"`
 	Phone     string `json:"phone" binding:"required"`
-	ImageID   string `json:"image_id" format:"uuid4,omitempty"`
+	ImageID   string `json:"image_id,omitempty" binding:"omitempty,uuid4"`
 }
 
 type GetDriverResponse struct {
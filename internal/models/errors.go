@@ -6,4 +6,6 @@ var (
 	ErrNotFound     = errors.New("not found")
 	ErrUnauthorized = errors.New("unauthorized")
 	ErrForbidden    = errors.New("permission denied")
+	ErrConflict     = errors.New("conflict")
+	ErrTooManyTries = errors.New("too many failed attempts")
 )
@@ -3,12 +3,13 @@ package models
 type GetEntitiesRequest struct {
 	PageRequest
 	Search     string `json:"search" form:"search"`
-	EntityType string `json:"entity_type" form:"entity_type" binding:"required,oneof=recruiter safety company truck trailer driver driver_type drug_test_type fuel_card_status road_test_status incab_devices external_devices need_repair_devices"`
+	EntityType string `json:"entity_type" form:"entity_type" binding:"required" example:"driver_type"`
 }
 
 type GetEntitiesResponse struct {
-	Count    int                 `json:"count"`
-	Entities []GetEntityResponse `json:"entities"`
+	Count      int                 `json:"count"`
+	CanAddItem bool                `json:"can_add_item"`
+	Entities   []GetEntityResponse `json:"entities"`
 }
 
 type GetEntityResponse struct {
@@ -16,9 +17,31 @@ type GetEntityResponse struct {
 	Name string `json:"name"`
 }
 
+type CreateEntityRequest struct {
+	ID         string `json:"id" swaggerignore:"true"`
+	EntityType string `json:"entity_type" binding:"required" example:"driver_type"`
+	Name       string `json:"name" binding:"required" example:"Owner operator"`
+	CreatedBy  string `json:"created_by" swaggerignore:"true"`
+}
+
 type GetAllFormRequest struct {
 	PageRequest
-	Search string `json:"search"`
+	Search string `json:"search" form:"search"`
+}
+
+type GetFormRequest struct {
+	ID      string `json:"id" swaggerignore:"true"`
+	Version int    `json:"version" form:"version" example:"2"`
+}
+
+type GetAllFormVersionsResponse struct {
+	Count    int                      `json:"count"`
+	Versions []GetFormVersionResponse `json:"versions"`
+}
+
+type GetFormVersionResponse struct {
+	Version   int    `json:"version" example:"2"`
+	CreatedAt string `json:"created_at"`
 }
 
 type GetAllFormResponse struct {
@@ -29,29 +52,31 @@ type GetAllFormResponse struct {
 type CreateFormRequest struct {
 	ID     string   `json:"id" swaggerignore:"true"`
 	Title  string   `json:"title" binding:"required"`
-	Groups []string `json:"groups" binding:"required"`
+	Groups []string `json:"groups" binding:"required,dive,uuid4"`
 }
 
 type GetFormResponse struct {
-	ID     string                 `json:"id"`
-	Title  string                 `json:"title"`
-	Groups []GetFormGroupResponse `json:"groups"`
+	ID      string                 `json:"id"`
+	Title   string                 `json:"title"`
+	Version int                    `json:"version"`
+	Groups  []GetFormGroupResponse `json:"groups"`
 }
 
 type CreateFormGroupRequest struct {
 	ID       string                   `json:"id" swaggerignore:"true"`
 	Title    string                   `json:"title" binding:"required" example:"Select driver type"`
+	Alias    string                   `json:"alias" example:"select-driver-type"`
 	Sequence int                      `json:"sequence" binding:"required" example:"1"`
-	Fields   []CreateFormFieldRequest `json:"fields" binding:"required"`
+	Fields   []CreateFormFieldRequest `json:"fields" binding:"required,dive"`
 }
 
 type CreateFormFieldRequest struct {
-	ID            string                   `json:"id" example:"f535ef7c-2718-49ae-9fcf-65670fcad644"`
+	ID            string                   `json:"id" binding:"omitempty,uuid4" example:"f535ef7c-2718-49ae-9fcf-65670fcad644"`
 	Label         string                   `json:"label" binding:"required" example:"Select Recruiter"`
 	LabelPosition string                   `json:"label_position" binding:"required,oneof=top bottom left right inside"`
 	Icon          string                   `json:"icon" example:"http://example.com/pencil.svg"`
 	Alias         string                   `json:"alias" example:"#select-recruiter"`
-	ChildFields   []CreateFormFieldRequest `json:"child_fields,omitempty"`
+	ChildFields   []CreateFormFieldRequest `json:"child_fields,omitempty" binding:"dive"`
 	Hint          string                   `json:"hint" example:"Select recruiter from list"`
 	Warning       string                   `json:"warning" example:"Give trainee handbook"`
 	Sequence      int                      `json:"sequence" example:"1"`
@@ -116,12 +141,41 @@ type FormValidation struct {
 }
 
 type SetFormFieldValue struct {
-	DriverID string               `json:"driver_id" example:"f535ef7c-2718-49ae-9fcf-65670fcad644"`
-	StepID   string               `json:"step_id" example:"f535ef7c-2718-49ae-9fcf-65670fcad644"`
-	Fields   []FormFieldWithValue `json:"fields"`
+	DriverID  string               `json:"driver_id" binding:"required,uuid4" example:"f535ef7c-2718-49ae-9fcf-65670fcad644"`
+	StepID    string               `json:"step_id" binding:"required,uuid4" example:"f535ef7c-2718-49ae-9fcf-65670fcad644"`
+	Fields    []FormFieldWithValue `json:"fields" binding:"required,dive"`
+	CreatedBy string               `json:"created_by" swaggerignore:"true"`
 }
 
 type FormFieldWithValue struct {
 	FieldID string   `json:"field_id" binding:"required,uuid4" example:"f535ef7c-2718-49ae-9fcf-65670fcad644"`
 	Values  []string `json:"values"`
 }
+
+type GetFormFieldValueHistoryRequest struct {
+	PageRequest
+	DriverID string `json:"driver_id" form:"driver_id" binding:"required,uuid4"`
+	FieldID  string `json:"field_id" form:"field_id" binding:"required,uuid4"`
+	UserID   string `json:"user_id" form:"-" swaggerignore:"true"`
+}
+
+type GetFormFieldValueHistoryResponse struct {
+	Count     int                            `json:"count"`
+	Revisions []GetFormFieldRevisionResponse `json:"revisions"`
+}
+
+type GetFormFieldRevisionResponse struct {
+	ID        string      `json:"id"`
+	StepID    string      `json:"step_id"`
+	Current   bool        `json:"current"`
+	CreatedBy string      `json:"created_by"`
+	CreatedAt string      `json:"created_at"`
+	Values    []FormValue `json:"values"`
+}
+
+type RevertFormFieldValueRequest struct {
+	DriverID   string `json:"driver_id" binding:"required,uuid4"`
+	FieldID    string `json:"field_id" binding:"required,uuid4"`
+	RevisionID string `json:"revision_id" binding:"required,uuid4"`
+	CreatedBy  string `json:"created_by" swaggerignore:"true"`
+}
@@ -0,0 +1,37 @@
+package models
+
+type SaveSessionRequest struct {
+	ID             string `json:"id"`
+	UserID         string `json:"user_id"`
+	UserAgent      string `json:"user_agent"`
+	IP             string `json:"ip"`
+	AccessTokenID  string `json:"access_token_id"`
+	RefreshTokenID string `json:"refresh_token_id"`
+	ExpiresAt      int64  `json:"expires_at"`
+}
+
+type RevokeSessionRequest struct {
+	ID     string `json:"id" uri:"id" binding:"required,uuid4"`
+	UserID string `json:"-" swaggerignore:"true"`
+}
+
+type GetAllSessionsRequest struct {
+	PageRequest
+	UserID string `json:"user_id" form:"user_id" binding:"omitempty,uuid4"`
+}
+
+type GetSessionResponse struct {
+	ID         string `json:"id"`
+	UserID     string `json:"user_id"`
+	Username   string `json:"username"`
+	UserAgent  string `json:"user_agent"`
+	IP         string `json:"ip"`
+	CreatedAt  string `json:"created_at"`
+	LastSeenAt string `json:"last_seen_at"`
+	ExpiresAt  string `json:"expires_at"`
+}
+
+type GetAllSessionsResponse struct {
+	Count    int                  `json:"count"`
+	Sessions []GetSessionResponse `json:"sessions"`
+}
@@ -6,4 +6,55 @@ type GetStatusResponse struct {
 	Name     string `json:"name" example:"Booked"`
 	Sequence int    `json:"sequence" example:"1"`
 	Color    string `json:"color" example:"#EB5757"`
+	IsSystem bool   `json:"is_system" example:"true"`
+}
+
+type CreateStatusRequest struct {
+	ID         string `json:"id" swaggerignore:"true"`
+	EntityType string `json:"entity_type" binding:"required,oneof=truck trailer car driver step" example:"truck"`
+	Alias      string `json:"alias" binding:"required" example:"in_repair"`
+	Name       string `json:"name" binding:"required" example:"In repair"`
+	Sequence   int    `json:"sequence" binding:"required,min=1" example:"2"`
+	Color      string `json:"color" binding:"required,hexcolor" example:"#F2994A"`
+}
+
+type UpdateStatusRequest struct {
+	ID       string `json:"id" swaggerignore:"true"`
+	Alias    string `json:"alias" binding:"required" example:"in_repair"`
+	Name     string `json:"name" binding:"required" example:"In repair"`
+	Sequence int    `json:"sequence" binding:"required,min=1" example:"2"`
+	Color    string `json:"color" binding:"required,hexcolor" example:"#F2994A"`
+}
+
+type ReorderStatusesRequest struct {
+	EntityType string   `json:"entity_type" binding:"required,oneof=truck trailer car driver step" example:"truck"`
+	IDs        []string `json:"ids" binding:"required,min=1,dive,uuid4"`
+}
+
+type GetAllStatusesRequest struct {
+	EntityType string `json:"entity_type" form:"entity_type" binding:"required,oneof=truck trailer car driver step" example:"truck"`
+}
+
+type GetAllStatusesResponse struct {
+	Count    int                 `json:"count"`
+	Statuses []GetStatusResponse `json:"statuses"`
+}
+
+type CreateStatusTransitionRequest struct {
+	ID           string `json:"id" swaggerignore:"true"`
+	FromStatusID string `json:"from_status_id" binding:"required,uuid4"`
+	ToStatusID   string `json:"to_status_id" binding:"required,uuid4"`
+	RoleID       string `json:"role_id,omitempty" binding:"omitempty,uuid4"`
+}
+
+type GetStatusTransitionResponse struct {
+	ID         string            `json:"id"`
+	FromStatus GetStatusResponse `json:"from_status"`
+	ToStatus   GetStatusResponse `json:"to_status"`
+	Role       *GetRoleResponse  `json:"role,omitempty"`
+}
+
+type GetAllStatusTransitionsResponse struct {
+	Count       int                           `json:"count"`
+	Transitions []GetStatusTransitionResponse `json:"transitions"`
 }
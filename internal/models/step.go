@@ -1,14 +1,28 @@
 package models
 
 type UpdateStepStatusRequest struct {
-	DriverID string `json:"driver_id" binding:"required,uuid4"`
-	StepID   string `json:"step_id" binding:"required,uuid4"`
-	StatusID string `json:"status_id" binding:"required,uuid4"`
+	DriverID  string `json:"driver_id" binding:"required,uuid4"`
+	StepID    string `json:"step_id" binding:"required,uuid4"`
+	StatusID  string `json:"status_id" binding:"required,uuid4"`
+	UpdatedBy string `json:"updated_by" swaggerignore:"true"`
+	// CurrentStatusID is status which transition was checked from
+	CurrentStatusID string `json:"-"`
+}
+
+type CreateStepRequest struct {
+	ID           string `json:"id" swaggerignore:"true"`
+	DepartmentID string `json:"department_id" binding:"required,uuid4"`
+	FormID       string `json:"form_id,omitempty" binding:"omitempty,uuid4"`
+	Alias        string `json:"alias" binding:"required" example:"road-test"`
+	Name         string `json:"name" binding:"required" example:"Road test"`
+	Sequence     int    `json:"sequence" binding:"required" example:"1"`
+	UserID       string `json:"user_id" swaggerignore:"true"`
 }
 
 type GetAllStepsRequest struct {
 	DepartmentID string `json:"department_id" uri:"department_id" binding:"required,uuid4" swaggerignore:"true"`
 	DriverID     string `json:"driver_id" uri:"driver_id" binding:"required,uuid4" swaggerignore:"true"`
+	UserID       string `json:"user_id" swaggerignore:"true"`
 }
 
 type GetAllStepsGroupByStatusResponse struct {
@@ -40,10 +54,12 @@ type GetFieldValueResponse struct {
 }
 
 type GetStepResponse struct {
-	ID       string       `json:"id"`
-	Alias    string       `json:"alias"`
-	Name     string       `json:"name"`
-	Sequence int          `json:"sequence"`
-	StatusID string       `json:"-"`
-	Fields   []StepFields `json:"fields"`
+	ID           string       `json:"id"`
+	DepartmentID string       `json:"department_id,omitempty"`
+	FormID       string       `json:"form_id,omitempty"`
+	Alias        string       `json:"alias"`
+	Name         string       `json:"name"`
+	Sequence     int          `json:"sequence"`
+	StatusID     string       `json:"-"`
+	Fields       []StepFields `json:"fields"`
 }
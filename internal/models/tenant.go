@@ -0,0 +1,12 @@
+package models
+
+// TenantKey is key of Tenant in request context
+const TenantKey = "tenant"
+
+// Tenant limits rows to company of authenticated user,
+// admins see rows of all companies until they switch to one of them
+type Tenant struct {
+	UserID    string
+	CompanyID string
+	All       bool
+}
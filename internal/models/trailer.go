@@ -14,6 +14,7 @@ type GetTrailerDashboardResponse struct {
 }
 
 type GetTrailerPickUpDropOffHistoryRequest struct {
+	TrailerID string `json:"trailer_id" swaggerignore:"true"`
 	PageRequest
 	Search string `json:"search" form:"search"`
 }
@@ -55,6 +56,7 @@ type GetTrailerInspectionResponse struct {
 	CompanyRepresentativeSignatureID string               `json:"company_representative_signature_id"`
 	Comments                         []GetCommentResponse `json:"comments"`
 	InspectionType                   string               `json:"inspection_type"`
+	CreatedAt                        string               `json:"created_at"`
 }
 
 type CreateTrailerInspectionRequest struct {
@@ -76,6 +78,7 @@ type CreateTrailerInspectionRequest struct {
 	DriverSignatureID                string               `json:"driver_signature_id" binding:"required,uuid4"`
 	CompanyRepresentativeSignatureID string               `json:"company_representative_signature_id" binding:"required,uuid4"`
 	Comments                         []GetCommentResponse `json:"comments"`
+	CreatedBy                        string               `json:"created_by" swaggerignore:"true"`
 }
 
 type AssignedTrailerResponse struct {
@@ -90,6 +93,8 @@ type CreateTrailerRequest struct {
 	YearMade    int    `json:"year_made" binding:"required" example:"2020"`
 	TrailerType string `json:"trailer_type" binding:"required" example:"Dry"`
 	PlateNumber string `json:"plate_number" binding:"required" example:"3920-3920"`
+	StatusID    string `json:"status_id" binding:"omitempty,uuid4"`
+	DriverID    string `json:"driver_id" binding:"omitempty,uuid4"`
 }
 
 type GetTrailerResponse struct {
@@ -55,6 +55,12 @@ type GetTruckInspectionResponse struct {
 	CompanyRepresentativeSignatureID string               `json:"company_representative_signature_id"`
 	Comments                         []GetCommentResponse `json:"comments"`
 	InspectionType                   string               `json:"inspection_type"`
+	CreatedAt                        string               `json:"created_at"`
+}
+
+type GetTruckLatestInspectionsResponse struct {
+	PickUp  *GetTruckInspectionResponse `json:"pickup"`
+	DropOff *GetTruckInspectionResponse `json:"drop_off"`
 }
 
 type CreateTruckInspectionRequest struct {
@@ -63,25 +69,33 @@ type CreateTruckInspectionRequest struct {
 	DriverID                         string               `json:"driver_id" binding:"required,uuid4"`
 	InspectionType                   string               `json:"inspection_type" binding:"required,oneof=pickup drop-off"`
 	Location                         string               `json:"location" binding:"required"`
-	OdometerImages                   []string             `json:"odometer_images" binding:"required"`
-	FuelLevelImages                  []string             `json:"fuel_level_images" binding:"required"`
-	DriverSideImages                 []string             `json:"driver_side_images" binding:"required"`
-	FrontSideImages                  []string             `json:"front_side_images" binding:"required"`
-	PassengerSideImages              []string             `json:"passenger_side_images" binding:"required"`
-	BackSideImages                   []string             `json:"back_side_images" binding:"required"`
-	TireImages                       []string             `json:"tire_images" binding:"required"`
-	DamageImages                     []string             `json:"damage_images" binding:"required"`
+	OdometerImages                   []string             `json:"odometer_images" binding:"required,dive,uuid4"`
+	FuelLevelImages                  []string             `json:"fuel_level_images" binding:"required,dive,uuid4"`
+	DriverSideImages                 []string             `json:"driver_side_images" binding:"required,dive,uuid4"`
+	FrontSideImages                  []string             `json:"front_side_images" binding:"required,dive,uuid4"`
+	PassengerSideImages              []string             `json:"passenger_side_images" binding:"required,dive,uuid4"`
+	BackSideImages                   []string             `json:"back_side_images" binding:"required,dive,uuid4"`
+	TireImages                       []string             `json:"tire_images" binding:"required,dive,uuid4"`
+	DamageImages                     []string             `json:"damage_images" binding:"required,dive,uuid4"`
 	IncabDevices                     []string             `json:"incab_devices" binding:"required"`
 	ExternalDisplayed                []string             `json:"external_displayed" binding:"required"`
 	DriverSignatureID                string               `json:"driver_signature_id" binding:"required,uuid4"`
 	CompanyRepresentativeSignatureID string               `json:"company_representative_signature_id" binding:"required,uuid4"`
 	Comments                         []GetCommentResponse `json:"comments"`
+	CreatedBy                        string               `json:"created_by" swaggerignore:"true"`
 }
 
 type GetCommentResponse struct {
-	CreatedBy string `json:"created_by"`
-	Text      string `json:"text"`
-	CreatedAt string `json:"created_at"`
+	ID         string                   `json:"id,omitempty"`
+	EntityType string                   `json:"entity_type,omitempty"`
+	EntityID   string                   `json:"entity_id,omitempty"`
+	DriverID   string                   `json:"driver_id,omitempty"`
+	AuthorID   string                   `json:"author_id,omitempty"`
+	CreatedBy  string                   `json:"created_by"`
+	Text       string                   `json:"text"`
+	Mentions   []CommentMentionResponse `json:"mentions,omitempty"`
+	CreatedAt  string                   `json:"created_at"`
+	UpdatedAt  string                   `json:"updated_at,omitempty"`
 }
 
 type AssignedTruckResponse struct {
@@ -97,6 +111,8 @@ type CreateTruckRequest struct {
 	YearMade    int     `json:"year_made" binding:"required" example:"2020"`
 	Milage      float64 `json:"milage" binding:"required" example:"350000"`
 	PlateNumber string  `json:"plate_number" binding:"required" example:"3920-3920"`
+	StatusID    string  `json:"status_id" binding:"omitempty,uuid4"`
+	DriverID    string  `json:"driver_id" binding:"omitempty,uuid4"`
 }
 
 type GetTruckResponse struct {
@@ -0,0 +1,26 @@
+package models
+
+type TwoFactorCodeRequest struct {
+	Code   string `json:"code" binding:"required" example:"123456"`
+	UserID string `json:"-" swaggerignore:"true"`
+}
+
+type SetupTwoFactorResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type GetTwoFactorResponse struct {
+	UserID  string `json:"user_id"`
+	Secret  string `json:"-"` // encrypted with security.EncryptTotpSecret
+	Enabled bool   `json:"enabled"`
+}
+
+type RecoveryCode struct {
+	ID       string `json:"id"`
+	CodeHash string `json:"-"`
+}
@@ -2,6 +2,27 @@ package models
 
 type DriverFileJobStatus int
 
+const (
+	DriverFileJobStatusInit DriverFileJobStatus = iota
+	DriverFileJobStatusInProcess
+	DriverFileJobStatusError
+	DriverFileJobStatusFinish
+)
+
+// String returns file status of job which is exposed in GetFileResponse.FileStatus
+func (s DriverFileJobStatus) String() string {
+	switch s {
+	case DriverFileJobStatusInProcess:
+		return "in_process"
+	case DriverFileJobStatusError:
+		return "error"
+	case DriverFileJobStatusFinish:
+		return "finish"
+	default:
+		return "init"
+	}
+}
+
 type GetFileRequest struct {
 	ID string `json:"id" uri:"id" binding:"required,uuid4"`
 }
@@ -18,13 +39,22 @@ type GenerateZipInternalRequest struct {
 	EntityID        string
 	EntityType      string
 	RequestID       string
+	CompanyID       string
+	UserID          string
 	GeneratedFileID string
 	Status          DriverFileJobStatus
 	JobError        error
 }
 
+// ZipFileStatus is cached status of zip generation, only its requester can get it
+type ZipFileStatus struct {
+	File      GetFileResponse `json:"file"`
+	CompanyID string          `json:"company_id"`
+	UserID    string          `json:"user_id"`
+}
+
 type GenerateZipFileRequest struct {
-	EntityID   string `json:"entity_id" binding:"required"`
+	EntityID   string `json:"entity_id" binding:"required,uuid4"`
 	EntityType string `json:"entity_type" binding:"required,oneof=driver truck trailer"`
 }
 
@@ -32,6 +62,10 @@ type GenerateZipFileResponse struct {
 	RequestID string `json:"request_id"`
 }
 
+type GetZipFileRequest struct {
+	RequestID string `json:"request_id" uri:"request_id" binding:"required,uuid4"`
+}
+
 type FilesForZippingResponse struct {
 	CategoryName string
 	FileNames    []string
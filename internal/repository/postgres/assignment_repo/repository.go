@@ -0,0 +1,321 @@
+package assignment_repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/abdivasiyev/project_template/config"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	"github.com/abdivasiyev/project_template/internal/types"
+	"github.com/abdivasiyev/project_template/pkg/helpers"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/storage"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+)
+
+const (
+	EntityTypeTruck   = "truck"
+	EntityTypeTrailer = "trailer"
+)
+
+// entityColumns maps assignable entity type to its column in driver_assignment table,
+// table of entity has the same name as entity type
+var entityColumns = map[string]string{
+	EntityTypeTruck:   "truck_id",
+	EntityTypeTrailer: "trailer_id",
+}
+
+var Module = fx.Provide(New)
+
+type repo struct {
+	querier storage.Querier
+	log     logger.Logger
+}
+
+type Params struct {
+	fx.In
+	Querier storage.Querier
+	Log     logger.Logger
+}
+
+func New(params Params) repository.Assignment {
+	return &repo{
+		querier: params.Querier,
+		log:     params.Log,
+	}
+}
+
+// Assign makes driver active driver of truck or trailer, previous assignments of driver
+// to the same entity type and previous assignment of entity are closed.
+// Returns models.ErrNotFound when driver or entity does not exist and
+// models.ErrConflict when driver or entity is assigned concurrently
+func (r *repo) Assign(ctx context.Context, req models.AssignDriverRequest) error {
+	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+
+	if err = AssignTx(tx, helpers.GetTenant(ctx), req); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// AssignTx is Assign within given transaction, so entity can be saved together with its assignment
+func AssignTx(tx *sqlx.Tx, tenant models.Tenant, req models.AssignDriverRequest) error {
+	column, ok := entityColumns[req.EntityType]
+	if !ok {
+		return models.ErrNotFound
+	}
+
+	var isActive bool
+
+	query := fmt.Sprintf(`
+		select exists(
+			select 1 from driver_assignment
+			where driver_id = $1 and %s = $2 and unassigned_at is null
+		)
+	`, column)
+
+	if err := tx.QueryRow(query, req.DriverID, req.EntityID).Scan(&isActive); err != nil {
+		return errors.Wrap(err, "could not check active assignment")
+	}
+
+	if isActive {
+		return nil
+	}
+
+	query = fmt.Sprintf(`
+		update driver_assignment set unassigned_at = current_timestamp
+		where unassigned_at is null
+		  and ((driver_id = $1 and %[1]s is not null) or %[1]s = $2)
+	`, column)
+
+	if _, err := tx.Exec(query, req.DriverID, req.EntityID); err != nil {
+		return errors.Wrap(err, "could not close previous assignments")
+	}
+
+	query = fmt.Sprintf(`
+		insert into driver_assignment (id, driver_id, %[1]s, assigned_by, assigned_at)
+		select $1, d.id, e.id, $4, current_timestamp
+		from driver d
+		join %[2]s e on e.id = $3 and e.deleted_at is null
+		  and ($5 or e.company_id is not distinct from $6)
+		where d.id = $2 and d.deleted_at is null
+		  and ($5 or d.company_id is not distinct from $6)
+	`, column, req.EntityType)
+
+	result, err := tx.Exec(
+		query,
+		req.ID,
+		req.DriverID,
+		req.EntityID,
+		helpers.ToNullString(req.AssignedBy),
+		tenant.All,
+		helpers.ToNullString(tenant.CompanyID),
+	)
+	if err != nil {
+		// concurrent assignment of the same driver or entity is rejected by unique indexes of active assignments
+		return helpers.ToCustomError(errors.Wrap(err, "could not create assignment"))
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affectedRows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// Unassign closes active assignment of truck or trailer
+func (r *repo) Unassign(ctx context.Context, req models.UnassignDriverRequest) error {
+	tenant := helpers.GetTenant(ctx)
+
+	column, ok := entityColumns[req.EntityType]
+	if !ok {
+		return models.ErrNotFound
+	}
+
+	result, err := r.querier.Exec(ctx, unassignQuery(column, req.EntityType), req.EntityID, tenant.All, helpers.ToNullString(tenant.CompanyID))
+	if err != nil {
+		return errors.Wrap(err, "could not close assignment")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affectedRows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// UnassignTx closes active assignment of truck or trailer within given transaction,
+// entity without active assignment is left as is
+func UnassignTx(tx *sqlx.Tx, tenant models.Tenant, req models.UnassignDriverRequest) error {
+	column, ok := entityColumns[req.EntityType]
+	if !ok {
+		return models.ErrNotFound
+	}
+
+	_, err := tx.Exec(unassignQuery(column, req.EntityType), req.EntityID, tenant.All, helpers.ToNullString(tenant.CompanyID))
+
+	return errors.Wrap(err, "could not close assignment")
+}
+
+func unassignQuery(column, entityType string) string {
+	return fmt.Sprintf(`
+		update driver_assignment da set unassigned_at = current_timestamp
+		from %[2]s e
+		where da.%[1]s = $1 and da.unassigned_at is null
+		  and e.id = da.%[1]s and ($2 or e.company_id is not distinct from $3)
+	`, column, entityType)
+}
+
+func (r *repo) GetHistory(ctx context.Context, req models.GetAssignmentHistoryRequest) (models.GetAssignmentHistoryResponse, error) {
+	var (
+		response  models.GetAssignmentHistoryResponse
+		statement = `WHERE (:tenant_all OR d.company_id IS NOT DISTINCT FROM :tenant_id)`
+		params    = make(types.M)
+		tenant    = helpers.GetTenant(ctx)
+	)
+
+	params["tenant_all"], params["tenant_id"] = tenant.All, helpers.ToNullString(tenant.CompanyID)
+
+	if !helpers.IsEmpty(req.DriverID) {
+		params["driver_id"] = req.DriverID
+		statement += ` AND da.driver_id = :driver_id`
+	}
+
+	if column, ok := entityColumns[req.EntityType]; ok {
+		statement += ` AND da.` + column + ` IS NOT NULL`
+
+		if !helpers.IsEmpty(req.EntityID) {
+			params["entity_id"] = req.EntityID
+			statement += ` AND da.` + column + ` = :entity_id`
+		}
+	} else if !helpers.IsEmpty(req.EntityID) {
+		params["entity_id"] = req.EntityID
+		statement += ` AND (da.truck_id = :entity_id OR da.trailer_id = :entity_id)`
+	}
+
+	if req.Active {
+		statement += ` AND da.unassigned_at IS NULL`
+	}
+
+	params["offset"], params["limit"] = helpers.NormalizePagination(req.Page, req.Limit)
+
+	queryCount := `
+		SELECT
+			count(1)
+		FROM driver_assignment da
+		JOIN driver d ON d.id = da.driver_id
+	` + statement
+
+	stmtCount, err := r.querier.PrepareNamed(ctx, queryCount)
+	if err != nil {
+		return response, errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmtCount.Close()
+
+	if err = stmtCount.QueryRow(params).Scan(&response.Count); err != nil {
+		return response, helpers.ToCustomError(err)
+	}
+
+	query := `
+		SELECT
+			da.id,
+			d.id,
+			d.first_name || ' ' || d.last_name,
+			t.id,
+			t.number,
+			tr.id,
+			tr.number,
+			da.assigned_by,
+			da.assigned_at,
+			da.unassigned_at
+		FROM driver_assignment da
+		JOIN driver d ON d.id = da.driver_id
+		LEFT JOIN truck t ON t.id = da.truck_id
+		LEFT JOIN trailer tr ON tr.id = da.trailer_id
+	` + statement + `
+		ORDER BY da.assigned_at DESC
+		OFFSET :offset LIMIT :limit
+	`
+
+	stmt, err := r.querier.PrepareNamed(ctx, query)
+	if err != nil {
+		return response, errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(params)
+	if err != nil {
+		return response, errors.Wrap(err, "could not query with params")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			assignment               models.GetAssignmentResponse
+			assignedAt               time.Time
+			unassignedAt             sql.NullTime
+			truckID, truckNumber     sql.NullString
+			trailerID, trailerNumber sql.NullString
+			assignedBy               sql.NullString
+		)
+
+		if err = rows.Scan(
+			&assignment.ID,
+			&assignment.Driver.ID,
+			&assignment.Driver.Name,
+			&truckID,
+			&truckNumber,
+			&trailerID,
+			&trailerNumber,
+			&assignedBy,
+			&assignedAt,
+			&unassignedAt,
+		); err != nil {
+			return response, errors.Wrap(err, "could not scan rows")
+		}
+
+		if truckID.Valid {
+			assignment.EntityType = EntityTypeTruck
+			assignment.Truck = &models.AssignedTruckResponse{
+				ID:     truckID.String,
+				Number: truckNumber.String,
+			}
+		}
+
+		if trailerID.Valid {
+			assignment.EntityType = EntityTypeTrailer
+			assignment.Trailer = &models.AssignedTrailerResponse{
+				ID:     trailerID.String,
+				Number: trailerNumber.String,
+			}
+		}
+
+		assignment.AssignedBy = assignedBy.String
+		assignment.AssignedAt = helpers.TimeToString(assignedAt, config.DateTimeFormat, true)
+		assignment.UnassignedAt = helpers.TimeToString(unassignedAt.Time, config.DateTimeFormat, unassignedAt.Valid)
+
+		response.Assignments = append(response.Assignments, assignment)
+	}
+
+	return response, nil
+}
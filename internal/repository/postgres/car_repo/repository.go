@@ -0,0 +1,478 @@
+package car_repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/abdivasiyev/project_template/config"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	"github.com/abdivasiyev/project_template/internal/types"
+	"github.com/abdivasiyev/project_template/pkg/helpers"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/storage"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+)
+
+const (
+	StatusAvailable = "available"
+	StatusBooked    = "booked"
+
+	TypePickup  = "pickup"
+	TypeDropOff = "dropoff"
+)
+
+var Module = fx.Provide(New)
+
+type repo struct {
+	querier storage.Querier
+	log     logger.Logger
+}
+
+type Params struct {
+	fx.In
+	Querier storage.Querier
+	Log     logger.Logger
+}
+
+func New(params Params) repository.Car {
+	return &repo{
+		querier: params.Querier,
+		log:     params.Log,
+	}
+}
+
+func (r *repo) Create(ctx context.Context, req models.CreateCarRequest) error {
+	tenant := helpers.GetTenant(ctx)
+
+	query := `
+		insert into car (id, make, model, year, color, plate_number, image_id, status_id, company_id, created_at)
+		values (
+			$1, $2, $3, $4, $5, $6, $7,
+			(select id from status where entity_type = 'car' and alias = $8 and deleted_at is null),
+			$9,
+			current_timestamp
+		)
+	`
+
+	_, err := r.querier.Exec(
+		ctx,
+		query,
+		req.ID,
+		req.Make,
+		req.Model,
+		req.Year,
+		req.Color,
+		req.PlateNumber,
+		helpers.ToNullString(req.ImageID),
+		StatusAvailable,
+		helpers.ToNullString(tenant.CompanyID),
+	)
+
+	return errors.Wrap(helpers.ToCustomError(err), "could not create car")
+}
+
+func (r *repo) Update(ctx context.Context, req models.CreateCarRequest) error {
+	tenant := helpers.GetTenant(ctx)
+
+	query := `
+		update car set
+			make = $2,
+			model = $3,
+			year = $4,
+			color = $5,
+			plate_number = $6,
+			image_id = $7,
+			updated_at = current_timestamp
+		where id = $1 and deleted_at is null
+		  and ($8 or company_id is not distinct from $9)
+	`
+
+	result, err := r.querier.Exec(
+		ctx,
+		query,
+		req.ID,
+		req.Make,
+		req.Model,
+		req.Year,
+		req.Color,
+		req.PlateNumber,
+		helpers.ToNullString(req.ImageID),
+		tenant.All,
+		helpers.ToNullString(tenant.CompanyID),
+	)
+	if err != nil {
+		return errors.Wrap(err, "could not update car")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affectedRows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *repo) Delete(ctx context.Context, id string) error {
+	tenant := helpers.GetTenant(ctx)
+
+	query := `
+		update car set deleted_at = current_timestamp
+		where id = $1 and deleted_at is null
+		  and ($2 or company_id is not distinct from $3)
+	`
+
+	result, err := r.querier.Exec(ctx, query, id, tenant.All, helpers.ToNullString(tenant.CompanyID))
+	if err != nil {
+		return errors.Wrap(err, "could not delete car")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affectedRows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *repo) Get(ctx context.Context, id string) (models.GetCarResponse, error) {
+	response, err := r.find(ctx, `WHERE c.id = :id AND c.deleted_at is null`, types.M{
+		"id":     id,
+		"offset": 0,
+		"limit":  1,
+	})
+	if err != nil {
+		return models.GetCarResponse{}, err
+	}
+
+	if len(response.Cars) == 0 {
+		return models.GetCarResponse{}, models.ErrNotFound
+	}
+
+	return response.Cars[0], nil
+}
+
+func (r *repo) GetAll(ctx context.Context, req models.GetAllCarsRequest) (models.GetAllCarsResponse, error) {
+	var (
+		statement = `WHERE c.deleted_at is null`
+		params    = make(types.M)
+	)
+
+	if !helpers.IsEmpty(req.Search) {
+		params["search"] = req.Search
+
+		statement += ` AND (
+			c.make ilike '%' || :search || '%' OR
+			c.model ilike '%' || :search || '%' OR
+			c.plate_number ilike '%' || :search || '%'
+		)`
+	}
+
+	params["offset"], params["limit"] = helpers.NormalizePagination(req.Page, req.Limit)
+	return r.find(ctx, statement, params)
+}
+
+func (r *repo) find(ctx context.Context, statement string, params types.M) (models.GetAllCarsResponse, error) {
+	var response models.GetAllCarsResponse
+
+	tenant := helpers.GetTenant(ctx)
+
+	statement += ` AND (:tenant_all OR c.company_id IS NOT DISTINCT FROM :tenant_id)`
+	params["tenant_all"], params["tenant_id"] = tenant.All, helpers.ToNullString(tenant.CompanyID)
+
+	queryCount := `
+		SELECT
+			count(1)
+		FROM car c
+	` + statement
+
+	stmtCount, err := r.querier.PrepareNamed(ctx, queryCount)
+	if err != nil {
+		return response, errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmtCount.Close()
+
+	if err = stmtCount.QueryRow(params).Scan(&response.Count); err != nil {
+		return response, helpers.ToCustomError(err)
+	}
+
+	query := `
+		SELECT
+			c.id,
+			c.make,
+			c.model,
+			c.year,
+			c.color,
+			c.plate_number,
+			c.image_id,
+			s.id,
+			s.alias,
+			s.name,
+			s.sequence,
+			s.color
+		FROM car c
+		LEFT JOIN status s ON s.id = c.status_id
+	` + statement + `
+		ORDER BY c.created_at DESC
+		OFFSET :offset LIMIT :limit
+	`
+
+	stmt, err := r.querier.PrepareNamed(ctx, query)
+	if err != nil {
+		return response, errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(params)
+	if err != nil {
+		return response, errors.Wrap(err, "could not query with params")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			car                            models.GetCarResponse
+			imageID, statusID, statusAlias sql.NullString
+			statusName, statusColor        sql.NullString
+			statusSequence                 sql.NullInt64
+		)
+
+		if err = rows.Scan(
+			&car.ID,
+			&car.Make,
+			&car.Model,
+			&car.Year,
+			&car.Color,
+			&car.PlateNumber,
+			&imageID,
+			&statusID,
+			&statusAlias,
+			&statusName,
+			&statusSequence,
+			&statusColor,
+		); err != nil {
+			return response, errors.Wrap(err, "could not scan rows")
+		}
+
+		car.ImageID = imageID.String
+		car.Status = models.GetStatusResponse{
+			ID:       statusID.String,
+			Alias:    statusAlias.String,
+			Name:     statusName.String,
+			Sequence: int(statusSequence.Int64),
+			Color:    statusColor.String,
+		}
+
+		response.Cars = append(response.Cars, car)
+	}
+
+	return response, nil
+}
+
+// CreatePickupDropOff records key handover and switches car status,
+// returns models.ErrConflict when car is already booked for pickup or not booked for drop-off
+func (r *repo) CreatePickupDropOff(ctx context.Context, req models.CarPickupDropOffRequest) error {
+	var fromStatus, toStatus = StatusAvailable, StatusBooked
+
+	if req.Type == TypeDropOff {
+		fromStatus, toStatus = StatusBooked, StatusAvailable
+	}
+
+	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+
+	query := `
+		update car set
+			status_id = (select id from status where entity_type = 'car' and alias = $3 and deleted_at is null),
+			updated_at = current_timestamp
+		where id = $1 and deleted_at is null
+		  and coalesce((select alias from status where id = car.status_id), $2) = $2
+	`
+
+	result, err := tx.Exec(query, req.CarID, fromStatus, toStatus)
+	if err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not update car status")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if affectedRows == 0 {
+		_ = tx.Rollback()
+		return models.ErrConflict
+	}
+
+	query = `
+		insert into car_pickup_dropoff (
+			id,
+			car_id,
+			full_name,
+			phone_number,
+			company_id,
+			key_given_by,
+			key_given_by_id,
+			location,
+			odometer,
+			pickup_date,
+			drop_off_date,
+			type,
+			created_at
+		) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10::timestamp, $11::timestamp, $12, current_timestamp)
+	`
+
+	if _, err = tx.Exec(
+		query,
+		req.ID,
+		req.CarID,
+		req.FullName,
+		req.PhoneNumber,
+		helpers.ToNullString(req.CompanyID),
+		req.KeyGivenBy,
+		helpers.ToNullString(req.KeyGivenByID),
+		req.Location,
+		req.Odometer,
+		req.PickupDate,
+		req.DropOffDate,
+		req.Type,
+	); err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(helpers.ToCustomError(err), "could not create car pickup/drop-off")
+	}
+
+	return tx.Commit()
+}
+
+func (r *repo) GetPickupDropOff(ctx context.Context, id string) (models.GetCarPickupDropOffResponse, error) {
+	response, err := r.findPickupDropOff(ctx, `WHERE h.id = :id`, types.M{
+		"id":     id,
+		"offset": 0,
+		"limit":  1,
+	})
+	if err != nil {
+		return models.GetCarPickupDropOffResponse{}, err
+	}
+
+	if len(response.History) == 0 {
+		return models.GetCarPickupDropOffResponse{}, models.ErrNotFound
+	}
+
+	return response.History[0], nil
+}
+
+func (r *repo) GetPickupDropOffHistory(ctx context.Context, req models.GetCarPickupDropOffHistoryRequest) (models.GetCarPickupDropOffHistoryResponse, error) {
+	params := types.M{
+		"car_id": req.CarID,
+	}
+
+	params["offset"], params["limit"] = helpers.NormalizePagination(req.Page, req.Limit)
+	return r.findPickupDropOff(ctx, `WHERE h.car_id = :car_id`, params)
+}
+
+func (r *repo) findPickupDropOff(ctx context.Context, statement string, params types.M) (models.GetCarPickupDropOffHistoryResponse, error) {
+	var response models.GetCarPickupDropOffHistoryResponse
+
+	queryCount := `
+		SELECT
+			count(1)
+		FROM car_pickup_dropoff h
+	` + statement
+
+	stmtCount, err := r.querier.PrepareNamed(ctx, queryCount)
+	if err != nil {
+		return response, errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmtCount.Close()
+
+	if err = stmtCount.QueryRow(params).Scan(&response.Count); err != nil {
+		return response, helpers.ToCustomError(err)
+	}
+
+	query := `
+		SELECT
+			h.id,
+			h.full_name,
+			h.phone_number,
+			c.id,
+			c.name,
+			h.key_given_by,
+			h.key_given_by_id,
+			h.location,
+			h.odometer,
+			h.pickup_date,
+			h.drop_off_date,
+			h.type,
+			h.created_at
+		FROM car_pickup_dropoff h
+		LEFT JOIN company c ON c.id = h.company_id
+	` + statement + `
+		ORDER BY h.created_at DESC
+		OFFSET :offset LIMIT :limit
+	`
+
+	stmt, err := r.querier.PrepareNamed(ctx, query)
+	if err != nil {
+		return response, errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(params)
+	if err != nil {
+		return response, errors.Wrap(err, "could not query with params")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			history                              models.GetCarPickupDropOffResponse
+			companyID, companyName, keyGivenByID sql.NullString
+			pickupDate, dropOffDate, createdAt   time.Time
+		)
+
+		if err = rows.Scan(
+			&history.ID,
+			&history.FullName,
+			&history.PhoneNumber,
+			&companyID,
+			&companyName,
+			&history.KeyGivenBy,
+			&keyGivenByID,
+			&history.Location,
+			&history.Odometer,
+			&pickupDate,
+			&dropOffDate,
+			&history.Type,
+			&createdAt,
+		); err != nil {
+			return response, errors.Wrap(err, "could not scan rows")
+		}
+
+		history.Company = models.GetCompanyResponse{
+			ID:   companyID.String,
+			Name: companyName.String,
+		}
+		history.KeyGivenByID = keyGivenByID.String
+		history.PickupDate = helpers.TimeToString(pickupDate, config.DateTimeFormat, true)
+		history.DropOffDate = helpers.TimeToString(dropOffDate, config.DateTimeFormat, true)
+		history.CreatedAt = helpers.TimeToString(createdAt, config.DateTimeFormat, true)
+
+		response.History = append(response.History, history)
+	}
+
+	return response, nil
+}
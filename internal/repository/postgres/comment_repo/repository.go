@@ -0,0 +1,393 @@
+package comment_repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/abdivasiyev/project_template/config"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	"github.com/abdivasiyev/project_template/internal/types"
+	"github.com/abdivasiyev/project_template/pkg/helpers"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/storage"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+)
+
+const EntityTypeDriverStep = "driver_step"
+
+// entityQueries check that commented entity exists and belongs to tenant,
+// driver step is identified by step id together with driver id
+var entityQueries = map[string]string{
+	"truck": `
+		select exists(
+			select 1 from truck
+			where id = :entity_id and deleted_at is null
+			  and (:tenant_all or company_id is not distinct from :tenant_id)
+		)
+	`,
+	"trailer": `
+		select exists(
+			select 1 from trailer
+			where id = :entity_id and deleted_at is null
+			  and (:tenant_all or company_id is not distinct from :tenant_id)
+		)
+	`,
+	"driver": `
+		select exists(
+			select 1 from driver
+			where id = :entity_id and deleted_at is null
+			  and (:tenant_all or company_id is not distinct from :tenant_id)
+		)
+	`,
+	"truck_inspection": `
+		select exists(
+			select 1 from truck_inspection ti
+			join truck t on t.id = ti.truck_id
+			where ti.id = :entity_id
+			  and (:tenant_all or t.company_id is not distinct from :tenant_id)
+		)
+	`,
+	"trailer_inspection": `
+		select exists(
+			select 1 from trailer_inspection ti
+			join trailer t on t.id = ti.trailer_id
+			where ti.id = :entity_id
+			  and (:tenant_all or t.company_id is not distinct from :tenant_id)
+		)
+	`,
+	EntityTypeDriverStep: `
+		select exists(
+			select 1 from step st
+			join driver d on d.id = :driver_id and d.deleted_at is null
+			where st.id = :entity_id and st.deleted_at is null
+			  and (:tenant_all or d.company_id is not distinct from :tenant_id)
+		)
+	`,
+}
+
+var Module = fx.Provide(New)
+
+type repo struct {
+	querier storage.Querier
+	log     logger.Logger
+}
+
+type Params struct {
+	fx.In
+	Querier storage.Querier
+	Log     logger.Logger
+}
+
+func New(params Params) repository.Comment {
+	return &repo{
+		querier: params.Querier,
+		log:     params.Log,
+	}
+}
+
+// CheckEntity returns models.ErrNotFound when commented entity does not exist
+func (r *repo) CheckEntity(ctx context.Context, entityType, entityID, driverID string) error {
+	var (
+		exists bool
+		tenant = helpers.GetTenant(ctx)
+	)
+
+	query, ok := entityQueries[entityType]
+	if !ok {
+		return models.ErrNotFound
+	}
+
+	stmt, err := r.querier.PrepareNamed(ctx, query)
+	if err != nil {
+		return errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmt.Close()
+
+	if err = stmt.QueryRow(types.M{
+		"entity_id":  entityID,
+		"driver_id":  helpers.ToNullString(driverID),
+		"tenant_all": tenant.All,
+		"tenant_id":  helpers.ToNullString(tenant.CompanyID),
+	}).Scan(&exists); err != nil {
+		return errors.Wrap(err, "could not check commented entity")
+	}
+
+	if !exists {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// Create inserts comment with its mentions,
+// returns models.ErrNotFound when any of mentioned users does not exist
+func (r *repo) Create(ctx context.Context, req models.CreateCommentRequest) error {
+	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+
+	query := `
+		insert into comment (id, entity_type, entity_id, driver_id, text, created_by, created_at)
+		values ($1, $2, $3, $4, $5, $6, current_timestamp)
+	`
+
+	if _, err = tx.Exec(
+		query,
+		req.ID,
+		req.EntityType,
+		req.EntityID,
+		helpers.ToNullString(req.DriverID),
+		req.Text,
+		helpers.ToNullString(req.CreatedBy),
+	); err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not create comment")
+	}
+
+	if err = setMentions(ctx, tx, req.ID, req.MentionIDs); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Update changes text and mentions of comment,
+// returns models.ErrNotFound when comment or any of mentioned users does not exist
+func (r *repo) Update(ctx context.Context, req models.UpdateCommentRequest) error {
+	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+
+	query := `
+		update comment set
+			text = $2,
+			updated_at = current_timestamp
+		where id = $1 and deleted_at is null
+	`
+
+	result, err := tx.Exec(query, req.ID, req.Text)
+	if err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not update comment")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if affectedRows == 0 {
+		_ = tx.Rollback()
+		return models.ErrNotFound
+	}
+
+	if _, err = tx.Exec(`delete from comment_mention where comment_id = $1`, req.ID); err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not delete comment mentions")
+	}
+
+	if err = setMentions(ctx, tx, req.ID, req.MentionIDs); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *repo) Delete(ctx context.Context, id string) error {
+	query := `update comment set deleted_at = current_timestamp where id = $1 and deleted_at is null`
+
+	result, err := r.querier.Exec(ctx, query, id)
+	if err != nil {
+		return errors.Wrap(err, "could not delete comment")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affectedRows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *repo) Get(ctx context.Context, id string) (models.GetCommentResponse, error) {
+	response, err := r.find(ctx, `WHERE c.id = :id AND c.deleted_at is null`, types.M{
+		"id":     id,
+		"offset": 0,
+		"limit":  1,
+	})
+	if err != nil {
+		return models.GetCommentResponse{}, err
+	}
+
+	if len(response.Comments) == 0 {
+		return models.GetCommentResponse{}, models.ErrNotFound
+	}
+
+	return response.Comments[0], nil
+}
+
+func (r *repo) GetAll(ctx context.Context, req models.GetAllCommentsRequest) (models.GetAllCommentsResponse, error) {
+	var (
+		statement = `WHERE c.entity_type = :entity_type AND c.entity_id = :entity_id AND c.deleted_at is null`
+		params    = make(types.M)
+	)
+
+	params["entity_type"], params["entity_id"] = req.EntityType, req.EntityID
+
+	if req.EntityType == EntityTypeDriverStep {
+		params["driver_id"] = req.DriverID
+		statement += ` AND c.driver_id = :driver_id`
+	}
+
+	params["offset"], params["limit"] = helpers.NormalizePagination(req.Page, req.Limit)
+	return r.find(ctx, statement, params)
+}
+
+func (r *repo) find(ctx context.Context, statement string, params types.M) (models.GetAllCommentsResponse, error) {
+	var response models.GetAllCommentsResponse
+
+	queryCount := `
+		SELECT
+			count(1)
+		FROM comment c
+	` + statement
+
+	stmtCount, err := r.querier.PrepareNamed(ctx, queryCount)
+	if err != nil {
+		return response, errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmtCount.Close()
+
+	if err = stmtCount.QueryRow(params).Scan(&response.Count); err != nil {
+		return response, helpers.ToCustomError(err)
+	}
+
+	query := `
+		SELECT
+			c.id,
+			c.entity_type,
+			c.entity_id,
+			c.driver_id,
+			c.created_by,
+			coalesce(u.first_name || ' ' || u.last_name, u.username, ''),
+			c.text,
+			coalesce(m.ids, '{}'),
+			coalesce(m.names, '{}'),
+			c.created_at,
+			c.updated_at
+		FROM comment c
+		LEFT JOIN "user" u ON u.id = c.created_by
+		LEFT JOIN LATERAL (
+			SELECT
+				array_agg(mu.id::text ORDER BY mu.first_name, mu.last_name) AS ids,
+				array_agg(mu.first_name || ' ' || mu.last_name ORDER BY mu.first_name, mu.last_name) AS names
+			FROM comment_mention cm
+			JOIN "user" mu ON mu.id = cm.user_id
+			WHERE cm.comment_id = c.id
+		) m ON true
+	` + statement + `
+		ORDER BY c.created_at
+		OFFSET :offset LIMIT :limit
+	`
+
+	stmt, err := r.querier.PrepareNamed(ctx, query)
+	if err != nil {
+		return response, errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(params)
+	if err != nil {
+		return response, errors.Wrap(err, "could not query with params")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			comment                  models.GetCommentResponse
+			authorID, driverID       sql.NullString
+			mentionIDs, mentionNames []string
+			createdAt                time.Time
+			updatedAt                sql.NullTime
+		)
+
+		if err = rows.Scan(
+			&comment.ID,
+			&comment.EntityType,
+			&comment.EntityID,
+			&driverID,
+			&authorID,
+			&comment.CreatedBy,
+			&comment.Text,
+			pq.Array(&mentionIDs),
+			pq.Array(&mentionNames),
+			&createdAt,
+			&updatedAt,
+		); err != nil {
+			return response, errors.Wrap(err, "could not scan rows")
+		}
+
+		for i := range mentionIDs {
+			comment.Mentions = append(comment.Mentions, models.CommentMentionResponse{
+				ID:   mentionIDs[i],
+				Name: mentionNames[i],
+			})
+		}
+
+		comment.DriverID = driverID.String
+		comment.AuthorID = authorID.String
+		comment.CreatedAt = helpers.TimeToString(createdAt, config.DateTimeFormat, true)
+		comment.UpdatedAt = helpers.TimeToString(updatedAt.Time, config.DateTimeFormat, updatedAt.Valid)
+
+		response.Comments = append(response.Comments, comment)
+	}
+
+	return response, nil
+}
+
+// setMentions links mentioned users of the same company to comment
+func setMentions(ctx context.Context, tx *sqlx.Tx, commentID string, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	tenant := helpers.GetTenant(ctx)
+
+	query := `
+		insert into comment_mention (comment_id, user_id)
+		select $1, u.id from "user" u
+		where u.id = any($2::uuid[]) and u.deleted_at is null
+		  and ($3 or u.company_id is not distinct from $4)
+		on conflict do nothing
+	`
+
+	result, err := tx.Exec(query, commentID, pq.Array(userIDs), tenant.All, helpers.ToNullString(tenant.CompanyID))
+	if err != nil {
+		return errors.Wrap(err, "could not create comment mentions")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if int(affectedRows) != len(userIDs) {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
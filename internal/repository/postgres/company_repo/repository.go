@@ -0,0 +1,189 @@
+package company_repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/abdivasiyev/project_template/config"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	"github.com/abdivasiyev/project_template/internal/types"
+	"github.com/abdivasiyev/project_template/pkg/helpers"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/storage"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+)
+
+var Module = fx.Provide(New)
+
+type repo struct {
+	querier storage.Querier
+	log     logger.Logger
+}
+
+type Params struct {
+	fx.In
+	Querier storage.Querier
+	Log     logger.Logger
+}
+
+func New(params Params) repository.Company {
+	return &repo{
+		querier: params.Querier,
+		log:     params.Log,
+	}
+}
+
+func (r *repo) Create(ctx context.Context, req models.CreateCompanyRequest) error {
+	query := `insert into company (id, name, created_at) values ($1, $2, current_timestamp)`
+
+	_, err := r.querier.Exec(ctx, query, req.ID, req.Name)
+
+	return errors.Wrap(err, "could not create company")
+}
+
+func (r *repo) Update(ctx context.Context, req models.UpdateCompanyRequest) error {
+	query := `update company set name = $2, updated_at = current_timestamp where id = $1 and deleted_at is null`
+
+	result, err := r.querier.Exec(ctx, query, req.ID, req.Name)
+	if err != nil {
+		return errors.Wrap(err, "could not update company")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affectedRows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *repo) Delete(ctx context.Context, id string) error {
+	query := `update company set deleted_at = current_timestamp where id = $1 and deleted_at is null`
+
+	result, err := r.querier.Exec(ctx, query, id)
+	if err != nil {
+		return errors.Wrap(err, "could not delete company")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affectedRows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *repo) Get(ctx context.Context, id string) (models.GetCompanyResponse, error) {
+	response, err := r.find(ctx, `WHERE c.id = :id AND c.deleted_at is null`, types.M{
+		"id":     id,
+		"offset": 0,
+		"limit":  1,
+	})
+	if err != nil {
+		return models.GetCompanyResponse{}, err
+	}
+
+	if len(response.Companies) == 0 {
+		return models.GetCompanyResponse{}, models.ErrNotFound
+	}
+
+	return response.Companies[0], nil
+}
+
+func (r *repo) GetAll(ctx context.Context, req models.GetAllCompaniesRequest) (models.GetAllCompaniesResponse, error) {
+	var (
+		statement = `WHERE c.deleted_at is null`
+		params    = make(types.M)
+	)
+
+	if !helpers.IsEmpty(req.Search) {
+		params["search"] = req.Search
+		statement += ` AND c.name ilike '%' || :search || '%'`
+	}
+
+	params["offset"], params["limit"] = helpers.NormalizePagination(req.Page, req.Limit)
+	return r.find(ctx, statement, params)
+}
+
+func (r *repo) find(ctx context.Context, statement string, params types.M) (models.GetAllCompaniesResponse, error) {
+	var response models.GetAllCompaniesResponse
+
+	tenant := helpers.GetTenant(ctx)
+
+	statement += ` AND (:tenant_all OR c.id = :tenant_id)`
+	params["tenant_all"], params["tenant_id"] = tenant.All, helpers.ToNullString(tenant.CompanyID)
+
+	queryCount := `
+		SELECT
+			count(1)
+		FROM company c
+	` + statement
+
+	stmtCount, err := r.querier.PrepareNamed(ctx, queryCount)
+	if err != nil {
+		return response, errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmtCount.Close()
+
+	if err = stmtCount.QueryRow(params).Scan(&response.Count); err != nil {
+		return response, helpers.ToCustomError(err)
+	}
+
+	query := `
+		SELECT
+			c.id,
+			c.name,
+			c.created_at,
+			c.updated_at
+		FROM company c
+	` + statement + `
+		ORDER BY c.name
+		OFFSET :offset LIMIT :limit
+	`
+
+	stmt, err := r.querier.PrepareNamed(ctx, query)
+	if err != nil {
+		return response, errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(params)
+	if err != nil {
+		return response, errors.Wrap(err, "could not query with params")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			company   models.GetCompanyResponse
+			createdAt sql.NullTime
+			updatedAt sql.NullTime
+		)
+
+		if err = rows.Scan(
+			&company.ID,
+			&company.Name,
+			&createdAt,
+			&updatedAt,
+		); err != nil {
+			return response, errors.Wrap(err, "could not scan rows")
+		}
+
+		company.CreatedAt = helpers.TimeToString(createdAt.Time, config.DateTimeFormat, createdAt.Valid)
+		company.UpdatedAt = helpers.TimeToString(updatedAt.Time, config.DateTimeFormat, updatedAt.Valid)
+
+		response.Companies = append(response.Companies, company)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,193 @@
+package dashboard_repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	"github.com/abdivasiyev/project_template/internal/repository/postgres/car_repo"
+	"github.com/abdivasiyev/project_template/internal/repository/postgres/step_repo"
+	"github.com/abdivasiyev/project_template/pkg/helpers"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/storage"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+)
+
+var Module = fx.Provide(New)
+
+type repo struct {
+	querier storage.Querier
+	log     logger.Logger
+}
+
+type Params struct {
+	fx.In
+	Querier storage.Querier
+	Log     logger.Logger
+}
+
+func New(params Params) repository.Dashboard {
+	return &repo{
+		querier: params.Querier,
+		log:     params.Log,
+	}
+}
+
+// Get counts drivers, trucks, trailers and cars of tenant in one query,
+// growth compares rows created since periodStart with rows created in previous period of the same length.
+// Truck or trailer is vacant without driver, assigned when its driver has no pair vehicle and full
+// when its driver drives both truck and trailer
+func (r *repo) Get(ctx context.Context, periodStart, previousPeriodStart time.Time) (models.GetDashboardResponse, error) {
+	var (
+		response                        models.GetDashboardResponse
+		driverCurrent, driverPrevious   int
+		truckCurrent, truckPrevious     int
+		trailerCurrent, trailerPrevious int
+		carCurrent, carPrevious         int
+		tenant                          = helpers.GetTenant(ctx)
+	)
+
+	query := `
+		with drivers as (
+			select d.created_at, s.alias
+			from driver d
+			left join status s on s.id = d.status_id
+			where d.deleted_at is null and ($1 or d.company_id is not distinct from $2)
+		), trucks as (
+			select
+				t.created_at,
+				da.driver_id,
+				exists(
+					select 1 from driver_assignment p
+					join trailer tr on tr.id = p.trailer_id and tr.deleted_at is null
+					where p.driver_id = da.driver_id and p.unassigned_at is null
+				) as has_pair
+			from truck t
+			left join driver_assignment da on da.truck_id = t.id and da.unassigned_at is null
+			where t.deleted_at is null and ($1 or t.company_id is not distinct from $2)
+		), trailers as (
+			select
+				tr.created_at,
+				da.driver_id,
+				exists(
+					select 1 from driver_assignment p
+					join truck t on t.id = p.truck_id and t.deleted_at is null
+					where p.driver_id = da.driver_id and p.unassigned_at is null
+				) as has_pair
+			from trailer tr
+			left join driver_assignment da on da.trailer_id = tr.id and da.unassigned_at is null
+			where tr.deleted_at is null and ($1 or tr.company_id is not distinct from $2)
+		), cars as (
+			select c.created_at, s.alias
+			from car c
+			left join status s on s.id = c.status_id
+			where c.deleted_at is null and ($1 or c.company_id is not distinct from $2)
+		)
+		select
+			d.total, d.current, d.previous, d.pending, d.in_process, d.active,
+			t.total, t.current, t.previous, t.vacant, t.assigned, t.full_rig,
+			tr.total, tr.current, tr.previous, tr.vacant, tr.assigned, tr.full_rig,
+			c.total, c.current, c.previous, c.booked, c.available
+		from (
+			select
+				count(1) as total,
+				count(1) filter (where created_at >= $3) as current,
+				count(1) filter (where created_at >= $4 and created_at < $3) as previous,
+				count(1) filter (where alias = $5) as pending,
+				count(1) filter (where alias = $6) as in_process,
+				count(1) filter (where alias = $7) as active
+			from drivers
+		) d
+		cross join (
+			select
+				count(1) as total,
+				count(1) filter (where created_at >= $3) as current,
+				count(1) filter (where created_at >= $4 and created_at < $3) as previous,
+				count(1) filter (where driver_id is null) as vacant,
+				count(1) filter (where driver_id is not null and not has_pair) as assigned,
+				count(1) filter (where driver_id is not null and has_pair) as full_rig
+			from trucks
+		) t
+		cross join (
+			select
+				count(1) as total,
+				count(1) filter (where created_at >= $3) as current,
+				count(1) filter (where created_at >= $4 and created_at < $3) as previous,
+				count(1) filter (where driver_id is null) as vacant,
+				count(1) filter (where driver_id is not null and not has_pair) as assigned,
+				count(1) filter (where driver_id is not null and has_pair) as full_rig
+			from trailers
+		) tr
+		cross join (
+			select
+				count(1) as total,
+				count(1) filter (where created_at >= $3) as current,
+				count(1) filter (where created_at >= $4 and created_at < $3) as previous,
+				count(1) filter (where alias = $8) as booked,
+				count(1) filter (where alias is distinct from $8) as available
+			from cars
+		) c
+	`
+
+	err := r.querier.QueryRow(
+		ctx,
+		query,
+		tenant.All,
+		helpers.ToNullString(tenant.CompanyID),
+		periodStart,
+		previousPeriodStart,
+		step_repo.DriverStatusPending,
+		step_repo.DriverStatusInProcess,
+		step_repo.DriverStatusActive,
+		car_repo.StatusBooked,
+	).Scan(
+		&response.DriverStatistics.Total,
+		&driverCurrent,
+		&driverPrevious,
+		&response.DriverStatistics.Pending,
+		&response.DriverStatistics.InProcess,
+		&response.DriverStatistics.Active,
+		&response.TruckStatistics.Total,
+		&truckCurrent,
+		&truckPrevious,
+		&response.TruckStatistics.Vacant,
+		&response.TruckStatistics.Assigned,
+		&response.TruckStatistics.Full,
+		&response.TrailerStatistics.Total,
+		&trailerCurrent,
+		&trailerPrevious,
+		&response.TrailerStatistics.Vacant,
+		&response.TrailerStatistics.Assigned,
+		&response.TrailerStatistics.Full,
+		&response.CarStatistics.Total,
+		&carCurrent,
+		&carPrevious,
+		&response.CarStatistics.Booked,
+		&response.CarStatistics.Available,
+	)
+	if err != nil {
+		return response, errors.Wrap(err, "could not get dashboard statistics")
+	}
+
+	response.DriverStatistics.Growth = growth(driverCurrent, driverPrevious)
+	response.TruckStatistics.Growth = growth(truckCurrent, truckPrevious)
+	response.TrailerStatistics.Growth = growth(trailerCurrent, trailerPrevious)
+	response.CarStatistics.Growth = growth(carCurrent, carPrevious)
+
+	return response, nil
+}
+
+// growth returns change of current period against previous one in percents
+func growth(current, previous int) string {
+	if previous == 0 {
+		if current == 0 {
+			return "0%"
+		}
+		return "+100%"
+	}
+
+	return fmt.Sprintf("%+.2f%%", float64(current-previous)*100/float64(previous))
+}
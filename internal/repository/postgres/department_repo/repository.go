@@ -0,0 +1,374 @@
+package department_repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/abdivasiyev/project_template/config"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	"github.com/abdivasiyev/project_template/internal/types"
+	"github.com/abdivasiyev/project_template/pkg/helpers"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/storage"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+)
+
+var Module = fx.Provide(New)
+
+type repo struct {
+	querier storage.Querier
+	log     logger.Logger
+}
+
+type Params struct {
+	fx.In
+	Querier storage.Querier
+	Log     logger.Logger
+}
+
+func New(params Params) repository.Department {
+	return &repo{
+		querier: params.Querier,
+		log:     params.Log,
+	}
+}
+
+// Create inserts department into requested position and shifts next departments,
+// returns models.ErrConflict when alias is already taken
+func (r *repo) Create(ctx context.Context, req models.CreateDepartmentRequest) error {
+	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+
+	if err = checkAlias(tx, req.ID, req.Alias); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	query := `
+		insert into department (id, alias, name, sequence, start_color, end_color, created_at)
+		values ($1, $2, $3, $4, $5, $6, current_timestamp)
+	`
+
+	if _, err = tx.Exec(
+		query,
+		req.ID,
+		req.Alias,
+		req.Name,
+		req.Sequence,
+		req.Gradient.StartColor,
+		req.Gradient.EndColor,
+	); err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not create department")
+	}
+
+	if err = move(tx, req.ID, req.Sequence); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Update changes department and moves it into requested position,
+// returns models.ErrConflict when alias is already taken
+func (r *repo) Update(ctx context.Context, req models.CreateDepartmentRequest) error {
+	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+
+	if err = checkAlias(tx, req.ID, req.Alias); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	query := `
+		update department set
+			alias = $2,
+			name = $3,
+			start_color = $4,
+			end_color = $5,
+			updated_at = current_timestamp
+		where id = $1 and deleted_at is null
+	`
+
+	result, err := tx.Exec(
+		query,
+		req.ID,
+		req.Alias,
+		req.Name,
+		req.Gradient.StartColor,
+		req.Gradient.EndColor,
+	)
+	if err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not update department")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if affectedRows == 0 {
+		_ = tx.Rollback()
+		return models.ErrNotFound
+	}
+
+	if err = move(tx, req.ID, req.Sequence); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete removes department and closes the gap in sequences
+func (r *repo) Delete(ctx context.Context, id string) error {
+	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+
+	query := `update department set deleted_at = current_timestamp where id = $1 and deleted_at is null`
+
+	result, err := tx.Exec(query, id)
+	if err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not delete department")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if affectedRows == 0 {
+		_ = tx.Rollback()
+		return models.ErrNotFound
+	}
+
+	if err = reorder(tx, nil); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Reorder places given departments first in given order, other departments keep their order after them,
+// returns models.ErrNotFound when any of departments does not exist
+func (r *repo) Reorder(ctx context.Context, ids []string) error {
+	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+
+	var count int
+
+	query := `select count(1) from department where id = any($1::uuid[]) and deleted_at is null`
+
+	if err = tx.QueryRow(query, pq.Array(ids)).Scan(&count); err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not count departments")
+	}
+
+	if count != len(ids) {
+		_ = tx.Rollback()
+		return models.ErrNotFound
+	}
+
+	if err = reorder(tx, ids); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *repo) Get(ctx context.Context, id string) (models.GetDepartmentResponse, error) {
+	response, err := r.find(ctx, `WHERE d.id = :id AND d.deleted_at is null`, types.M{
+		"id":     id,
+		"offset": 0,
+		"limit":  1,
+	})
+	if err != nil {
+		return models.GetDepartmentResponse{}, err
+	}
+
+	if len(response.Departments) == 0 {
+		return models.GetDepartmentResponse{}, models.ErrNotFound
+	}
+
+	return response.Departments[0], nil
+}
+
+func (r *repo) GetAll(ctx context.Context, req models.GetAllDepartmentsRequest) (models.GetAllDepartmentsResponse, error) {
+	var (
+		statement = `WHERE d.deleted_at is null`
+		params    = make(types.M)
+	)
+
+	if !helpers.IsEmpty(req.Search) {
+		params["search"] = req.Search
+
+		statement += ` AND (
+			d.alias ilike '%' || :search || '%' OR
+			d.name ilike '%' || :search || '%'
+		)`
+	}
+
+	params["offset"], params["limit"] = helpers.NormalizePagination(req.Page, req.Limit)
+	return r.find(ctx, statement, params)
+}
+
+func (r *repo) find(ctx context.Context, statement string, params types.M) (models.GetAllDepartmentsResponse, error) {
+	var response models.GetAllDepartmentsResponse
+
+	queryCount := `
+		SELECT
+			count(1)
+		FROM department d
+	` + statement
+
+	stmtCount, err := r.querier.PrepareNamed(ctx, queryCount)
+	if err != nil {
+		return response, errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmtCount.Close()
+
+	if err = stmtCount.QueryRow(params).Scan(&response.Count); err != nil {
+		return response, helpers.ToCustomError(err)
+	}
+
+	query := `
+		SELECT
+			d.id,
+			d.alias,
+			d.name,
+			d.sequence,
+			d.start_color,
+			d.end_color,
+			d.created_at,
+			d.updated_at
+		FROM department d
+	` + statement + `
+		ORDER BY d.sequence
+		OFFSET :offset LIMIT :limit
+	`
+
+	stmt, err := r.querier.PrepareNamed(ctx, query)
+	if err != nil {
+		return response, errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(params)
+	if err != nil {
+		return response, errors.Wrap(err, "could not query with params")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			department models.GetDepartmentResponse
+			createdAt  sql.NullTime
+			updatedAt  sql.NullTime
+		)
+
+		if err = rows.Scan(
+			&department.ID,
+			&department.Alias,
+			&department.Name,
+			&department.Sequence,
+			&department.Gradient.StartColor,
+			&department.Gradient.EndColor,
+			&createdAt,
+			&updatedAt,
+		); err != nil {
+			return response, errors.Wrap(err, "could not scan rows")
+		}
+
+		department.CreatedAt = helpers.TimeToString(createdAt.Time, config.DateTimeFormat, createdAt.Valid)
+		department.UpdatedAt = helpers.TimeToString(updatedAt.Time, config.DateTimeFormat, updatedAt.Valid)
+
+		response.Departments = append(response.Departments, department)
+	}
+
+	return response, nil
+}
+
+func checkAlias(tx *sqlx.Tx, id, alias string) error {
+	var count int
+
+	query := `select count(1) from department where alias = $1 and id <> $2 and deleted_at is null`
+
+	if err := tx.QueryRow(query, alias, id).Scan(&count); err != nil {
+		return errors.Wrap(err, "could not check department alias")
+	}
+
+	if count > 0 {
+		return models.ErrConflict
+	}
+
+	return nil
+}
+
+// move places department into given position and renumbers others without gaps,
+// position greater than number of departments puts department to the end
+func move(tx *sqlx.Tx, id string, position int) error {
+	query := `
+		update department d set
+			sequence = case when o.position < $2 then o.position else o.position + 1 end
+		from (
+			select id, row_number() over (order by sequence, created_at) as position
+			from department
+			where deleted_at is null and id <> $1
+		) o
+		where d.id = o.id
+	`
+
+	if _, err := tx.Exec(query, id, position); err != nil {
+		return errors.Wrap(err, "could not shift departments")
+	}
+
+	query = `
+		update department set
+			sequence = least($2, (select count(1) from department where deleted_at is null))
+		where id = $1
+	`
+
+	if _, err := tx.Exec(query, id, position); err != nil {
+		return errors.Wrap(err, "could not move department")
+	}
+
+	return nil
+}
+
+// reorder renumbers departments from one without gaps, given departments go first
+func reorder(tx *sqlx.Tx, ids []string) error {
+	query := `
+		update department d set
+			sequence = o.position,
+			updated_at = current_timestamp
+		from (
+			select id, row_number() over (order by array_position($1::uuid[], id) nulls last, sequence, created_at) as position
+			from department
+			where deleted_at is null
+		) o
+		where d.id = o.id and d.sequence <> o.position
+	`
+
+	_, err := tx.Exec(query, pq.Array(ids))
+
+	return errors.Wrap(err, "could not reorder departments")
+}
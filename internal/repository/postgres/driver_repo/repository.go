@@ -0,0 +1,359 @@
+package driver_repo
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"time"
+
+	"github.com/abdivasiyev/project_template/config"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	"github.com/abdivasiyev/project_template/internal/types"
+	"github.com/abdivasiyev/project_template/pkg/helpers"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/storage"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+)
+
+const StepStatusCompleted = "completed"
+
+var Module = fx.Provide(New)
+
+type repo struct {
+	querier storage.Querier
+	log     logger.Logger
+}
+
+type Params struct {
+	fx.In
+	Querier storage.Querier
+	Log     logger.Logger
+}
+
+func New(params Params) repository.Driver {
+	return &repo{
+		querier: params.Querier,
+		log:     params.Log,
+	}
+}
+
+func (r *repo) Create(ctx context.Context, req models.CreateDriverRequest) error {
+	tenant := helpers.GetTenant(ctx)
+
+	query := `
+		insert into driver (id, first_name, last_name, email, phone, image_id, status_id, company_id, created_at)
+		values (
+			$1, $2, $3, $4, $5, $6,
+			(select id from status where entity_type = 'driver' and deleted_at is null order by sequence limit 1),
+			$7,
+			current_timestamp
+		)
+	`
+
+	_, err := r.querier.Exec(
+		ctx,
+		query,
+		req.ID,
+		req.FirstName,
+		req.LastName,
+		req.Email,
+		req.Phone,
+		helpers.ToNullString(req.ImageID),
+		helpers.ToNullString(tenant.CompanyID),
+	)
+
+	return errors.Wrap(helpers.ToCustomError(err), "could not create driver")
+}
+
+func (r *repo) Update(ctx context.Context, req models.CreateDriverRequest) error {
+	tenant := helpers.GetTenant(ctx)
+
+	query := `
+		update driver set
+			first_name = $2,
+			last_name = $3,
+			email = $4,
+			phone = $5,
+			image_id = $6,
+			updated_at = current_timestamp
+		where id = $1 and deleted_at is null
+		  and ($7 or company_id is not distinct from $8)
+	`
+
+	result, err := r.querier.Exec(
+		ctx,
+		query,
+		req.ID,
+		req.FirstName,
+		req.LastName,
+		req.Email,
+		req.Phone,
+		helpers.ToNullString(req.ImageID),
+		tenant.All,
+		helpers.ToNullString(tenant.CompanyID),
+	)
+	if err != nil {
+		return errors.Wrap(err, "could not update driver")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affectedRows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *repo) Get(ctx context.Context, id string) (models.GetDriverResponse, error) {
+	response, err := r.find(ctx, `WHERE d.id = :id AND d.deleted_at is null`, types.M{
+		"id":     id,
+		"offset": 0,
+		"limit":  1,
+	})
+	if err != nil {
+		return models.GetDriverResponse{}, err
+	}
+
+	if len(response.Drivers) == 0 {
+		return models.GetDriverResponse{}, models.ErrNotFound
+	}
+
+	return response.Drivers[0], nil
+}
+
+func (r *repo) GetAll(ctx context.Context, req models.GetAllDriversRequest) (models.GetAllDriversResponse, error) {
+	var (
+		statement = `WHERE d.deleted_at is null`
+		params    = make(types.M)
+	)
+
+	if !helpers.IsEmpty(req.Search) {
+		params["search"] = req.Search
+
+		statement += ` AND (
+			(d.first_name || ' ' || d.last_name) ilike '%' || :search || '%' OR
+			d.email ilike '%' || :search || '%' OR
+			d.phone ilike '%' || :search || '%'
+		)`
+	}
+
+	params["offset"], params["limit"] = helpers.NormalizePagination(req.Page, req.Limit)
+	return r.find(ctx, statement, params)
+}
+
+func (r *repo) find(ctx context.Context, statement string, params types.M) (models.GetAllDriversResponse, error) {
+	var response models.GetAllDriversResponse
+
+	tenant := helpers.GetTenant(ctx)
+
+	statement += ` AND (:tenant_all OR d.company_id IS NOT DISTINCT FROM :tenant_id)`
+	params["tenant_all"], params["tenant_id"] = tenant.All, helpers.ToNullString(tenant.CompanyID)
+
+	queryCount := `
+		SELECT
+			count(1)
+		FROM driver d
+	` + statement
+
+	stmtCount, err := r.querier.PrepareNamed(ctx, queryCount)
+	if err != nil {
+		return response, errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmtCount.Close()
+
+	if err = stmtCount.QueryRow(params).Scan(&response.Count); err != nil {
+		return response, helpers.ToCustomError(err)
+	}
+
+	query := `
+		SELECT
+			d.id,
+			d.first_name,
+			d.last_name,
+			d.email,
+			d.phone,
+			f.id,
+			f.name,
+			f.url,
+			d.on_board_date,
+			d.created_at,
+			d.updated_at,
+			s.id,
+			s.alias,
+			s.name,
+			s.sequence,
+			s.color,
+			t.id,
+			t.number,
+			tr.id,
+			tr.number
+		FROM driver d
+		LEFT JOIN file f ON f.id = d.image_id AND f.deleted_at is null
+		LEFT JOIN status s ON s.id = d.status_id
+		LEFT JOIN driver_assignment dat ON dat.driver_id = d.id AND dat.truck_id IS NOT NULL AND dat.unassigned_at is null
+		LEFT JOIN truck t ON t.id = dat.truck_id AND t.deleted_at is null
+		LEFT JOIN driver_assignment datr ON datr.driver_id = d.id AND datr.trailer_id IS NOT NULL AND datr.unassigned_at is null
+		LEFT JOIN trailer tr ON tr.id = datr.trailer_id AND tr.deleted_at is null
+	` + statement + `
+		ORDER BY d.created_at DESC
+		OFFSET :offset LIMIT :limit
+	`
+
+	stmt, err := r.querier.PrepareNamed(ctx, query)
+	if err != nil {
+		return response, errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(params)
+	if err != nil {
+		return response, errors.Wrap(err, "could not query with params")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			driver                            models.GetDriverResponse
+			createdAt                         time.Time
+			updatedAt, onBoardDate            sql.NullTime
+			imageID, imageName, imageURL      sql.NullString
+			statusID, statusAlias, statusName sql.NullString
+			statusColor, truckID, truckNumber sql.NullString
+			trailerID, trailerNumber          sql.NullString
+			statusSequence                    sql.NullInt64
+		)
+
+		if err = rows.Scan(
+			&driver.ID,
+			&driver.FirstName,
+			&driver.LastName,
+			&driver.Email,
+			&driver.Phone,
+			&imageID,
+			&imageName,
+			&imageURL,
+			&onBoardDate,
+			&createdAt,
+			&updatedAt,
+			&statusID,
+			&statusAlias,
+			&statusName,
+			&statusSequence,
+			&statusColor,
+			&truckID,
+			&truckNumber,
+			&trailerID,
+			&trailerNumber,
+		); err != nil {
+			return response, errors.Wrap(err, "could not scan rows")
+		}
+
+		driver.Image = models.GetFileResponse{
+			FileID:   imageID.String,
+			FileName: imageName.String,
+			FileURL:  imageURL.String,
+		}
+		driver.OnBoardDate = helpers.TimeToString(onBoardDate.Time, config.DateFormat, onBoardDate.Valid)
+		driver.CreatedAt = helpers.TimeToString(createdAt, config.DateFormat, true)
+		driver.UpdatedAt = helpers.TimeToString(updatedAt.Time, config.DateFormat, updatedAt.Valid)
+		driver.Status = models.GetStatusResponse{
+			ID:       statusID.String,
+			Alias:    statusAlias.String,
+			Name:     statusName.String,
+			Sequence: int(statusSequence.Int64),
+			Color:    statusColor.String,
+		}
+
+		if truckID.Valid {
+			driver.AssignedTruck = &models.AssignedTruckResponse{
+				ID:     truckID.String,
+				Number: truckNumber.String,
+			}
+		}
+
+		if trailerID.Valid {
+			driver.AssignedTrailer = &models.AssignedTrailerResponse{
+				ID:     trailerID.String,
+				Number: trailerNumber.String,
+			}
+		}
+
+		response.Drivers = append(response.Drivers, driver)
+	}
+
+	return response, nil
+}
+
+// GetDepartmentStatistics returns completion percentage of every department for each driver,
+// percentage is share of completed steps among all steps of department
+func (r *repo) GetDepartmentStatistics(ctx context.Context, driverIDs []string) (map[string][]models.DepartmentStatisticResponse, error) {
+	var response = make(map[string][]models.DepartmentStatisticResponse, len(driverIDs))
+
+	if len(driverIDs) == 0 {
+		return response, nil
+	}
+
+	query := `
+		select
+			dr.id,
+			dp.id,
+			dp.alias,
+			dp.name,
+			dp.sequence,
+			dp.start_color,
+			dp.end_color,
+			count(st.id),
+			count(st.id) filter (where s.alias = $2)
+		from unnest($1::uuid[]) as dr(id)
+		cross join department dp
+		left join step st on st.department_id = dp.id and st.deleted_at is null
+		left join driver_step ds on ds.step_id = st.id and ds.driver_id = dr.id
+		left join status s on s.id = ds.status_id
+		where dp.deleted_at is null
+		group by dr.id, dp.id
+		order by dp.sequence
+	`
+
+	rows, err := r.querier.Query(ctx, query, pq.Array(driverIDs), StepStatusCompleted)
+	if err != nil {
+		return response, errors.Wrap(err, "could not query department statistics")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			driverID         string
+			statistic        models.DepartmentStatisticResponse
+			total, completed int
+		)
+
+		if err = rows.Scan(
+			&driverID,
+			&statistic.ID,
+			&statistic.Alias,
+			&statistic.Name,
+			&statistic.Sequence,
+			&statistic.Gradient.StartColor,
+			&statistic.Gradient.EndColor,
+			&total,
+			&completed,
+		); err != nil {
+			return response, errors.Wrap(err, "could not scan rows")
+		}
+
+		if total > 0 {
+			statistic.Percentage = math.Round(float64(completed)*10000/float64(total)) / 100
+		}
+
+		response[driverID] = append(response[driverID], statistic)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,253 @@
+package entity_repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	"github.com/abdivasiyev/project_template/internal/repository/postgres/user_repo"
+	"github.com/abdivasiyev/project_template/internal/types"
+	"github.com/abdivasiyev/project_template/pkg/helpers"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/storage"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+)
+
+// Source describes how id/name pairs of entity type are selected, main table must be aliased as e
+type Source struct {
+	// From is from clause with joins
+	From string
+	// Name is expression of entity name, it is also used for search and ordering
+	Name string
+	// Where is condition of active rows
+	Where string
+	// TenantColumn is column compared with company of tenant, empty column means entity is shared by all companies
+	TenantColumn string
+	// Shared makes rows without company visible to all companies
+	Shared bool
+	// CanAddItem allows users to create new lookup values of entity type inline
+	CanAddItem bool
+}
+
+var Module = fx.Provide(New)
+
+type repo struct {
+	querier storage.Querier
+	log     logger.Logger
+	sources map[string]Source
+}
+
+type Params struct {
+	fx.In
+	Querier storage.Querier
+	Log     logger.Logger
+}
+
+func New(params Params) repository.Entity {
+	return &repo{
+		querier: params.Querier,
+		log:     params.Log,
+		sources: newSources(),
+	}
+}
+
+// newSources returns sources of entity types supported by lookup, new entity types are plugged in here
+func newSources() map[string]Source {
+	sources := map[string]Source{
+		"company": {
+			From:         `company e`,
+			Name:         `e.name`,
+			Where:        `e.deleted_at is null`,
+			TenantColumn: `e.id`,
+		},
+		"truck": {
+			From:         `truck e`,
+			Name:         `e.number`,
+			Where:        `e.deleted_at is null`,
+			TenantColumn: `e.company_id`,
+		},
+		"trailer": {
+			From:         `trailer e`,
+			Name:         `e.number`,
+			Where:        `e.deleted_at is null`,
+			TenantColumn: `e.company_id`,
+		},
+		"driver": {
+			From:         `driver e`,
+			Name:         `e.first_name || ' ' || e.last_name`,
+			Where:        `e.deleted_at is null`,
+			TenantColumn: `e.company_id`,
+		},
+	}
+
+	for entityType, roleAlias := range map[string]string{
+		"recruiter": user_repo.RoleHR,
+		"safety":    user_repo.RoleSafety,
+	} {
+		sources[entityType] = Source{
+			From:         `"user" e join user_role ur on ur.user_id = e.id join role ro on ro.id = ur.role_id`,
+			Name:         `coalesce(nullif(trim(concat_ws(' ', e.first_name, e.last_name)), ''), e.username)`,
+			Where:        fmt.Sprintf(`e.deleted_at is null and ro.alias = '%s'`, roleAlias),
+			TenantColumn: `e.company_id`,
+		}
+	}
+
+	for _, entityType := range []string{
+		"driver_type",
+		"drug_test_type",
+		"fuel_card_status",
+		"road_test_status",
+		"incab_devices",
+		"external_devices",
+		"need_repair_devices",
+	} {
+		sources[entityType] = lookupSource(entityType)
+	}
+
+	return sources
+}
+
+// lookupSource returns source of entity type which values are kept in lookup_value table and can be added by users,
+// values without company are shared by all companies
+func lookupSource(entityType string) Source {
+	return Source{
+		From:         `lookup_value e`,
+		Name:         `e.name`,
+		Where:        fmt.Sprintf(`e.deleted_at is null and e.entity_type = '%s'`, entityType),
+		TenantColumn: `e.company_id`,
+		Shared:       true,
+		CanAddItem:   true,
+	}
+}
+
+// CanAddItem returns whether users can add values of entity type, returns models.ErrNotFound for unknown entity type
+func (r *repo) CanAddItem(_ context.Context, entityType string) (bool, error) {
+	source, ok := r.sources[entityType]
+	if !ok {
+		return false, models.ErrNotFound
+	}
+
+	return source.CanAddItem, nil
+}
+
+// Create adds lookup value to company of tenant, values added outside of company are shared by all companies,
+// returns models.ErrConflict when value with the same name is visible to tenant
+func (r *repo) Create(ctx context.Context, req models.CreateEntityRequest) error {
+	var (
+		count     int
+		companyID = helpers.ToNullString(helpers.GetTenant(ctx).CompanyID)
+	)
+
+	// shared values are not covered by unique index of company values, so they are checked here
+	query := `
+		select count(1) from lookup_value
+		where entity_type = $1 and lower(name) = lower($2) and deleted_at is null
+		  and ($3::uuid is null or company_id is null or company_id = $3)
+	`
+
+	if err := r.querier.QueryRow(ctx, query, req.EntityType, req.Name, companyID).Scan(&count); err != nil {
+		return errors.Wrap(err, "could not check lookup value")
+	}
+
+	if count > 0 {
+		return models.ErrConflict
+	}
+
+	query = `
+		insert into lookup_value (id, company_id, entity_type, name, created_by, created_at)
+		values ($1, $2, $3, $4, $5, current_timestamp)
+	`
+
+	_, err := r.querier.Exec(ctx, query, req.ID, companyID, req.EntityType, req.Name, helpers.ToNullString(req.CreatedBy))
+
+	// value created by parallel request after the check is rejected by unique index
+	return helpers.ToCustomError(errors.Wrap(err, "could not create lookup value"))
+}
+
+// GetAll returns id/name pairs of entity type, returns models.ErrNotFound for unknown entity type
+func (r *repo) GetAll(ctx context.Context, req models.GetEntitiesRequest) (models.GetEntitiesResponse, error) {
+	var response models.GetEntitiesResponse
+
+	source, ok := r.sources[req.EntityType]
+	if !ok {
+		return response, models.ErrNotFound
+	}
+
+	response.CanAddItem = source.CanAddItem
+
+	var (
+		tenant    = helpers.GetTenant(ctx)
+		statement = `WHERE ` + source.Where
+		params    = make(types.M)
+	)
+
+	if !helpers.IsEmpty(source.TenantColumn) {
+		params["tenant_all"], params["tenant_id"] = tenant.All, helpers.ToNullString(tenant.CompanyID)
+
+		if source.Shared {
+			statement += ` AND (:tenant_all OR ` + source.TenantColumn + ` IS NULL OR ` + source.TenantColumn + ` = :tenant_id)`
+		} else {
+			statement += ` AND (:tenant_all OR ` + source.TenantColumn + ` IS NOT DISTINCT FROM :tenant_id)`
+		}
+	}
+
+	if !helpers.IsEmpty(req.Search) {
+		params["search"] = req.Search
+
+		statement += ` AND ` + source.Name + ` ilike '%' || :search || '%'`
+	}
+
+	params["offset"], params["limit"] = helpers.NormalizePagination(req.Page, req.Limit)
+
+	queryCount := `
+		SELECT
+			count(1)
+		FROM ` + source.From + `
+	` + statement
+
+	stmtCount, err := r.querier.PrepareNamed(ctx, queryCount)
+	if err != nil {
+		return response, errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmtCount.Close()
+
+	if err = stmtCount.QueryRow(params).Scan(&response.Count); err != nil {
+		return response, helpers.ToCustomError(err)
+	}
+
+	query := `
+		SELECT
+			e.id,
+			` + source.Name + `
+		FROM ` + source.From + `
+	` + statement + `
+		ORDER BY 2
+		OFFSET :offset LIMIT :limit
+	`
+
+	stmt, err := r.querier.PrepareNamed(ctx, query)
+	if err != nil {
+		return response, errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(params)
+	if err != nil {
+		return response, errors.Wrap(err, "could not query with params")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entity models.GetEntityResponse
+
+		if err = rows.Scan(&entity.ID, &entity.Name); err != nil {
+			return response, errors.Wrap(err, "could not scan rows")
+		}
+
+		response.Entities = append(response.Entities, entity)
+	}
+
+	return response, nil
+}
@@ -7,11 +7,66 @@ import (
 	"github.com/abdivasiyev/project_template/pkg/helpers"
 	"github.com/abdivasiyev/project_template/pkg/logger"
 	"github.com/abdivasiyev/project_template/pkg/storage"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
 	"go.uber.org/fx"
 )
 
 var Module = fx.Provide(New)
 
+// entityFilesQueries select category and file id pairs of every file attached to entity,
+// nested folders of category are separated by slash
+var entityFilesQueries = map[string]string{
+	"driver": `
+		select 'Profile', d.image_id::text
+		from driver d
+		where d.id = $1 and d.image_id is not null
+		union all
+		select replace(dp.name, '/', '-') || '/' || replace(st.name, '/', '-'), v.value
+		from form_field_value v
+		join form_field ff on ff.id = v.field_id and ff.type = 'file'
+		join step st on st.id = v.step_id
+		join department dp on dp.id = st.department_id
+		where v.driver_id = $1 and v.deleted_at is null
+	`,
+	"truck": `
+		select
+			'Inspections/' || ti.inspection_type || ' ' || to_char(ti.created_at, 'YYYY-MM-DD HH24-MI-SS') || '/' || c.name,
+			c.file_id::text
+		from truck_inspection ti
+		cross join lateral (
+			select 'Odometer', unnest(ti.odometer_images)
+			union all select 'Fuel level', unnest(ti.fuel_level_images)
+			union all select 'Driver side', unnest(ti.driver_side_images)
+			union all select 'Front side', unnest(ti.front_side_images)
+			union all select 'Passenger side', unnest(ti.passenger_side_images)
+			union all select 'Back side', unnest(ti.back_side_images)
+			union all select 'Tires', unnest(ti.tire_images)
+			union all select 'Damages', unnest(ti.damage_images)
+			union all select 'Signatures', unnest(array[ti.driver_signature_id, ti.company_representative_signature_id])
+		) c(name, file_id)
+		where ti.truck_id = $1
+	`,
+	"trailer": `
+		select
+			'Inspections/' || ti.inspection_type || ' ' || to_char(ti.created_at, 'YYYY-MM-DD HH24-MI-SS') || '/' || c.name,
+			c.file_id::text
+		from trailer_inspection ti
+		cross join lateral (
+			select 'Fuel level', unnest(ti.fuel_level_images)
+			union all select 'Left side', unnest(ti.left_side_images)
+			union all select 'Front side', unnest(ti.front_side_images)
+			union all select 'Right side', unnest(ti.right_side_images)
+			union all select 'Back side', unnest(ti.back_side_images)
+			union all select 'Inside', unnest(ti.in_side_images)
+			union all select 'Tires', unnest(ti.tire_images)
+			union all select 'Damages', unnest(ti.damage_images)
+			union all select 'Signatures', unnest(array[ti.driver_signature_id, ti.company_representative_signature_id])
+		) c(name, file_id)
+		where ti.trailer_id = $1
+	`,
+}
+
 type repo struct {
 	querier storage.Querier
 	log     logger.Logger
@@ -51,3 +106,96 @@ func (r *repo) Get(ctx context.Context, id string) (models.GetFileResponse, erro
 
 	return response, helpers.ToCustomError(err)
 }
+
+// FindMissing returns ids which have no matching file
+func (r *repo) FindMissing(ctx context.Context, ids []string) ([]string, error) {
+	var missing []string
+
+	query := `
+		select array(
+			select distinct i.id::text from unnest($1::uuid[]) as i(id)
+			where not exists (select 1 from file f where f.id = i.id and f.deleted_at is null)
+		)
+	`
+
+	err := r.querier.QueryRow(ctx, query, pq.Array(ids)).Scan(pq.Array(&missing))
+
+	return missing, helpers.ToCustomError(err)
+}
+
+func (r *repo) GetByIDs(ctx context.Context, ids []string) ([]models.GetFileResponse, error) {
+	var response []models.GetFileResponse
+
+	query := `select id, name, url from file where deleted_at is null and id = any($1::uuid[])`
+
+	rows, err := r.querier.Query(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not query files")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var file models.GetFileResponse
+
+		if err = rows.Scan(
+			&file.FileID,
+			&file.FileName,
+			&file.FileURL,
+		); err != nil {
+			return nil, errors.Wrap(err, "could not scan rows")
+		}
+
+		response = append(response, file)
+	}
+
+	return response, nil
+}
+
+// GetFilesForZipping returns stored names of entity files grouped by category,
+// entity is limited to tenant
+func (r *repo) GetFilesForZipping(ctx context.Context, entityType, entityID string) ([]models.FilesForZippingResponse, error) {
+	var response []models.FilesForZippingResponse
+
+	entityQuery, ok := entityFilesQueries[entityType]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+
+	tenant := helpers.GetTenant(ctx)
+
+	// entity type is checked above and matches name of entity table
+	query := `
+		select
+			e.category,
+			array_agg(distinct f.name)
+		from (` + entityQuery + `) as e(category, file_id)
+		join file f on f.id::text = e.file_id and f.deleted_at is null
+		where exists (
+			select 1 from ` + entityType + ` t
+			where t.id = $1 and ($2 or t.company_id is not distinct from $3)
+		)
+		group by e.category
+		order by e.category
+	`
+
+	rows, err := r.querier.Query(ctx, query, entityID, tenant.All, helpers.ToNullString(tenant.CompanyID))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not query files for zipping")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var files models.FilesForZippingResponse
+
+		if err = rows.Scan(
+			&files.CategoryName,
+			pq.Array(&files.FileNames),
+		); err != nil {
+			return nil, errors.Wrap(err, "could not scan rows")
+		}
+
+		response = append(response, files)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,987 @@
+package form_repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/abdivasiyev/project_template/config"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	"github.com/abdivasiyev/project_template/internal/types"
+	"github.com/abdivasiyev/project_template/pkg/helpers"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/storage"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+)
+
+var Module = fx.Provide(New)
+
+type repo struct {
+	querier storage.Querier
+	log     logger.Logger
+}
+
+type Params struct {
+	fx.In
+	Querier storage.Querier
+	Log     logger.Logger
+}
+
+func New(params Params) repository.Form {
+	return &repo{
+		querier: params.Querier,
+		log:     params.Log,
+	}
+}
+
+func (r *repo) Create(ctx context.Context, req models.CreateFormRequest) error {
+	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+
+	query := `insert into form (id, title, version, created_at) values ($1, $2, 1, current_timestamp)`
+
+	if _, err = tx.Exec(query, req.ID, req.Title); err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not create form")
+	}
+
+	if err = r.setGroups(tx, req.ID, req.Groups); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Update changes form title and groups, every update creates new version of form
+func (r *repo) Update(ctx context.Context, req models.CreateFormRequest) error {
+	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+
+	query := `
+		update form set
+			title = $2,
+			version = version + 1,
+			updated_at = current_timestamp
+		where id = $1 and deleted_at is null
+	`
+
+	result, err := tx.Exec(query, req.ID, req.Title)
+	if err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not update form")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if affectedRows == 0 {
+		_ = tx.Rollback()
+		return models.ErrNotFound
+	}
+
+	if _, err = tx.Exec(`delete from form_group_relation where form_id = $1`, req.ID); err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not delete form groups")
+	}
+
+	if err = r.setGroups(tx, req.ID, req.Groups); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// setGroups links groups to form, returns models.ErrNotFound when any of groups does not exist
+func (r *repo) setGroups(tx *sqlx.Tx, formID string, groups []string) error {
+	query := `
+		insert into form_group_relation (form_id, group_id)
+		select $1, g.id from form_group g
+		where g.id = any($2::uuid[]) and g.deleted_at is null
+		on conflict do nothing
+	`
+
+	result, err := tx.Exec(query, formID, pq.Array(groups))
+	if err != nil {
+		return errors.Wrap(err, "could not create form groups")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	unique := make(map[string]struct{}, len(groups))
+	for _, group := range groups {
+		unique[group] = struct{}{}
+	}
+
+	if int(affectedRows) != len(unique) {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *repo) Delete(ctx context.Context, id string) error {
+	query := `update form set deleted_at = current_timestamp where id = $1 and deleted_at is null`
+
+	result, err := r.querier.Exec(ctx, query, id)
+	if err != nil {
+		return errors.Wrap(err, "could not delete form")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affectedRows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *repo) Get(ctx context.Context, id string) (models.GetFormResponse, error) {
+	response, err := r.find(ctx, `WHERE f.id = :id AND f.deleted_at is null`, types.M{
+		"id":     id,
+		"offset": 0,
+		"limit":  1,
+	})
+	if err != nil {
+		return models.GetFormResponse{}, err
+	}
+
+	if len(response.Forms) == 0 {
+		return models.GetFormResponse{}, models.ErrNotFound
+	}
+
+	form := response.Forms[0]
+
+	groups, err := r.findGroups(ctx, `g.id in (select group_id from form_group_relation where form_id = $1)`, id)
+	if err != nil {
+		return models.GetFormResponse{}, err
+	}
+	form.Groups = groups
+
+	return form, nil
+}
+
+func (r *repo) GetAll(ctx context.Context, req models.GetAllFormRequest) (models.GetAllFormResponse, error) {
+	var (
+		statement = `WHERE f.deleted_at is null`
+		params    = make(types.M)
+	)
+
+	if !helpers.IsEmpty(req.Search) {
+		params["search"] = req.Search
+
+		statement += ` AND f.title ilike '%' || :search || '%'`
+	}
+
+	params["offset"], params["limit"] = helpers.NormalizePagination(req.Page, req.Limit)
+	return r.find(ctx, statement, params)
+}
+
+func (r *repo) find(ctx context.Context, statement string, params types.M) (models.GetAllFormResponse, error) {
+	var response models.GetAllFormResponse
+
+	queryCount := `
+		SELECT
+			count(1)
+		FROM form f
+	` + statement
+
+	stmtCount, err := r.querier.PrepareNamed(ctx, queryCount)
+	if err != nil {
+		return response, errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmtCount.Close()
+
+	if err = stmtCount.QueryRow(params).Scan(&response.Count); err != nil {
+		return response, helpers.ToCustomError(err)
+	}
+
+	query := `
+		SELECT
+			f.id,
+			f.title,
+			f.version
+		FROM form f
+	` + statement + `
+		ORDER BY f.created_at DESC
+		OFFSET :offset LIMIT :limit
+	`
+
+	stmt, err := r.querier.PrepareNamed(ctx, query)
+	if err != nil {
+		return response, errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(params)
+	if err != nil {
+		return response, errors.Wrap(err, "could not query with params")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var form models.GetFormResponse
+
+		if err = rows.Scan(
+			&form.ID,
+			&form.Title,
+			&form.Version,
+		); err != nil {
+			return response, errors.Wrap(err, "could not scan rows")
+		}
+
+		response.Forms = append(response.Forms, form)
+	}
+
+	return response, nil
+}
+
+// CreateVersion stores snapshot of form tree with its current version
+func (r *repo) CreateVersion(ctx context.Context, form models.GetFormResponse) error {
+	body, err := json.Marshal(form)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal form")
+	}
+
+	query := `
+		insert into form_version (form_id, version, body, created_at)
+		values ($1, $2, $3, current_timestamp)
+		on conflict (form_id, version) do update set body = excluded.body
+	`
+
+	_, err = r.querier.Exec(ctx, query, form.ID, form.Version, body)
+
+	return errors.Wrap(err, "could not create form version")
+}
+
+func (r *repo) GetVersion(ctx context.Context, id string, version int) (models.GetFormResponse, error) {
+	var (
+		response models.GetFormResponse
+		body     []byte
+	)
+
+	query := `
+		select v.body from form_version v
+		join form f on f.id = v.form_id and f.deleted_at is null
+		where v.form_id = $1 and v.version = $2
+	`
+
+	if err := r.querier.QueryRow(ctx, query, id, version).Scan(&body); err != nil {
+		return response, helpers.ToCustomError(err)
+	}
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		return response, errors.Wrap(err, "could not unmarshal form")
+	}
+
+	return response, nil
+}
+
+func (r *repo) GetVersions(ctx context.Context, id string) (models.GetAllFormVersionsResponse, error) {
+	var response models.GetAllFormVersionsResponse
+
+	query := `
+		select v.version, v.created_at from form_version v
+		join form f on f.id = v.form_id and f.deleted_at is null
+		where v.form_id = $1
+		order by v.version desc
+	`
+
+	rows, err := r.querier.Query(ctx, query, id)
+	if err != nil {
+		return response, errors.Wrap(err, "could not query form versions")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			version   models.GetFormVersionResponse
+			createdAt time.Time
+		)
+
+		if err = rows.Scan(&version.Version, &createdAt); err != nil {
+			return response, errors.Wrap(err, "could not scan rows")
+		}
+
+		version.CreatedAt = helpers.TimeToString(createdAt, config.DateTimeFormat, true)
+
+		response.Versions = append(response.Versions, version)
+	}
+
+	response.Count = len(response.Versions)
+
+	return response, nil
+}
+
+func (r *repo) CreateGroup(ctx context.Context, req models.CreateFormGroupRequest) error {
+	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+
+	query := `
+		insert into form_group (id, title, alias, sequence, created_at)
+		values ($1, $2, $3, $4, current_timestamp)
+	`
+
+	if _, err = tx.Exec(query, req.ID, req.Title, req.Alias, req.Sequence); err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not create form group")
+	}
+
+	if _, err = r.saveFields(tx, req.ID, "", req.Fields); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateGroup replaces field tree of group, fields missing in request are deleted.
+// Returns ids of forms which use this group, version of every such form is increased
+func (r *repo) UpdateGroup(ctx context.Context, req models.CreateFormGroupRequest) ([]string, error) {
+	var formIDs []string
+
+	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not begin transaction")
+	}
+
+	query := `
+		update form_group set
+			title = $2,
+			alias = $3,
+			sequence = $4,
+			updated_at = current_timestamp
+		where id = $1 and deleted_at is null
+	`
+
+	result, err := tx.Exec(query, req.ID, req.Title, req.Alias, req.Sequence)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, errors.Wrap(err, "could not update form group")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if affectedRows == 0 {
+		_ = tx.Rollback()
+		return nil, models.ErrNotFound
+	}
+
+	fieldIDs, err := r.saveFields(tx, req.ID, "", req.Fields)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	query = `
+		update form_field set deleted_at = current_timestamp
+		where group_id = $1 and deleted_at is null and not (id = any($2::uuid[]))
+	`
+
+	if _, err = tx.Exec(query, req.ID, pq.Array(fieldIDs)); err != nil {
+		_ = tx.Rollback()
+		return nil, errors.Wrap(err, "could not delete form fields")
+	}
+
+	query = `
+		update form set
+			version = version + 1,
+			updated_at = current_timestamp
+		where deleted_at is null and id in (select form_id from form_group_relation where group_id = $1)
+		returning id
+	`
+
+	rows, err := tx.Query(query, req.ID)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, errors.Wrap(err, "could not update form versions")
+	}
+
+	for rows.Next() {
+		var formID string
+
+		if err = rows.Scan(&formID); err != nil {
+			_ = rows.Close()
+			_ = tx.Rollback()
+			return nil, errors.Wrap(err, "could not scan rows")
+		}
+
+		formIDs = append(formIDs, formID)
+	}
+	_ = rows.Close()
+
+	return formIDs, tx.Commit()
+}
+
+// saveFields upserts field tree recursively and returns ids of all saved fields
+func (r *repo) saveFields(tx *sqlx.Tx, groupID, parentID string, fields []models.CreateFormFieldRequest) ([]string, error) {
+	var ids []string
+
+	query := `
+		insert into form_field (
+			id,
+			group_id,
+			parent_id,
+			label,
+			label_position,
+			icon,
+			alias,
+			hint,
+			warning,
+			sequence,
+			type,
+			validation,
+			multiple,
+			list_type,
+			can_search,
+			can_add_item,
+			can_open_popup,
+			grouped,
+			is_signature,
+			action,
+			created_at
+		) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, current_timestamp)
+		on conflict (id) do update set
+			group_id = excluded.group_id,
+			parent_id = excluded.parent_id,
+			label = excluded.label,
+			label_position = excluded.label_position,
+			icon = excluded.icon,
+			alias = excluded.alias,
+			hint = excluded.hint,
+			warning = excluded.warning,
+			sequence = excluded.sequence,
+			type = excluded.type,
+			validation = excluded.validation,
+			multiple = excluded.multiple,
+			list_type = excluded.list_type,
+			can_search = excluded.can_search,
+			can_add_item = excluded.can_add_item,
+			can_open_popup = excluded.can_open_popup,
+			grouped = excluded.grouped,
+			is_signature = excluded.is_signature,
+			action = excluded.action,
+			updated_at = current_timestamp,
+			deleted_at = null
+	`
+
+	for _, field := range fields {
+		if helpers.IsEmpty(field.ID) {
+			field.ID = uuid.New().String()
+		}
+
+		validation, err := json.Marshal(field.Validation)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not marshal field validation")
+		}
+
+		if _, err = tx.Exec(
+			query,
+			field.ID,
+			groupID,
+			helpers.ToNullString(parentID),
+			field.Label,
+			field.LabelPosition,
+			helpers.ToNullString(field.Icon),
+			helpers.ToNullString(field.Alias),
+			helpers.ToNullString(field.Hint),
+			helpers.ToNullString(field.Warning),
+			field.Sequence,
+			field.Type,
+			validation,
+			field.Multiple,
+			helpers.ToNullString(field.ListType),
+			field.CanSearch,
+			field.CanAddItem,
+			field.CanOpenPopup,
+			field.Grouped,
+			field.IsSignature,
+			helpers.ToNullString(field.Action),
+		); err != nil {
+			return nil, errors.Wrap(err, "could not save form field")
+		}
+
+		childIDs, err := r.saveFields(tx, groupID, field.ID, field.ChildFields)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, field.ID)
+		ids = append(ids, childIDs...)
+	}
+
+	return ids, nil
+}
+
+func (r *repo) GetGroup(ctx context.Context, id string) (models.GetFormGroupResponse, error) {
+	groups, err := r.findGroups(ctx, `g.id = $1`, id)
+	if err != nil {
+		return models.GetFormGroupResponse{}, err
+	}
+
+	if len(groups) == 0 {
+		return models.GetFormGroupResponse{}, models.ErrNotFound
+	}
+
+	return groups[0], nil
+}
+
+func (r *repo) findGroups(ctx context.Context, statement string, args ...any) ([]models.GetFormGroupResponse, error) {
+	var (
+		groups  []models.GetFormGroupResponse
+		indexes = make(map[string]int)
+	)
+
+	query := `
+		select
+			g.id,
+			g.title,
+			g.alias,
+			g.sequence
+		from form_group g
+		where g.deleted_at is null and ` + statement + `
+		order by g.sequence
+	`
+
+	rows, err := r.querier.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not query form groups")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var group models.GetFormGroupResponse
+
+		if err = rows.Scan(
+			&group.ID,
+			&group.Title,
+			&group.Alias,
+			&group.Sequence,
+		); err != nil {
+			return nil, errors.Wrap(err, "could not scan rows")
+		}
+
+		indexes[group.ID] = len(groups)
+		groups = append(groups, group)
+	}
+
+	if len(groups) == 0 {
+		return groups, nil
+	}
+
+	groupIDs := make([]string, 0, len(groups))
+	for _, group := range groups {
+		groupIDs = append(groupIDs, group.ID)
+	}
+
+	fields, err := r.findFields(ctx, groupIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for groupID, index := range indexes {
+		groups[index].Fields = buildFieldTree(fields, groupID)
+	}
+
+	return groups, nil
+}
+
+// findFields returns fields of groups keyed by parent field id, root fields are keyed by group id
+func (r *repo) findFields(ctx context.Context, groupIDs []string) (map[string][]models.GetFormFieldResponse, error) {
+	var fields = make(map[string][]models.GetFormFieldResponse)
+
+	query := `
+		select
+			f.id,
+			f.group_id,
+			f.parent_id,
+			p.alias,
+			f.label,
+			f.label_position,
+			f.icon,
+			f.alias,
+			f.hint,
+			f.warning,
+			f.sequence,
+			f.type,
+			f.validation,
+			f.multiple,
+			f.list_type,
+			f.can_search,
+			f.can_add_item,
+			f.can_open_popup,
+			f.grouped,
+			f.is_signature,
+			f.action
+		from form_field f
+		left join form_field p on p.id = f.parent_id
+		where f.group_id = any($1::uuid[]) and f.deleted_at is null
+		order by f.sequence
+	`
+
+	rows, err := r.querier.Query(ctx, query, pq.Array(groupIDs))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not query form fields")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			field                              models.GetFormFieldResponse
+			groupID                            string
+			parentID, parentAlias, icon, alias sql.NullString
+			hint, warning, listType, action    sql.NullString
+			validation                         []byte
+		)
+
+		if err = rows.Scan(
+			&field.ID,
+			&groupID,
+			&parentID,
+			&parentAlias,
+			&field.Label,
+			&field.LabelPosition,
+			&icon,
+			&alias,
+			&hint,
+			&warning,
+			&field.Sequence,
+			&field.Type,
+			&validation,
+			&field.Multiple,
+			&listType,
+			&field.CanSearch,
+			&field.CanAddItem,
+			&field.CanOpenPopup,
+			&field.Grouped,
+			&field.IsSignature,
+			&action,
+		); err != nil {
+			return nil, errors.Wrap(err, "could not scan rows")
+		}
+
+		if err = json.Unmarshal(validation, &field.Validation); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal field validation")
+		}
+
+		field.Name = strings.TrimPrefix(alias.String, "#")
+		field.ParentAlias = parentAlias.String
+		field.Icon = icon.String
+		field.Alias = alias.String
+		field.Hint = hint.String
+		field.Warning = warning.String
+		field.ListType = listType.String
+		field.Action = action.String
+
+		key := groupID
+		if parentID.Valid {
+			key = parentID.String
+		}
+
+		fields[key] = append(fields[key], field)
+	}
+
+	return fields, nil
+}
+
+func buildFieldTree(fields map[string][]models.GetFormFieldResponse, parentID string) []models.GetFormFieldResponse {
+	children := fields[parentID]
+
+	for i := range children {
+		children[i].ChildFields = buildFieldTree(fields, children[i].ID)
+	}
+
+	return children
+}
+
+// GetStepFields returns flat list of fields of form attached to step
+func (r *repo) GetStepFields(ctx context.Context, stepID string) ([]models.GetFormFieldResponse, error) {
+	var (
+		formID   sql.NullString
+		groupIDs []string
+		fields   []models.GetFormFieldResponse
+	)
+
+	query := `select form_id from step where id = $1 and deleted_at is null`
+
+	if err := r.querier.QueryRow(ctx, query, stepID).Scan(&formID); err != nil {
+		return nil, helpers.ToCustomError(err)
+	}
+
+	if !formID.Valid {
+		return fields, nil
+	}
+
+	query = `
+		select array(
+			select r.group_id::text from form_group_relation r
+			join form f on f.id = r.form_id and f.deleted_at is null
+			where r.form_id = $1
+		)
+	`
+
+	if err := r.querier.QueryRow(ctx, query, formID.String).Scan(pq.Array(&groupIDs)); err != nil {
+		return nil, helpers.ToCustomError(err)
+	}
+
+	if len(groupIDs) == 0 {
+		return fields, nil
+	}
+
+	fieldsByParent, err := r.findFields(ctx, groupIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, children := range fieldsByParent {
+		fields = append(fields, children...)
+	}
+
+	return fields, nil
+}
+
+// SetFieldValues creates new revision for every submitted field, values of previous revision are kept as deleted
+func (r *repo) SetFieldValues(ctx context.Context, req models.SetFormFieldValue) error {
+	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+
+	for _, field := range req.Fields {
+		revisionID := uuid.New().String()
+
+		if err = r.createRevision(tx, revisionID, req.DriverID, req.StepID, field.FieldID, req.CreatedBy); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+
+		query := `
+			insert into form_field_value (id, driver_id, step_id, field_id, value, revision_id, created_by, created_at)
+			values ($1, $2, $3, $4, $5, $6, $7, current_timestamp)
+		`
+
+		for _, value := range field.Values {
+			if _, err = tx.Exec(
+				query,
+				uuid.New().String(),
+				req.DriverID,
+				req.StepID,
+				field.FieldID,
+				value,
+				revisionID,
+				helpers.ToNullString(req.CreatedBy),
+			); err != nil {
+				_ = tx.Rollback()
+				return errors.Wrap(err, "could not create field value")
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// createRevision deletes current values of field and registers new revision
+func (r *repo) createRevision(tx *sqlx.Tx, revisionID, driverID, stepID, fieldID, createdBy string) error {
+	query := `
+		update form_field_value set deleted_at = current_timestamp
+		where driver_id = $1 and step_id = $2 and field_id = $3 and deleted_at is null
+	`
+
+	if _, err := tx.Exec(query, driverID, stepID, fieldID); err != nil {
+		return errors.Wrap(err, "could not delete previous field values")
+	}
+
+	query = `
+		insert into form_field_revision (id, driver_id, step_id, field_id, created_by, created_at)
+		values ($1, $2, $3, $4, $5, current_timestamp)
+	`
+
+	if _, err := tx.Exec(query, revisionID, driverID, stepID, fieldID, helpers.ToNullString(createdBy)); err != nil {
+		return errors.Wrap(err, "could not create field revision")
+	}
+
+	return nil
+}
+
+func (r *repo) GetFieldValueHistory(ctx context.Context, req models.GetFormFieldValueHistoryRequest) (models.GetFormFieldValueHistoryResponse, error) {
+	var (
+		response models.GetFormFieldValueHistoryResponse
+		indexes  = make(map[string]int)
+	)
+
+	query := `select count(1) from form_field_revision where driver_id = $1 and field_id = $2`
+
+	if err := r.querier.QueryRow(ctx, query, req.DriverID, req.FieldID).Scan(&response.Count); err != nil {
+		return response, helpers.ToCustomError(err)
+	}
+
+	offset, limit := helpers.NormalizePagination(req.Page, req.Limit)
+
+	query = `
+		select
+			r.id,
+			r.step_id,
+			r.id = (
+				select l.id from form_field_revision l
+				where l.driver_id = r.driver_id and l.step_id = r.step_id and l.field_id = r.field_id
+				order by l.created_at desc
+				limit 1
+			),
+			coalesce(u.first_name || ' ' || u.last_name, u.username, ''),
+			r.created_at
+		from form_field_revision r
+		left join "user" u on u.id = r.created_by
+		where r.driver_id = $1 and r.field_id = $2
+		order by r.created_at desc
+		offset $3 limit $4
+	`
+
+	rows, err := r.querier.Query(ctx, query, req.DriverID, req.FieldID, offset, limit)
+	if err != nil {
+		return response, errors.Wrap(err, "could not query field revisions")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			revision  models.GetFormFieldRevisionResponse
+			createdAt time.Time
+		)
+
+		if err = rows.Scan(
+			&revision.ID,
+			&revision.StepID,
+			&revision.Current,
+			&revision.CreatedBy,
+			&createdAt,
+		); err != nil {
+			return response, errors.Wrap(err, "could not scan rows")
+		}
+
+		revision.CreatedAt = helpers.TimeToString(createdAt, config.DateTimeFormat, true)
+
+		indexes[revision.ID] = len(response.Revisions)
+		response.Revisions = append(response.Revisions, revision)
+	}
+
+	if len(response.Revisions) == 0 {
+		return response, nil
+	}
+
+	revisionIDs := make([]string, 0, len(response.Revisions))
+	for _, revision := range response.Revisions {
+		revisionIDs = append(revisionIDs, revision.ID)
+	}
+
+	query = `
+		select
+			v.revision_id,
+			v.value
+		from form_field_value v
+		where v.revision_id = any($1::uuid[])
+		order by v.created_at
+	`
+
+	valueRows, err := r.querier.Query(ctx, query, pq.Array(revisionIDs))
+	if err != nil {
+		return response, errors.Wrap(err, "could not query field values")
+	}
+	defer valueRows.Close()
+
+	for valueRows.Next() {
+		var revisionID, value string
+
+		if err = valueRows.Scan(&revisionID, &value); err != nil {
+			return response, errors.Wrap(err, "could not scan rows")
+		}
+
+		revision := &response.Revisions[indexes[revisionID]]
+		revision.Values = append(revision.Values, models.FormValue{
+			CreatedBy: revision.CreatedBy,
+			CreatedAt: revision.CreatedAt,
+			Value:     value,
+		})
+	}
+
+	return response, nil
+}
+
+// RevertFieldValue creates new revision with values of earlier revision,
+// returns models.ErrNotFound when revision does not belong to driver field
+func (r *repo) RevertFieldValue(ctx context.Context, req models.RevertFormFieldValueRequest) error {
+	var stepID string
+
+	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+
+	query := `select step_id from form_field_revision where id = $1 and driver_id = $2 and field_id = $3`
+
+	if err = tx.QueryRow(query, req.RevisionID, req.DriverID, req.FieldID).Scan(&stepID); err != nil {
+		_ = tx.Rollback()
+		return helpers.ToCustomError(err)
+	}
+
+	revisionID := uuid.New().String()
+
+	if err = r.createRevision(tx, revisionID, req.DriverID, stepID, req.FieldID, req.CreatedBy); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	query = `
+		insert into form_field_value (id, driver_id, step_id, field_id, value, revision_id, created_by, created_at)
+		select uuid_generate_v4(), driver_id, step_id, field_id, value, $2, $3, current_timestamp
+		from form_field_value
+		where revision_id = $1
+		order by created_at
+	`
+
+	if _, err = tx.Exec(query, req.RevisionID, revisionID, helpers.ToNullString(req.CreatedBy)); err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not copy field values")
+	}
+
+	return tx.Commit()
+}
+
+func (r *repo) GetFieldRevisionStepID(ctx context.Context, req models.RevertFormFieldValueRequest) (string, error) {
+	var stepID string
+
+	query := `select step_id from form_field_revision where id = $1 and driver_id = $2 and field_id = $3`
+
+	if err := r.querier.QueryRow(ctx, query, req.RevisionID, req.DriverID, req.FieldID).Scan(&stepID); err != nil {
+		return "", helpers.ToCustomError(err)
+	}
+
+	return stepID, nil
+}
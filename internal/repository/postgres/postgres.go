@@ -2,9 +2,24 @@ package postgres
 
 import (
 	"github.com/abdivasiyev/project_template/internal/repository/postgres/app_repo"
+	"github.com/abdivasiyev/project_template/internal/repository/postgres/assignment_repo"
+	"github.com/abdivasiyev/project_template/internal/repository/postgres/car_repo"
+	"github.com/abdivasiyev/project_template/internal/repository/postgres/comment_repo"
+	"github.com/abdivasiyev/project_template/internal/repository/postgres/company_repo"
+	"github.com/abdivasiyev/project_template/internal/repository/postgres/dashboard_repo"
+	"github.com/abdivasiyev/project_template/internal/repository/postgres/department_repo"
+	"github.com/abdivasiyev/project_template/internal/repository/postgres/driver_repo"
+	"github.com/abdivasiyev/project_template/internal/repository/postgres/entity_repo"
 	"github.com/abdivasiyev/project_template/internal/repository/postgres/file_repo"
+	"github.com/abdivasiyev/project_template/internal/repository/postgres/form_repo"
 	"github.com/abdivasiyev/project_template/internal/repository/postgres/permission_repo"
 	"github.com/abdivasiyev/project_template/internal/repository/postgres/role_repo"
+	"github.com/abdivasiyev/project_template/internal/repository/postgres/session_repo"
+	"github.com/abdivasiyev/project_template/internal/repository/postgres/status_repo"
+	"github.com/abdivasiyev/project_template/internal/repository/postgres/step_repo"
+	"github.com/abdivasiyev/project_template/internal/repository/postgres/trailer_repo"
+	"github.com/abdivasiyev/project_template/internal/repository/postgres/truck_repo"
+	"github.com/abdivasiyev/project_template/internal/repository/postgres/two_factor_repo"
 	"github.com/abdivasiyev/project_template/internal/repository/postgres/user_repo"
 	"go.uber.org/fx"
 )
@@ -15,4 +30,19 @@ var Module = fx.Options(
 	role_repo.Module,
 	user_repo.Module,
 	app_repo.Module,
+	truck_repo.Module,
+	trailer_repo.Module,
+	car_repo.Module,
+	driver_repo.Module,
+	form_repo.Module,
+	status_repo.Module,
+	step_repo.Module,
+	department_repo.Module,
+	company_repo.Module,
+	dashboard_repo.Module,
+	entity_repo.Module,
+	assignment_repo.Module,
+	comment_repo.Module,
+	session_repo.Module,
+	two_factor_repo.Module,
 )
@@ -0,0 +1,217 @@
+package session_repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/abdivasiyev/project_template/config"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	"github.com/abdivasiyev/project_template/internal/types"
+	"github.com/abdivasiyev/project_template/pkg/helpers"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/storage"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+)
+
+var Module = fx.Provide(New)
+
+type repo struct {
+	querier storage.Querier
+	log     logger.Logger
+}
+
+type Params struct {
+	fx.In
+	Querier storage.Querier
+	Log     logger.Logger
+}
+
+func New(params Params) repository.Session {
+	return &repo{
+		querier: params.Querier,
+		log:     params.Log,
+	}
+}
+
+// Save creates session on login and updates its tokens on refresh,
+// user agent and creation time are kept from login
+func (r *repo) Save(ctx context.Context, req models.SaveSessionRequest) error {
+	query := `
+		insert into session (id, user_id, user_agent, ip, access_token_id, refresh_token_id, created_at, last_seen_at, expires_at)
+		values ($1, $2, $3, $4, $5, $6, current_timestamp, current_timestamp, to_timestamp($7)::timestamp)
+		on conflict (id) do update set
+			ip = excluded.ip,
+			access_token_id = excluded.access_token_id,
+			refresh_token_id = excluded.refresh_token_id,
+			last_seen_at = excluded.last_seen_at,
+			expires_at = excluded.expires_at
+		where session.revoked_at is null
+	`
+
+	if _, err := r.querier.Exec(
+		ctx,
+		query,
+		req.ID,
+		req.UserID,
+		req.UserAgent,
+		req.IP,
+		req.AccessTokenID,
+		req.RefreshTokenID,
+		req.ExpiresAt,
+	); err != nil {
+		return errors.Wrap(err, "could not save session")
+	}
+
+	return nil
+}
+
+func (r *repo) Touch(ctx context.Context, id string) error {
+	query := `update session set last_seen_at = current_timestamp where id = $1 and revoked_at is null`
+
+	if _, err := r.querier.Exec(ctx, query, id); err != nil {
+		return errors.Wrap(err, "could not update session last seen time")
+	}
+
+	return nil
+}
+
+// Revoke marks session as revoked, limited to given user when user id is not empty
+func (r *repo) Revoke(ctx context.Context, req models.RevokeSessionRequest) error {
+	tenant := helpers.GetTenant(ctx)
+
+	query := `
+		update session s set revoked_at = current_timestamp
+		from "user" u
+		where u.id = s.user_id and s.id = $1 and s.revoked_at is null
+		  and ($2::uuid is null or s.user_id = $2)
+		  and ($3 or u.company_id is not distinct from $4)
+	`
+
+	result, err := r.querier.Exec(ctx, query, req.ID, helpers.ToNullString(req.UserID), tenant.All, helpers.ToNullString(tenant.CompanyID))
+	if err != nil {
+		return errors.Wrap(err, "could not revoke session")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affectedRows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *repo) RevokeByUser(ctx context.Context, userID string) error {
+	query := `update session set revoked_at = current_timestamp where user_id = $1 and revoked_at is null`
+
+	if _, err := r.querier.Exec(ctx, query, userID); err != nil {
+		return errors.Wrap(err, "could not revoke user sessions")
+	}
+
+	return nil
+}
+
+// GetAll returns active sessions of tenant, ordered by last activity
+func (r *repo) GetAll(ctx context.Context, req models.GetAllSessionsRequest) (models.GetAllSessionsResponse, error) {
+	var (
+		tenant    = helpers.GetTenant(ctx)
+		statement = `
+			WHERE s.revoked_at is null AND s.expires_at > current_timestamp
+			  AND (:tenant_all OR u.company_id is not distinct from :tenant_id)
+		`
+		params = make(types.M)
+	)
+
+	params["tenant_all"], params["tenant_id"] = tenant.All, helpers.ToNullString(tenant.CompanyID)
+
+	if !helpers.IsEmpty(req.UserID) {
+		params["user_id"] = req.UserID
+		statement += ` AND s.user_id = :user_id`
+	}
+
+	params["offset"], params["limit"] = helpers.NormalizePagination(req.Page, req.Limit)
+	return r.find(ctx, statement, params)
+}
+
+func (r *repo) find(ctx context.Context, statement string, params types.M) (models.GetAllSessionsResponse, error) {
+	var response models.GetAllSessionsResponse
+
+	queryCount := `
+		SELECT
+			count(1)
+		FROM session s
+		JOIN "user" u ON u.id = s.user_id
+	` + statement
+
+	stmtCount, err := r.querier.PrepareNamed(ctx, queryCount)
+	if err != nil {
+		return response, errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmtCount.Close()
+
+	if err = stmtCount.QueryRow(params).Scan(&response.Count); err != nil {
+		return response, helpers.ToCustomError(err)
+	}
+
+	query := `
+		SELECT
+			s.id,
+			s.user_id,
+			u.username,
+			s.user_agent,
+			s.ip,
+			s.created_at,
+			s.last_seen_at,
+			s.expires_at
+		FROM session s
+		JOIN "user" u ON u.id = s.user_id
+	` + statement + `
+		ORDER BY s.last_seen_at DESC
+		OFFSET :offset LIMIT :limit
+	`
+
+	stmt, err := r.querier.PrepareNamed(ctx, query)
+	if err != nil {
+		return response, errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(params)
+	if err != nil {
+		return response, errors.Wrap(err, "could not query with params")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			session                          models.GetSessionResponse
+			createdAt, lastSeenAt, expiresAt time.Time
+		)
+
+		if err = rows.Scan(
+			&session.ID,
+			&session.UserID,
+			&session.Username,
+			&session.UserAgent,
+			&session.IP,
+			&createdAt,
+			&lastSeenAt,
+			&expiresAt,
+		); err != nil {
+			return response, errors.Wrap(err, "could not scan rows")
+		}
+
+		session.CreatedAt = helpers.TimeToString(createdAt, config.DateTimeFormat, true)
+		session.LastSeenAt = helpers.TimeToString(lastSeenAt, config.DateTimeFormat, true)
+		session.ExpiresAt = helpers.TimeToString(expiresAt, config.DateTimeFormat, true)
+
+		response.Sessions = append(response.Sessions, session)
+	}
+
+	return response, nil
+}
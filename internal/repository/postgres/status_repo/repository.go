@@ -0,0 +1,518 @@
+package status_repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	"github.com/abdivasiyev/project_template/pkg/helpers"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/storage"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+)
+
+var Module = fx.Provide(New)
+
+type repo struct {
+	querier storage.Querier
+	log     logger.Logger
+}
+
+type Params struct {
+	fx.In
+	Querier storage.Querier
+	Log     logger.Logger
+}
+
+func New(params Params) repository.Status {
+	return &repo{
+		querier: params.Querier,
+		log:     params.Log,
+	}
+}
+
+// Create inserts status into requested position among statuses of the same entity type,
+// returns models.ErrConflict when alias is already taken
+func (r *repo) Create(ctx context.Context, req models.CreateStatusRequest) error {
+	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+
+	if err = checkAlias(tx, req.ID, req.EntityType, req.Alias); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	query := `
+		insert into status (id, entity_type, alias, name, sequence, color, created_at)
+		values ($1, $2, $3, $4, $5, $6, current_timestamp)
+	`
+
+	if _, err = tx.Exec(
+		query,
+		req.ID,
+		req.EntityType,
+		req.Alias,
+		req.Name,
+		req.Sequence,
+		req.Color,
+	); err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not create status")
+	}
+
+	if err = move(tx, req.ID, req.EntityType, req.Sequence); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Update changes status and moves it into requested position,
+// returns models.ErrConflict when alias is already taken or alias of system status is changed
+func (r *repo) Update(ctx context.Context, req models.UpdateStatusRequest) error {
+	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+
+	entityType, alias, isSystem, err := getStatus(tx, req.ID)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if isSystem && alias != req.Alias {
+		_ = tx.Rollback()
+		return models.ErrConflict
+	}
+
+	if err = checkAlias(tx, req.ID, entityType, req.Alias); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	query := `
+		update status set
+			alias = $2,
+			name = $3,
+			color = $4,
+			updated_at = current_timestamp
+		where id = $1 and deleted_at is null
+	`
+
+	if _, err = tx.Exec(
+		query,
+		req.ID,
+		req.Alias,
+		req.Name,
+		req.Color,
+	); err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not update status")
+	}
+
+	if err = move(tx, req.ID, entityType, req.Sequence); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete removes status with its transitions and closes the gap in sequences of its entity type,
+// returns models.ErrConflict when status is system one or it is still used
+func (r *repo) Delete(ctx context.Context, id string) error {
+	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+
+	entityType, _, isSystem, err := getStatus(tx, id)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if isSystem {
+		_ = tx.Rollback()
+		return models.ErrConflict
+	}
+
+	if err = checkUsage(tx, id); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	query := `update status set deleted_at = current_timestamp where id = $1 and deleted_at is null`
+
+	if _, err = tx.Exec(query, id); err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not delete status")
+	}
+
+	query = `
+		update status_transition set deleted_at = current_timestamp
+		where (from_status_id = $1 or to_status_id = $1) and deleted_at is null
+	`
+
+	if _, err = tx.Exec(query, id); err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not delete status transitions")
+	}
+
+	if err = reorder(tx, entityType, nil); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Reorder places given statuses of entity type first in given order, others keep their order after them,
+// returns models.ErrNotFound when any of statuses does not belong to entity type
+func (r *repo) Reorder(ctx context.Context, entityType string, ids []string) error {
+	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+
+	var count int
+
+	query := `select count(1) from status where id = any($1::uuid[]) and entity_type = $2 and deleted_at is null`
+
+	if err = tx.QueryRow(query, pq.Array(ids), entityType).Scan(&count); err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not count statuses")
+	}
+
+	if count != len(ids) {
+		_ = tx.Rollback()
+		return models.ErrNotFound
+	}
+
+	if err = reorder(tx, entityType, ids); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Get returns status with its entity type
+func (r *repo) Get(ctx context.Context, id string) (models.GetStatusResponse, string, error) {
+	var (
+		status     models.GetStatusResponse
+		entityType string
+	)
+
+	query := `
+		select
+			id,
+			alias,
+			name,
+			sequence,
+			color,
+			is_system,
+			entity_type
+		from status
+		where id = $1 and deleted_at is null
+	`
+
+	err := r.querier.QueryRow(ctx, query, id).Scan(
+		&status.ID,
+		&status.Alias,
+		&status.Name,
+		&status.Sequence,
+		&status.Color,
+		&status.IsSystem,
+		&entityType,
+	)
+
+	return status, entityType, helpers.ToCustomError(err)
+}
+
+func (r *repo) GetByEntityType(ctx context.Context, entityType string) ([]models.GetStatusResponse, error) {
+	var statuses []models.GetStatusResponse
+
+	query := `
+		select
+			id,
+			alias,
+			name,
+			sequence,
+			color,
+			is_system
+		from status
+		where entity_type = $1 and deleted_at is null
+		order by sequence
+	`
+
+	rows, err := r.querier.Query(ctx, query, entityType)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not query statuses")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var status models.GetStatusResponse
+
+		if err = rows.Scan(
+			&status.ID,
+			&status.Alias,
+			&status.Name,
+			&status.Sequence,
+			&status.Color,
+			&status.IsSystem,
+		); err != nil {
+			return nil, errors.Wrap(err, "could not scan rows")
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+func (r *repo) CreateTransition(ctx context.Context, req models.CreateStatusTransitionRequest) error {
+	query := `
+		insert into status_transition (id, from_status_id, to_status_id, role_id, created_at)
+		values ($1, $2, $3, $4, current_timestamp)
+	`
+
+	_, err := r.querier.Exec(
+		ctx,
+		query,
+		req.ID,
+		req.FromStatusID,
+		req.ToStatusID,
+		helpers.ToNullString(req.RoleID),
+	)
+
+	return errors.Wrap(err, "could not create status transition")
+}
+
+func (r *repo) DeleteTransition(ctx context.Context, id string) error {
+	query := `update status_transition set deleted_at = current_timestamp where id = $1 and deleted_at is null`
+
+	result, err := r.querier.Exec(ctx, query, id)
+	if err != nil {
+		return errors.Wrap(err, "could not delete status transition")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affectedRows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *repo) GetTransitions(ctx context.Context, entityType string) (models.GetAllStatusTransitionsResponse, error) {
+	var response models.GetAllStatusTransitionsResponse
+
+	query := `
+		select
+			t.id,
+			fs.id,
+			fs.alias,
+			fs.name,
+			fs.sequence,
+			fs.color,
+			ts.id,
+			ts.alias,
+			ts.name,
+			ts.sequence,
+			ts.color,
+			ro.id,
+			ro.alias,
+			ro.name
+		from status_transition t
+		join status fs on fs.id = t.from_status_id and fs.deleted_at is null
+		join status ts on ts.id = t.to_status_id and ts.deleted_at is null
+		left join role ro on ro.id = t.role_id
+		where t.deleted_at is null and fs.entity_type = $1
+		order by fs.sequence, ts.sequence
+	`
+
+	rows, err := r.querier.Query(ctx, query, entityType)
+	if err != nil {
+		return response, errors.Wrap(err, "could not query status transitions")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			transition                  models.GetStatusTransitionResponse
+			roleID, roleAlias, roleName sql.NullString
+		)
+
+		if err = rows.Scan(
+			&transition.ID,
+			&transition.FromStatus.ID,
+			&transition.FromStatus.Alias,
+			&transition.FromStatus.Name,
+			&transition.FromStatus.Sequence,
+			&transition.FromStatus.Color,
+			&transition.ToStatus.ID,
+			&transition.ToStatus.Alias,
+			&transition.ToStatus.Name,
+			&transition.ToStatus.Sequence,
+			&transition.ToStatus.Color,
+			&roleID,
+			&roleAlias,
+			&roleName,
+		); err != nil {
+			return response, errors.Wrap(err, "could not scan rows")
+		}
+
+		if roleID.Valid {
+			transition.Role = &models.GetRoleResponse{
+				ID:    roleID.String,
+				Alias: roleAlias.String,
+				Name:  roleName.String,
+			}
+		}
+
+		response.Transitions = append(response.Transitions, transition)
+	}
+
+	response.Count = len(response.Transitions)
+
+	return response, nil
+}
+
+// IsTransitionAllowed checks whether status can follow another one,
+// transitions without role are allowed for everyone, others only for users with that role
+func (r *repo) IsTransitionAllowed(ctx context.Context, fromStatusID, toStatusID, userID string) (bool, error) {
+	var allowed bool
+
+	query := `
+		select exists(
+			select 1 from status_transition t
+			where t.from_status_id = $1
+			  and t.to_status_id = $2
+			  and t.deleted_at is null
+			  and (t.role_id is null or t.role_id in (select role_id from user_role where user_id = $3))
+		)
+	`
+
+	err := r.querier.QueryRow(ctx, query, fromStatusID, toStatusID, userID).Scan(&allowed)
+
+	return allowed, errors.Wrap(err, "could not check status transition")
+}
+
+// getStatus returns entity type, alias and system flag of status, status row is locked until end of transaction
+func getStatus(tx *sqlx.Tx, id string) (string, string, bool, error) {
+	var (
+		entityType, alias string
+		isSystem          bool
+	)
+
+	query := `select entity_type, alias, is_system from status where id = $1 and deleted_at is null for update`
+
+	if err := tx.QueryRow(query, id).Scan(&entityType, &alias, &isSystem); err != nil {
+		return "", "", false, errors.Wrap(helpers.ToCustomError(err), "could not get status")
+	}
+
+	return entityType, alias, isSystem, nil
+}
+
+// checkUsage returns models.ErrConflict when status is used by any entity
+func checkUsage(tx *sqlx.Tx, id string) error {
+	var used bool
+
+	query := `
+		select exists(select 1 from truck where status_id = $1 and deleted_at is null)
+			or exists(select 1 from trailer where status_id = $1 and deleted_at is null)
+			or exists(select 1 from car where status_id = $1 and deleted_at is null)
+			or exists(select 1 from driver where status_id = $1 and deleted_at is null)
+			or exists(select 1 from driver_step where status_id = $1)
+	`
+
+	if err := tx.QueryRow(query, id).Scan(&used); err != nil {
+		return errors.Wrap(err, "could not check status usage")
+	}
+
+	if used {
+		return models.ErrConflict
+	}
+
+	return nil
+}
+
+func checkAlias(tx *sqlx.Tx, id, entityType, alias string) error {
+	var count int
+
+	query := `select count(1) from status where entity_type = $1 and alias = $2 and id <> $3 and deleted_at is null`
+
+	if err := tx.QueryRow(query, entityType, alias, id).Scan(&count); err != nil {
+		return errors.Wrap(err, "could not check status alias")
+	}
+
+	if count > 0 {
+		return models.ErrConflict
+	}
+
+	return nil
+}
+
+// move places status into given position among statuses of entity type and renumbers others without gaps,
+// position greater than number of statuses puts status to the end
+func move(tx *sqlx.Tx, id, entityType string, position int) error {
+	query := `
+		update status s set
+			sequence = case when o.position < $3 then o.position else o.position + 1 end
+		from (
+			select id, row_number() over (order by sequence, created_at) as position
+			from status
+			where entity_type = $2 and deleted_at is null and id <> $1
+		) o
+		where s.id = o.id
+	`
+
+	if _, err := tx.Exec(query, id, entityType, position); err != nil {
+		return errors.Wrap(err, "could not shift statuses")
+	}
+
+	query = `
+		update status set
+			sequence = least($3, (select count(1) from status where entity_type = $2 and deleted_at is null))
+		where id = $1
+	`
+
+	if _, err := tx.Exec(query, id, entityType, position); err != nil {
+		return errors.Wrap(err, "could not move status")
+	}
+
+	return nil
+}
+
+// reorder renumbers statuses of entity type from one without gaps, given statuses go first
+func reorder(tx *sqlx.Tx, entityType string, ids []string) error {
+	query := `
+		update status s set
+			sequence = o.position,
+			updated_at = current_timestamp
+		from (
+			select id, row_number() over (order by array_position($2::uuid[], id) nulls last, sequence, created_at) as position
+			from status
+			where entity_type = $1 and deleted_at is null
+		) o
+		where s.id = o.id and s.sequence <> o.position
+	`
+
+	_, err := tx.Exec(query, entityType, pq.Array(ids))
+
+	return errors.Wrap(err, "could not reorder statuses")
+}
@@ -0,0 +1,565 @@
+package step_repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	"github.com/abdivasiyev/project_template/pkg/helpers"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/storage"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+)
+
+const (
+	StatusToDo      = "to_do"
+	StatusCompleted = "completed"
+
+	DriverStatusPending   = "pending"
+	DriverStatusInProcess = "in_process"
+	DriverStatusActive    = "active"
+)
+
+var Module = fx.Provide(New)
+
+type repo struct {
+	querier storage.Querier
+	log     logger.Logger
+}
+
+type Params struct {
+	fx.In
+	Querier storage.Querier
+	Log     logger.Logger
+}
+
+func New(params Params) repository.Step {
+	return &repo{
+		querier: params.Querier,
+		log:     params.Log,
+	}
+}
+
+// Create inserts step into department, returns models.ErrNotFound when department does not exist
+func (r *repo) Create(ctx context.Context, req models.CreateStepRequest) error {
+	query := `
+		insert into step (id, department_id, form_id, alias, name, sequence, created_at)
+		select $1, d.id, $3, $4, $5, $6, current_timestamp
+		from department d
+		where d.id = $2 and d.deleted_at is null
+	`
+
+	result, err := r.querier.Exec(
+		ctx,
+		query,
+		req.ID,
+		req.DepartmentID,
+		helpers.ToNullString(req.FormID),
+		req.Alias,
+		req.Name,
+		req.Sequence,
+	)
+	if err != nil {
+		return errors.Wrap(err, "could not create step")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affectedRows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *repo) Update(ctx context.Context, req models.CreateStepRequest) error {
+	query := `
+		update step set
+			department_id = $2,
+			form_id = $3,
+			alias = $4,
+			name = $5,
+			sequence = $6,
+			updated_at = current_timestamp
+		where id = $1 and deleted_at is null
+		  and exists (select 1 from department where id = $2 and deleted_at is null)
+	`
+
+	result, err := r.querier.Exec(
+		ctx,
+		query,
+		req.ID,
+		req.DepartmentID,
+		helpers.ToNullString(req.FormID),
+		req.Alias,
+		req.Name,
+		req.Sequence,
+	)
+	if err != nil {
+		return errors.Wrap(err, "could not update step")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affectedRows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *repo) Delete(ctx context.Context, id string) error {
+	query := `update step set deleted_at = current_timestamp where id = $1 and deleted_at is null`
+
+	result, err := r.querier.Exec(ctx, query, id)
+	if err != nil {
+		return errors.Wrap(err, "could not delete step")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affectedRows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *repo) Get(ctx context.Context, id string) (models.GetStepResponse, error) {
+	steps, err := r.find(ctx, `st.id = $1`, id)
+	if err != nil {
+		return models.GetStepResponse{}, err
+	}
+
+	if len(steps) == 0 {
+		return models.GetStepResponse{}, models.ErrNotFound
+	}
+
+	return steps[0], nil
+}
+
+func (r *repo) GetAll(ctx context.Context, departmentID string) (models.GetAllStepsResponse, error) {
+	var response models.GetAllStepsResponse
+
+	steps, err := r.find(ctx, `st.department_id = $1`, departmentID)
+	if err != nil {
+		return response, err
+	}
+
+	response.Steps = steps
+	response.Count = len(steps)
+
+	return response, nil
+}
+
+func (r *repo) find(ctx context.Context, statement string, args ...any) ([]models.GetStepResponse, error) {
+	var steps []models.GetStepResponse
+
+	query := `
+		select
+			st.id,
+			st.department_id,
+			st.form_id,
+			st.alias,
+			st.name,
+			st.sequence
+		from step st
+		where st.deleted_at is null and ` + statement + `
+		order by st.sequence
+	`
+
+	rows, err := r.querier.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not query steps")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			step   models.GetStepResponse
+			formID sql.NullString
+		)
+
+		if err = rows.Scan(
+			&step.ID,
+			&step.DepartmentID,
+			&formID,
+			&step.Alias,
+			&step.Name,
+			&step.Sequence,
+		); err != nil {
+			return nil, errors.Wrap(err, "could not scan rows")
+		}
+
+		step.FormID = formID.String
+
+		steps = append(steps, step)
+	}
+
+	return steps, nil
+}
+
+// GetDriverSteps returns steps of department with driver status and current field values of every step
+func (r *repo) GetDriverSteps(ctx context.Context, req models.GetAllStepsRequest) ([]models.GetStepResponse, error) {
+	var (
+		steps   []models.GetStepResponse
+		indexes = make(map[string]int)
+	)
+
+	query := `
+		select
+			st.id,
+			st.department_id,
+			st.form_id,
+			st.alias,
+			st.name,
+			st.sequence,
+			ds.status_id
+		from step st
+		left join driver_step ds on ds.step_id = st.id and ds.driver_id = $2
+		where st.deleted_at is null and st.department_id = $1
+		order by st.sequence
+	`
+
+	rows, err := r.querier.Query(ctx, query, req.DepartmentID, req.DriverID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not query driver steps")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			step             models.GetStepResponse
+			formID, statusID sql.NullString
+		)
+
+		if err = rows.Scan(
+			&step.ID,
+			&step.DepartmentID,
+			&formID,
+			&step.Alias,
+			&step.Name,
+			&step.Sequence,
+			&statusID,
+		); err != nil {
+			return nil, errors.Wrap(err, "could not scan rows")
+		}
+
+		step.FormID = formID.String
+		step.StatusID = statusID.String
+
+		indexes[step.ID] = len(steps)
+		steps = append(steps, step)
+	}
+
+	if len(steps) == 0 {
+		return steps, nil
+	}
+
+	stepIDs := make([]string, 0, len(steps))
+	for _, step := range steps {
+		stepIDs = append(stepIDs, step.ID)
+	}
+
+	if err = r.fillStepFields(ctx, req.DriverID, stepIDs, steps, indexes); err != nil {
+		return nil, err
+	}
+
+	return steps, nil
+}
+
+// fillStepFields attaches form fields of steps with current values of driver
+func (r *repo) fillStepFields(ctx context.Context, driverID string, stepIDs []string, steps []models.GetStepResponse, indexes map[string]int) error {
+	query := `
+		select
+			st.id,
+			ff.id,
+			coalesce(ff.alias, ''),
+			ff.label,
+			v.id,
+			v.value
+		from step st
+		join form f on f.id = st.form_id and f.deleted_at is null
+		join form_group_relation r on r.form_id = f.id
+		join form_group g on g.id = r.group_id and g.deleted_at is null
+		join form_field ff on ff.group_id = g.id and ff.deleted_at is null
+		left join form_field_value v on v.field_id = ff.id
+			and v.step_id = st.id
+			and v.driver_id = $2
+			and v.deleted_at is null
+		where st.id = any($1::uuid[])
+		order by g.sequence, ff.sequence, v.created_at
+	`
+
+	rows, err := r.querier.Query(ctx, query, pq.Array(stepIDs), driverID)
+	if err != nil {
+		return errors.Wrap(err, "could not query step fields")
+	}
+	defer rows.Close()
+
+	fieldIndexes := make(map[string]int)
+
+	for rows.Next() {
+		var (
+			field          models.StepFields
+			valueID, value sql.NullString
+		)
+
+		if err = rows.Scan(
+			&field.StepID,
+			&field.ID,
+			&field.Alias,
+			&field.Label,
+			&valueID,
+			&value,
+		); err != nil {
+			return errors.Wrap(err, "could not scan rows")
+		}
+
+		step := &steps[indexes[field.StepID]]
+		key := field.StepID + field.ID
+
+		index, ok := fieldIndexes[key]
+		if !ok {
+			index = len(step.Fields)
+			fieldIndexes[key] = index
+			step.Fields = append(step.Fields, field)
+		}
+
+		if valueID.Valid {
+			step.Fields[index].Values = append(step.Fields[index].Values, models.GetFieldValueResponse{
+				ID:    valueID.String,
+				Value: value.String,
+			})
+		}
+	}
+
+	return nil
+}
+
+// UpdateStatus sets status of driver step and recalculates onboarding status of driver:
+// driver becomes active when every step is completed and in process when any step is started.
+// Returns models.ErrConflict when status of step is not current status of request anymore
+func (r *repo) UpdateStatus(ctx context.Context, req models.UpdateStepStatusRequest) error {
+	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+
+	query := `
+		insert into driver_step (driver_id, step_id, status_id, created_at)
+		values ($1, $2, $3, current_timestamp)
+		on conflict (driver_id, step_id) do update set
+			status_id = excluded.status_id,
+			updated_at = current_timestamp
+		where driver_step.status_id = $4
+	`
+
+	result, err := tx.Exec(query, req.DriverID, req.StepID, req.StatusID, req.CurrentStatusID)
+	if err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not update driver step status")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	// status was changed by another request after transition was checked
+	if affectedRows == 0 {
+		_ = tx.Rollback()
+		return models.ErrConflict
+	}
+
+	query = `
+		with progress as (
+			select
+				not exists (
+					select 1 from step st
+					where st.deleted_at is null and not exists (
+						select 1 from driver_step ds
+						join status s on s.id = ds.status_id
+						where ds.driver_id = $1 and ds.step_id = st.id and s.alias = $2
+					)
+				) as completed,
+				exists (
+					select 1 from driver_step ds
+					join step st on st.id = ds.step_id and st.deleted_at is null
+					join status s on s.id = ds.status_id
+					where ds.driver_id = $1 and s.alias <> $3
+				) as started
+		)
+		update driver set
+			status_id = (
+				select s.id from status s, progress p
+				where s.entity_type = 'driver' and s.deleted_at is null and s.alias = case
+					when p.completed then $4
+					when p.started then $5
+					else $6
+				end
+			),
+			on_board_date = case
+				when (select completed from progress) then coalesce(on_board_date, current_timestamp)
+				else on_board_date
+			end,
+			updated_at = current_timestamp
+		where id = $1
+	`
+
+	if _, err = tx.Exec(
+		query,
+		req.DriverID,
+		StatusCompleted,
+		StatusToDo,
+		DriverStatusActive,
+		DriverStatusInProcess,
+		DriverStatusPending,
+	); err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not update driver status")
+	}
+
+	return tx.Commit()
+}
+
+// GetDriverStepStatus returns current status of driver step, empty when step is not started yet
+func (r *repo) GetDriverStepStatus(ctx context.Context, driverID, stepID string) (string, error) {
+	var statusID string
+
+	query := `select status_id from driver_step where driver_id = $1 and step_id = $2`
+
+	err := r.querier.QueryRow(ctx, query, driverID, stepID).Scan(&statusID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+
+	return statusID, errors.Wrap(err, "could not get driver step status")
+}
+
+// GetDepartmentAccess merges access flags of all user roles for department
+func (r *repo) GetDepartmentAccess(ctx context.Context, userID, departmentID string) (models.HasAccessResponse, error) {
+	var response models.HasAccessResponse
+
+	query := `
+		select
+			coalesce(bool_or(a.can_view), false),
+			coalesce(bool_or(a.can_edit), false),
+			coalesce(bool_or(a.can_create), false),
+			coalesce(bool_or(a.can_delete), false)
+		from department_access a
+		join user_role ur on ur.role_id = a.role_id
+		where ur.user_id = $1 and a.department_id = $2
+	`
+
+	err := r.querier.QueryRow(ctx, query, userID, departmentID).Scan(
+		&response.CanView,
+		&response.CanEdit,
+		&response.CanCreate,
+		&response.CanDelete,
+	)
+
+	return response, errors.Wrap(err, "could not get department access")
+}
+
+func (r *repo) GetDepartmentAccesses(ctx context.Context, departmentID string) (models.GetAllDepartmentAccessResponse, error) {
+	var response models.GetAllDepartmentAccessResponse
+
+	query := `
+		select
+			ro.id,
+			ro.alias,
+			ro.name,
+			a.can_view,
+			a.can_edit,
+			a.can_create,
+			a.can_delete
+		from department_access a
+		join role ro on ro.id = a.role_id and ro.deleted_at is null
+		where a.department_id = $1
+		order by ro.name
+	`
+
+	rows, err := r.querier.Query(ctx, query, departmentID)
+	if err != nil {
+		return response, errors.Wrap(err, "could not query department accesses")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var access models.GetDepartmentAccessResponse
+
+		if err = rows.Scan(
+			&access.Role.ID,
+			&access.Role.Alias,
+			&access.Role.Name,
+			&access.CanView,
+			&access.CanEdit,
+			&access.CanCreate,
+			&access.CanDelete,
+		); err != nil {
+			return response, errors.Wrap(err, "could not scan rows")
+		}
+
+		response.Accesses = append(response.Accesses, access)
+	}
+
+	response.Count = len(response.Accesses)
+
+	return response, nil
+}
+
+// SetDepartmentAccess creates or replaces access flags of role for department,
+// returns models.ErrNotFound when department does not exist
+func (r *repo) SetDepartmentAccess(ctx context.Context, req models.SetDepartmentAccessRequest) error {
+	query := `
+		insert into department_access (department_id, role_id, can_view, can_edit, can_create, can_delete, created_at)
+		select d.id, $2, $3, $4, $5, $6, current_timestamp
+		from department d
+		where d.id = $1 and d.deleted_at is null
+		on conflict (department_id, role_id) do update set
+			can_view = excluded.can_view,
+			can_edit = excluded.can_edit,
+			can_create = excluded.can_create,
+			can_delete = excluded.can_delete,
+			updated_at = current_timestamp
+	`
+
+	result, err := r.querier.Exec(
+		ctx,
+		query,
+		req.DepartmentID,
+		req.RoleID,
+		req.CanView,
+		req.CanEdit,
+		req.CanCreate,
+		req.CanDelete,
+	)
+	if err != nil {
+		return errors.Wrap(err, "could not set department access")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affectedRows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,612 @@
+package trailer_repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/abdivasiyev/project_template/config"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	"github.com/abdivasiyev/project_template/internal/repository/postgres/assignment_repo"
+	"github.com/abdivasiyev/project_template/internal/types"
+	"github.com/abdivasiyev/project_template/pkg/helpers"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/storage"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+)
+
+var Module = fx.Provide(New)
+
+type repo struct {
+	querier storage.Querier
+	log     logger.Logger
+}
+
+type Params struct {
+	fx.In
+	Querier storage.Querier
+	Log     logger.Logger
+}
+
+func New(params Params) repository.Trailer {
+	return &repo{
+		querier: params.Querier,
+		log:     params.Log,
+	}
+}
+
+// Create saves trailer together with assignment of its driver
+func (r *repo) Create(ctx context.Context, req models.CreateTrailerRequest) error {
+	tenant := helpers.GetTenant(ctx)
+
+	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+
+	query := `
+		insert into trailer (id, make, number, year_made, trailer_type, plate_number, status_id, company_id, created_at)
+		values (
+			$1, $2, $3, $4, $5, $6,
+			coalesce($7::uuid, (select id from status where entity_type = 'trailer' and deleted_at is null order by sequence limit 1)),
+			$8,
+			current_timestamp
+		)
+	`
+
+	if _, err = tx.Exec(
+		query,
+		req.ID,
+		req.Make,
+		req.Number,
+		req.YearMade,
+		req.TrailerType,
+		req.PlateNumber,
+		helpers.ToNullString(req.StatusID),
+		helpers.ToNullString(tenant.CompanyID),
+	); err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(helpers.ToCustomError(err), "could not create trailer")
+	}
+
+	if err = assignDriver(tx, tenant, req.ID, req.DriverID); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Update changes trailer together with assignment of its driver
+func (r *repo) Update(ctx context.Context, req models.CreateTrailerRequest) error {
+	tenant := helpers.GetTenant(ctx)
+
+	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+
+	query := `
+		update trailer set
+			make = $2,
+			number = $3,
+			year_made = $4,
+			trailer_type = $5,
+			plate_number = $6,
+			status_id = coalesce($7::uuid, status_id),
+			updated_at = current_timestamp
+		where id = $1 and deleted_at is null
+		  and ($8 or company_id is not distinct from $9)
+	`
+
+	result, err := tx.Exec(
+		query,
+		req.ID,
+		req.Make,
+		req.Number,
+		req.YearMade,
+		req.TrailerType,
+		req.PlateNumber,
+		helpers.ToNullString(req.StatusID),
+		tenant.All,
+		helpers.ToNullString(tenant.CompanyID),
+	)
+	if err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(helpers.ToCustomError(err), "could not update trailer")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if affectedRows == 0 {
+		_ = tx.Rollback()
+		return models.ErrNotFound
+	}
+
+	if err = assignDriver(tx, tenant, req.ID, req.DriverID); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *repo) Delete(ctx context.Context, id string) error {
+	tenant := helpers.GetTenant(ctx)
+
+	query := `
+		update trailer set deleted_at = current_timestamp
+		where id = $1 and deleted_at is null
+		  and ($2 or company_id is not distinct from $3)
+	`
+
+	result, err := r.querier.Exec(ctx, query, id, tenant.All, helpers.ToNullString(tenant.CompanyID))
+	if err != nil {
+		return errors.Wrap(err, "could not delete trailer")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affectedRows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *repo) Get(ctx context.Context, id string) (models.GetTrailerResponse, error) {
+	response, err := r.find(ctx, `WHERE t.id = :id AND t.deleted_at is null`, types.M{
+		"id":     id,
+		"offset": 0,
+		"limit":  1,
+	})
+	if err != nil {
+		return models.GetTrailerResponse{}, err
+	}
+
+	if len(response.Trailers) == 0 {
+		return models.GetTrailerResponse{}, models.ErrNotFound
+	}
+
+	return response.Trailers[0], nil
+}
+
+func (r *repo) GetAll(ctx context.Context, req models.GetAllTrailersRequest) (models.GetAllTrailersResponse, error) {
+	var (
+		statement = `WHERE t.deleted_at is null`
+		params    = make(types.M)
+	)
+
+	if !helpers.IsEmpty(req.Search) {
+		params["search"] = req.Search
+
+		statement += ` AND (
+			t.number ilike '%' || :search || '%' OR
+			t.make ilike '%' || :search || '%' OR
+			t.trailer_type ilike '%' || :search || '%' OR
+			t.plate_number ilike '%' || :search || '%'
+		)`
+	}
+
+	if !helpers.IsEmpty(req.StatusID) {
+		params["status_id"] = req.StatusID
+
+		statement += ` AND t.status_id = :status_id`
+	}
+
+	params["offset"], params["limit"] = helpers.NormalizePagination(req.Page, req.Limit)
+	return r.find(ctx, statement, params)
+}
+
+func (r *repo) find(ctx context.Context, statement string, params types.M) (models.GetAllTrailersResponse, error) {
+	var response models.GetAllTrailersResponse
+
+	tenant := helpers.GetTenant(ctx)
+
+	statement += ` AND (:tenant_all OR t.company_id IS NOT DISTINCT FROM :tenant_id)`
+	params["tenant_all"], params["tenant_id"] = tenant.All, helpers.ToNullString(tenant.CompanyID)
+
+	queryCount := `
+		SELECT
+			count(1)
+		FROM trailer t
+	` + statement
+
+	stmtCount, err := r.querier.PrepareNamed(ctx, queryCount)
+	if err != nil {
+		return response, errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmtCount.Close()
+
+	if err = stmtCount.QueryRow(params).Scan(&response.Count); err != nil {
+		return response, helpers.ToCustomError(err)
+	}
+
+	query := `
+		SELECT
+			t.id,
+			t.make,
+			t.number,
+			t.year_made,
+			t.trailer_type,
+			t.plate_number,
+			t.created_at,
+			t.updated_at,
+			s.id,
+			s.alias,
+			s.name,
+			s.sequence,
+			s.color,
+			d.id,
+			d.first_name || ' ' || d.last_name,
+			da.assigned_at
+		FROM trailer t
+		LEFT JOIN status s ON s.id = t.status_id
+		LEFT JOIN driver_assignment da ON da.trailer_id = t.id AND da.unassigned_at is null
+		LEFT JOIN driver d ON d.id = da.driver_id AND d.deleted_at is null
+	` + statement + `
+		ORDER BY t.created_at DESC
+		OFFSET :offset LIMIT :limit
+	`
+
+	stmt, err := r.querier.PrepareNamed(ctx, query)
+	if err != nil {
+		return response, errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(params)
+	if err != nil {
+		return response, errors.Wrap(err, "could not query with params")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			trailer                               models.GetTrailerResponse
+			createdAt                             time.Time
+			updatedAt, driverAssignedAt           sql.NullTime
+			statusID, statusAlias, statusName     sql.NullString
+			statusColor, driverID, driverFullName sql.NullString
+			statusSequence                        sql.NullInt64
+		)
+
+		if err = rows.Scan(
+			&trailer.ID,
+			&trailer.Make,
+			&trailer.Number,
+			&trailer.YearMade,
+			&trailer.TrailerType,
+			&trailer.PlateNumber,
+			&createdAt,
+			&updatedAt,
+			&statusID,
+			&statusAlias,
+			&statusName,
+			&statusSequence,
+			&statusColor,
+			&driverID,
+			&driverFullName,
+			&driverAssignedAt,
+		); err != nil {
+			return response, errors.Wrap(err, "could not scan rows")
+		}
+
+		trailer.CreatedAt = helpers.TimeToString(createdAt, config.DateFormat, true)
+		trailer.UpdatedAt = helpers.TimeToString(updatedAt.Time, config.DateFormat, updatedAt.Valid)
+		trailer.Status = models.GetStatusResponse{
+			ID:       statusID.String,
+			Alias:    statusAlias.String,
+			Name:     statusName.String,
+			Sequence: int(statusSequence.Int64),
+			Color:    statusColor.String,
+		}
+		trailer.AssignedDriver.ID = driverID.String
+		trailer.AssignedDriver.Name = driverFullName.String
+		trailer.AssignedDriver.Since = helpers.TimeToString(driverAssignedAt.Time, config.DateFormat, driverID.Valid && driverAssignedAt.Valid)
+
+		response.Trailers = append(response.Trailers, trailer)
+	}
+
+	return response, nil
+}
+
+func (r *repo) CreateInspection(ctx context.Context, req models.CreateTrailerInspectionRequest) error {
+	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+
+	query := `
+		insert into trailer_inspection (
+			id,
+			trailer_id,
+			driver_id,
+			inspection_type,
+			is_empty,
+			location,
+			fuel_level_images,
+			left_side_images,
+			front_side_images,
+			right_side_images,
+			back_side_images,
+			in_side_images,
+			tire_images,
+			damage_images,
+			need_repair_devices,
+			driver_signature_id,
+			company_representative_signature_id,
+			created_by,
+			created_at
+		) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, current_timestamp)
+	`
+
+	if _, err = tx.Exec(
+		query,
+		req.ID,
+		req.TrailerID,
+		req.DriverID,
+		req.InspectionType,
+		req.IsEmpty,
+		req.Location,
+		pq.Array(req.FuelLevelImages),
+		pq.Array(req.LeftSideImages),
+		pq.Array(req.FrontSideImages),
+		pq.Array(req.RightSideImages),
+		pq.Array(req.BackSideImages),
+		pq.Array(req.InSideImages),
+		pq.Array(req.TireImages),
+		pq.Array(req.DamageImages),
+		pq.Array(req.NeedRepairDevices),
+		req.DriverSignatureID,
+		req.CompanyRepresentativeSignatureID,
+		helpers.ToNullString(req.CreatedBy),
+	); err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(helpers.ToCustomError(err), "could not create trailer inspection")
+	}
+
+	query = `
+		insert into comment (id, entity_type, entity_id, created_by, text, created_at)
+		values (uuid_generate_v4(), 'trailer_inspection', $1, $2, $3, current_timestamp)
+	`
+
+	for _, comment := range req.Comments {
+		if helpers.IsEmpty(comment.Text) {
+			continue
+		}
+
+		if _, err = tx.Exec(query, req.ID, helpers.ToNullString(req.CreatedBy), comment.Text); err != nil {
+			_ = tx.Rollback()
+			return errors.Wrap(helpers.ToCustomError(err), "could not create trailer inspection comment")
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *repo) GetInspection(ctx context.Context, id string) (models.GetTrailerInspectionResponse, error) {
+	return r.findInspection(ctx, `where ti.id = $1`, id)
+}
+
+func (r *repo) GetLatestInspection(ctx context.Context, trailerID string) (models.GetTrailerInspectionResponse, error) {
+	return r.findInspection(ctx, `where ti.trailer_id = $1 order by ti.created_at desc limit 1`, trailerID)
+}
+
+func (r *repo) findInspection(ctx context.Context, statement string, args ...any) (models.GetTrailerInspectionResponse, error) {
+	var (
+		response  models.GetTrailerInspectionResponse
+		createdAt time.Time
+	)
+
+	query := `
+		select
+			ti.id,
+			ti.trailer_id,
+			ti.inspection_type,
+			ti.is_empty,
+			ti.location,
+			ti.fuel_level_images,
+			ti.left_side_images,
+			ti.front_side_images,
+			ti.right_side_images,
+			ti.back_side_images,
+			ti.in_side_images,
+			ti.tire_images,
+			ti.damage_images,
+			ti.need_repair_devices,
+			ti.driver_signature_id,
+			ti.company_representative_signature_id,
+			ti.created_at
+		from trailer_inspection ti
+	` + statement
+
+	if err := r.querier.QueryRow(ctx, query, args...).Scan(
+		&response.ID,
+		&response.Trailer.ID,
+		&response.InspectionType,
+		&response.IsEmpty,
+		&response.Location,
+		pq.Array(&response.FuelLevelImages),
+		pq.Array(&response.LeftSideImages),
+		pq.Array(&response.FrontSideImages),
+		pq.Array(&response.RightSideImages),
+		pq.Array(&response.BackSideImages),
+		pq.Array(&response.InSideImages),
+		pq.Array(&response.TireImages),
+		pq.Array(&response.DamageImages),
+		pq.Array(&response.NeedRepairDevices),
+		&response.DriverSignatureID,
+		&response.CompanyRepresentativeSignatureID,
+		&createdAt,
+	); err != nil {
+		return models.GetTrailerInspectionResponse{}, helpers.ToCustomError(err)
+	}
+
+	response.CreatedAt = helpers.TimeToString(createdAt, config.DateTimeFormat, true)
+
+	comments, err := r.getInspectionComments(ctx, response.ID)
+	if err != nil {
+		return models.GetTrailerInspectionResponse{}, err
+	}
+	response.Comments = comments
+
+	return response, nil
+}
+
+func (r *repo) getInspectionComments(ctx context.Context, inspectionID string) ([]models.GetCommentResponse, error) {
+	var comments []models.GetCommentResponse
+
+	query := `
+		select
+			c.id,
+			coalesce(u.first_name || ' ' || u.last_name, u.username, ''),
+			c.text,
+			c.created_at
+		from comment c
+		left join "user" u on u.id = c.created_by
+		where c.entity_type = 'trailer_inspection' and c.entity_id = $1 and c.deleted_at is null
+		order by c.created_at
+	`
+
+	rows, err := r.querier.Query(ctx, query, inspectionID)
+	if err != nil {
+		return nil, helpers.ToCustomError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			comment   models.GetCommentResponse
+			createdAt time.Time
+		)
+
+		if err = rows.Scan(&comment.ID, &comment.CreatedBy, &comment.Text, &createdAt); err != nil {
+			return nil, helpers.ToCustomError(err)
+		}
+
+		comment.CreatedAt = helpers.TimeToString(createdAt, config.DateTimeFormat, true)
+
+		comments = append(comments, comment)
+	}
+
+	return comments, nil
+}
+
+func (r *repo) GetPickUpDropOffHistory(ctx context.Context, req models.GetTrailerPickUpDropOffHistoryRequest) (models.GetTrailerPickUpDropOffHistoryResponse, error) {
+	var (
+		response  models.GetTrailerPickUpDropOffHistoryResponse
+		statement = `WHERE ti.trailer_id = :trailer_id`
+		params    = types.M{
+			"trailer_id": req.TrailerID,
+		}
+	)
+
+	if !helpers.IsEmpty(req.Search) {
+		params["search"] = req.Search
+
+		statement += ` AND (
+			(d.first_name || ' ' || d.last_name) ilike '%' || :search || '%' OR
+			t.number ilike '%' || :search || '%' OR
+			ti.location ilike '%' || :search || '%'
+		)`
+	}
+
+	params["offset"], params["limit"] = helpers.NormalizePagination(req.Page, req.Limit)
+
+	from := `
+		FROM trailer_inspection ti
+		JOIN trailer t ON t.id = ti.trailer_id
+		JOIN driver d ON d.id = ti.driver_id
+	` + statement
+
+	stmtCount, err := r.querier.PrepareNamed(ctx, `SELECT count(1) `+from)
+	if err != nil {
+		return response, errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmtCount.Close()
+
+	if err = stmtCount.QueryRow(params).Scan(&response.Count); err != nil {
+		return response, helpers.ToCustomError(err)
+	}
+
+	query := `
+		SELECT
+			ti.id,
+			t.id,
+			t.number,
+			d.id,
+			d.first_name || ' ' || d.last_name,
+			ti.inspection_type,
+			ti.created_at
+	` + from + `
+		ORDER BY ti.created_at DESC
+		OFFSET :offset LIMIT :limit
+	`
+
+	stmt, err := r.querier.PrepareNamed(ctx, query)
+	if err != nil {
+		return response, errors.Wrap(err, "could not prepare named context")
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(params)
+	if err != nil {
+		return response, errors.Wrap(err, "could not query with params")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			history   models.TrailerPickUpDropOffHistory
+			createdAt time.Time
+		)
+
+		if err = rows.Scan(
+			&history.ID,
+			&history.TrailerID,
+			&history.TrailerNumber,
+			&history.DriverID,
+			&history.DriverName,
+			&history.InspectionType,
+			&createdAt,
+		); err != nil {
+			return response, errors.Wrap(err, "could not scan rows")
+		}
+
+		history.CreatedAt = helpers.TimeToString(createdAt, config.DateTimeFormat, true)
+
+		response.History = append(response.History, history)
+	}
+
+	return response, nil
+}
+
+// assignDriver moves trailer to given driver, empty driver closes current assignment
+func assignDriver(tx *sqlx.Tx, tenant models.Tenant, trailerID, driverID string) error {
+	if helpers.IsEmpty(driverID) {
+		return assignment_repo.UnassignTx(tx, tenant, models.UnassignDriverRequest{
+			EntityType: assignment_repo.EntityTypeTrailer,
+			EntityID:   trailerID,
+		})
+	}
+
+	return assignment_repo.AssignTx(tx, tenant, models.AssignDriverRequest{
+		ID:         uuid.New().String(),
+		DriverID:   driverID,
+		EntityType: assignment_repo.EntityTypeTrailer,
+		EntityID:   trailerID,
+	})
+}
@@ -0,0 +1,200 @@
+package two_factor_repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	"github.com/abdivasiyev/project_template/pkg/helpers"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/storage"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+)
+
+var Module = fx.Provide(New)
+
+type repo struct {
+	querier storage.Querier
+	log     logger.Logger
+}
+
+type Params struct {
+	fx.In
+	Querier storage.Querier
+	Log     logger.Logger
+}
+
+func New(params Params) repository.TwoFactor {
+	return &repo{
+		querier: params.Querier,
+		log:     params.Log,
+	}
+}
+
+func (r *repo) Get(ctx context.Context, userID string) (models.GetTwoFactorResponse, error) {
+	var response models.GetTwoFactorResponse
+
+	query := `select user_id, secret, enabled_at is not null from user_two_factor where user_id = $1`
+
+	if err := r.querier.QueryRow(ctx, query, userID).Scan(
+		&response.UserID,
+		&response.Secret,
+		&response.Enabled,
+	); err != nil {
+		return models.GetTwoFactorResponse{}, helpers.ToCustomError(err)
+	}
+
+	return response, nil
+}
+
+// SaveSecret replaces not confirmed encrypted secret of user,
+// returns models.ErrConflict when two-factor authentication is already enabled
+func (r *repo) SaveSecret(ctx context.Context, userID, secret string) error {
+	query := `
+		insert into user_two_factor (user_id, secret, created_at)
+		values ($1, $2, current_timestamp)
+		on conflict (user_id) do update set
+			secret = excluded.secret,
+			created_at = excluded.created_at
+		where user_two_factor.enabled_at is null
+	`
+
+	result, err := r.querier.Exec(ctx, query, userID, secret)
+	if err != nil {
+		return errors.Wrap(err, "could not save two factor secret")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affectedRows == 0 {
+		return models.ErrConflict
+	}
+
+	return nil
+}
+
+// Enable confirms secret of user and replaces recovery codes
+func (r *repo) Enable(ctx context.Context, userID string, recoveryCodeHashes []string) error {
+	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+
+	result, err := tx.Exec(`update user_two_factor set enabled_at = current_timestamp where user_id = $1 and enabled_at is null`, userID)
+	if err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not enable two factor")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if affectedRows == 0 {
+		_ = tx.Rollback()
+		return models.ErrNotFound
+	}
+
+	if _, err = tx.Exec(`delete from user_recovery_code where user_id = $1`, userID); err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not delete recovery codes")
+	}
+
+	for _, codeHash := range recoveryCodeHashes {
+		if _, err = tx.Exec(
+			`insert into user_recovery_code (id, user_id, code_hash, created_at) values ($1, $2, $3, current_timestamp)`,
+			uuid.New().String(),
+			userID,
+			codeHash,
+		); err != nil {
+			_ = tx.Rollback()
+			return errors.Wrap(err, "could not create recovery code")
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *repo) Disable(ctx context.Context, userID string) error {
+	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+
+	if _, err = tx.Exec(`delete from user_recovery_code where user_id = $1`, userID); err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not delete recovery codes")
+	}
+
+	result, err := tx.Exec(`delete from user_two_factor where user_id = $1`, userID)
+	if err != nil {
+		_ = tx.Rollback()
+		return errors.Wrap(err, "could not disable two factor")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if affectedRows == 0 {
+		_ = tx.Rollback()
+		return models.ErrNotFound
+	}
+
+	return tx.Commit()
+}
+
+// GetRecoveryCodes returns not used recovery codes of user
+func (r *repo) GetRecoveryCodes(ctx context.Context, userID string) ([]models.RecoveryCode, error) {
+	var response []models.RecoveryCode
+
+	query := `select id, code_hash from user_recovery_code where user_id = $1 and used_at is null order by created_at`
+
+	rows, err := r.querier.Query(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not query recovery codes")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var code models.RecoveryCode
+
+		if err = rows.Scan(&code.ID, &code.CodeHash); err != nil {
+			return nil, errors.Wrap(err, "could not scan rows")
+		}
+
+		response = append(response, code)
+	}
+
+	return response, nil
+}
+
+// UseRecoveryCode marks recovery code as used,
+// returns models.ErrNotFound when code is already used
+func (r *repo) UseRecoveryCode(ctx context.Context, id string) error {
+	result, err := r.querier.Exec(ctx, `update user_recovery_code set used_at = current_timestamp where id = $1 and used_at is null`, id)
+	if err != nil {
+		return errors.Wrap(err, "could not use recovery code")
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affectedRows == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
@@ -44,6 +44,8 @@ func New(params Params) repository.User {
 }
 
 func (r *repo) Create(ctx context.Context, req models.CreateUserRequest) error {
+	companyID := tenantCompanyID(ctx, req.CompanyID)
+
 	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
 	if err != nil {
 		return errors.Wrap(err, "could not begin transaction")
@@ -54,7 +56,7 @@ func (r *repo) Create(ctx context.Context, req models.CreateUserRequest) error {
 	_, err = tx.Exec(
 		query,
 		req.ID,
-		helpers.ToNullString(req.CompanyID),
+		helpers.ToNullString(companyID),
 		req.Username,
 		req.Password,
 		helpers.ToNullString(req.FirstName),
@@ -82,6 +84,8 @@ func (r *repo) Create(ctx context.Context, req models.CreateUserRequest) error {
 }
 
 func (r *repo) Update(ctx context.Context, req models.UpdateUserRequest) error {
+	tenant := helpers.GetTenant(ctx)
+
 	tx, err := r.querier.Begin(ctx, &sql.TxOptions{})
 	if err != nil {
 		return errors.Wrap(err, "could not begin transaction")
@@ -97,23 +101,37 @@ func (r *repo) Update(ctx context.Context, req models.UpdateUserRequest) error {
 			phone = $7,
 			updated_at = current_timestamp
 		where id = $1 and deleted_at is null
+		  and ($8 or company_id is not distinct from $9)
 	`
 
-	_, err = tx.Exec(
+	result, err := tx.Exec(
 		query,
 		req.ID,
-		helpers.ToNullString(req.CompanyID),
+		helpers.ToNullString(tenantCompanyID(ctx, req.CompanyID)),
 		req.Username,
 		helpers.ToNullString(req.FirstName),
 		helpers.ToNullString(req.LastName),
 		req.NewPassword,
 		helpers.ToNullString(req.Phone),
+		tenant.All,
+		helpers.ToNullString(tenant.CompanyID),
 	)
 	if err != nil {
 		_ = tx.Rollback()
 		return errors.Wrap(err, "could not update user")
 	}
 
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if affectedRows == 0 {
+		_ = tx.Rollback()
+		return models.ErrNotFound
+	}
+
 	query = `with d as (delete from user_role where user_id = $1) insert into user_role (user_id, role_id) values ($1, $2)`
 	_, err = tx.Exec(
 		query,
@@ -164,14 +182,20 @@ func (r *repo) UpdateProfile(ctx context.Context, req models.UpdateProfileReques
 }
 
 func (r *repo) Delete(ctx context.Context, id string) error {
+	tenant := helpers.GetTenant(ctx)
+
 	query := `
-		update "user" set deleted_at = current_timestamp where id = $1 and deleted_at is null
+		update "user" set deleted_at = current_timestamp
+		where id = $1 and deleted_at is null
+		  and ($2 or company_id is not distinct from $3)
 	`
 
 	_, err := r.querier.Exec(
 		ctx,
 		query,
 		id,
+		tenant.All,
+		helpers.ToNullString(tenant.CompanyID),
 	)
 	return errors.Wrap(err, "could not delete user")
 }
@@ -182,16 +206,30 @@ func (r *repo) GetByUsername(ctx context.Context, username string) (models.GetUs
 	})
 }
 
+// Get returns user of tenant, users can always get themselves
 func (r *repo) Get(ctx context.Context, id string) (models.GetUserResponse, error) {
-	return r.findByOne(ctx, "WHERE u.id = :id AND u.deleted_at is null", map[string]interface{}{
-		"id": id,
+	tenant := helpers.GetTenant(ctx)
+
+	return r.findByOne(ctx, `WHERE u.id = :id AND u.deleted_at is null AND (
+		:tenant_all OR
+		u.id = :tenant_user_id OR
+		u.company_id IS NOT DISTINCT FROM :tenant_id
+	)`, map[string]interface{}{
+		"id":             id,
+		"tenant_all":     tenant.All,
+		"tenant_user_id": helpers.ToNullString(tenant.UserID),
+		"tenant_id":      helpers.ToNullString(tenant.CompanyID),
 	})
 }
 
 func (r *repo) GetAll(ctx context.Context, req models.GetAllUsersRequest) (models.GetAllUsersResponse, error) {
 	var (
-		statement = `WHERE u.deleted_at is null`
-		params    = make(map[string]interface{})
+		tenant    = helpers.GetTenant(ctx)
+		statement = `WHERE u.deleted_at is null AND (:tenant_all OR u.company_id IS NOT DISTINCT FROM :tenant_id)`
+		params    = map[string]interface{}{
+			"tenant_all": tenant.All,
+			"tenant_id":  helpers.ToNullString(tenant.CompanyID),
+		}
 	)
 
 	if req.Search != "" {
@@ -387,3 +425,13 @@ func (r *repo) findByOne(ctx context.Context, statement string, params map[strin
 
 	return user, nil
 }
+
+// tenantCompanyID returns company of tenant for users which are not allowed to see all companies
+func tenantCompanyID(ctx context.Context, companyID string) string {
+	tenant := helpers.GetTenant(ctx)
+	if tenant.All {
+		return companyID
+	}
+
+	return tenant.CompanyID
+}
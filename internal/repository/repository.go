@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/abdivasiyev/project_template/internal/models"
 )
@@ -13,6 +14,9 @@ type App interface {
 type File interface {
 	Create(ctx context.Context, request models.GetFileResponse) error
 	Get(ctx context.Context, id string) (models.GetFileResponse, error)
+	FindMissing(ctx context.Context, ids []string) ([]string, error)
+	GetByIDs(ctx context.Context, ids []string) ([]models.GetFileResponse, error)
+	GetFilesForZipping(ctx context.Context, entityType, entityID string) ([]models.FilesForZippingResponse, error)
 }
 
 type Permission interface {
@@ -41,3 +45,165 @@ type User interface {
 	GetAll(ctx context.Context, req models.GetAllUsersRequest) (models.GetAllUsersResponse, error)
 	Delete(ctx context.Context, id string) error
 }
+
+// Truck provides truck database functions
+type Truck interface {
+	Create(ctx context.Context, req models.CreateTruckRequest) error
+	Update(ctx context.Context, req models.CreateTruckRequest) error
+	Delete(ctx context.Context, id string) error
+	Get(ctx context.Context, id string) (models.GetTruckResponse, error)
+	GetAll(ctx context.Context, req models.GetAllTrucksRequest) (models.GetAllTrucksResponse, error)
+	CreateInspection(ctx context.Context, req models.CreateTruckInspectionRequest) error
+	GetInspection(ctx context.Context, id string) (models.GetTruckInspectionResponse, error)
+	GetLatestInspection(ctx context.Context, truckID, inspectionType string) (models.GetTruckInspectionResponse, error)
+}
+
+// Trailer provides trailer and trailer inspection database functions
+type Trailer interface {
+	Create(ctx context.Context, req models.CreateTrailerRequest) error
+	Update(ctx context.Context, req models.CreateTrailerRequest) error
+	Delete(ctx context.Context, id string) error
+	Get(ctx context.Context, id string) (models.GetTrailerResponse, error)
+	GetAll(ctx context.Context, req models.GetAllTrailersRequest) (models.GetAllTrailersResponse, error)
+	CreateInspection(ctx context.Context, req models.CreateTrailerInspectionRequest) error
+	GetInspection(ctx context.Context, id string) (models.GetTrailerInspectionResponse, error)
+	GetLatestInspection(ctx context.Context, trailerID string) (models.GetTrailerInspectionResponse, error)
+	GetPickUpDropOffHistory(ctx context.Context, req models.GetTrailerPickUpDropOffHistoryRequest) (models.GetTrailerPickUpDropOffHistoryResponse, error)
+}
+
+// Car provides company car and key handover database functions
+type Car interface {
+	Create(ctx context.Context, req models.CreateCarRequest) error
+	Update(ctx context.Context, req models.CreateCarRequest) error
+	Delete(ctx context.Context, id string) error
+	Get(ctx context.Context, id string) (models.GetCarResponse, error)
+	GetAll(ctx context.Context, req models.GetAllCarsRequest) (models.GetAllCarsResponse, error)
+	CreatePickupDropOff(ctx context.Context, req models.CarPickupDropOffRequest) error
+	GetPickupDropOff(ctx context.Context, id string) (models.GetCarPickupDropOffResponse, error)
+	GetPickupDropOffHistory(ctx context.Context, req models.GetCarPickupDropOffHistoryRequest) (models.GetCarPickupDropOffHistoryResponse, error)
+}
+
+// Driver provides driver onboarding database functions
+type Driver interface {
+	Create(ctx context.Context, req models.CreateDriverRequest) error
+	Update(ctx context.Context, req models.CreateDriverRequest) error
+	Get(ctx context.Context, id string) (models.GetDriverResponse, error)
+	GetAll(ctx context.Context, req models.GetAllDriversRequest) (models.GetAllDriversResponse, error)
+	GetDepartmentStatistics(ctx context.Context, driverIDs []string) (map[string][]models.DepartmentStatisticResponse, error)
+}
+
+// Form provides dynamic form definition database functions
+type Form interface {
+	Create(ctx context.Context, req models.CreateFormRequest) error
+	Update(ctx context.Context, req models.CreateFormRequest) error
+	Delete(ctx context.Context, id string) error
+	Get(ctx context.Context, id string) (models.GetFormResponse, error)
+	GetAll(ctx context.Context, req models.GetAllFormRequest) (models.GetAllFormResponse, error)
+	CreateVersion(ctx context.Context, form models.GetFormResponse) error
+	GetVersion(ctx context.Context, id string, version int) (models.GetFormResponse, error)
+	GetVersions(ctx context.Context, id string) (models.GetAllFormVersionsResponse, error)
+	CreateGroup(ctx context.Context, req models.CreateFormGroupRequest) error
+	UpdateGroup(ctx context.Context, req models.CreateFormGroupRequest) ([]string, error)
+	GetGroup(ctx context.Context, id string) (models.GetFormGroupResponse, error)
+	GetStepFields(ctx context.Context, stepID string) ([]models.GetFormFieldResponse, error)
+	SetFieldValues(ctx context.Context, req models.SetFormFieldValue) error
+	GetFieldValueHistory(ctx context.Context, req models.GetFormFieldValueHistoryRequest) (models.GetFormFieldValueHistoryResponse, error)
+	RevertFieldValue(ctx context.Context, req models.RevertFormFieldValueRequest) error
+	GetFieldRevisionStepID(ctx context.Context, req models.RevertFormFieldValueRequest) (string, error)
+}
+
+// Status provides status catalog database functions
+type Status interface {
+	Create(ctx context.Context, req models.CreateStatusRequest) error
+	Update(ctx context.Context, req models.UpdateStatusRequest) error
+	Delete(ctx context.Context, id string) error
+	Reorder(ctx context.Context, entityType string, ids []string) error
+	Get(ctx context.Context, id string) (models.GetStatusResponse, string, error)
+	GetByEntityType(ctx context.Context, entityType string) ([]models.GetStatusResponse, error)
+	CreateTransition(ctx context.Context, req models.CreateStatusTransitionRequest) error
+	DeleteTransition(ctx context.Context, id string) error
+	GetTransitions(ctx context.Context, entityType string) (models.GetAllStatusTransitionsResponse, error)
+	IsTransitionAllowed(ctx context.Context, fromStatusID, toStatusID, userID string) (bool, error)
+}
+
+// Assignment provides history of driver assignments to trucks and trailers
+type Assignment interface {
+	Assign(ctx context.Context, req models.AssignDriverRequest) error
+	Unassign(ctx context.Context, req models.UnassignDriverRequest) error
+	GetHistory(ctx context.Context, req models.GetAssignmentHistoryRequest) (models.GetAssignmentHistoryResponse, error)
+}
+
+// Comment provides comments attached to any entity
+type Comment interface {
+	CheckEntity(ctx context.Context, entityType, entityID, driverID string) error
+	Create(ctx context.Context, req models.CreateCommentRequest) error
+	Update(ctx context.Context, req models.UpdateCommentRequest) error
+	Delete(ctx context.Context, id string) error
+	Get(ctx context.Context, id string) (models.GetCommentResponse, error)
+	GetAll(ctx context.Context, req models.GetAllCommentsRequest) (models.GetAllCommentsResponse, error)
+}
+
+// Session provides registry of user login sessions
+type Session interface {
+	Save(ctx context.Context, req models.SaveSessionRequest) error
+	Touch(ctx context.Context, id string) error
+	Revoke(ctx context.Context, req models.RevokeSessionRequest) error
+	RevokeByUser(ctx context.Context, userID string) error
+	GetAll(ctx context.Context, req models.GetAllSessionsRequest) (models.GetAllSessionsResponse, error)
+}
+
+// TwoFactor provides TOTP secrets and recovery codes of users
+type TwoFactor interface {
+	Get(ctx context.Context, userID string) (models.GetTwoFactorResponse, error)
+	SaveSecret(ctx context.Context, userID, secret string) error
+	Enable(ctx context.Context, userID string, recoveryCodeHashes []string) error
+	Disable(ctx context.Context, userID string) error
+	GetRecoveryCodes(ctx context.Context, userID string) ([]models.RecoveryCode, error)
+	UseRecoveryCode(ctx context.Context, id string) error
+}
+
+// Entity provides lookup of id/name pairs of registered entity types
+type Entity interface {
+	CanAddItem(ctx context.Context, entityType string) (bool, error)
+	Create(ctx context.Context, req models.CreateEntityRequest) error
+	GetAll(ctx context.Context, req models.GetEntitiesRequest) (models.GetEntitiesResponse, error)
+}
+
+// Dashboard provides aggregated statistics database functions
+type Dashboard interface {
+	Get(ctx context.Context, periodStart, previousPeriodStart time.Time) (models.GetDashboardResponse, error)
+}
+
+// Company provides company (tenant) database functions
+type Company interface {
+	Create(ctx context.Context, req models.CreateCompanyRequest) error
+	Update(ctx context.Context, req models.UpdateCompanyRequest) error
+	Delete(ctx context.Context, id string) error
+	Get(ctx context.Context, id string) (models.GetCompanyResponse, error)
+	GetAll(ctx context.Context, req models.GetAllCompaniesRequest) (models.GetAllCompaniesResponse, error)
+}
+
+// Department provides onboarding department database functions
+type Department interface {
+	Create(ctx context.Context, req models.CreateDepartmentRequest) error
+	Update(ctx context.Context, req models.CreateDepartmentRequest) error
+	Delete(ctx context.Context, id string) error
+	Reorder(ctx context.Context, ids []string) error
+	Get(ctx context.Context, id string) (models.GetDepartmentResponse, error)
+	GetAll(ctx context.Context, req models.GetAllDepartmentsRequest) (models.GetAllDepartmentsResponse, error)
+}
+
+// Step provides onboarding step database functions
+type Step interface {
+	Create(ctx context.Context, req models.CreateStepRequest) error
+	Update(ctx context.Context, req models.CreateStepRequest) error
+	Delete(ctx context.Context, id string) error
+	Get(ctx context.Context, id string) (models.GetStepResponse, error)
+	GetAll(ctx context.Context, departmentID string) (models.GetAllStepsResponse, error)
+	GetDriverSteps(ctx context.Context, req models.GetAllStepsRequest) ([]models.GetStepResponse, error)
+	UpdateStatus(ctx context.Context, req models.UpdateStepStatusRequest) error
+	GetDriverStepStatus(ctx context.Context, driverID, stepID string) (string, error)
+	GetDepartmentAccess(ctx context.Context, userID, departmentID string) (models.HasAccessResponse, error)
+	GetDepartmentAccesses(ctx context.Context, departmentID string) (models.GetAllDepartmentAccessResponse, error)
+	SetDepartmentAccess(ctx context.Context, req models.SetDepartmentAccessRequest) error
+}
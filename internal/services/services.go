@@ -2,11 +2,25 @@ package services
 
 import (
 	appV1 "github.com/abdivasiyev/project_template/internal/services/v1/app_service"
+	assignmentV1 "github.com/abdivasiyev/project_template/internal/services/v1/assignment_service"
 	authV1 "github.com/abdivasiyev/project_template/internal/services/v1/auth_service"
+	carV1 "github.com/abdivasiyev/project_template/internal/services/v1/car_service"
+	commentV1 "github.com/abdivasiyev/project_template/internal/services/v1/comment_service"
+	companyV1 "github.com/abdivasiyev/project_template/internal/services/v1/company_service"
+	dashboardV1 "github.com/abdivasiyev/project_template/internal/services/v1/dashboard_service"
+	departmentV1 "github.com/abdivasiyev/project_template/internal/services/v1/department_service"
+	driverV1 "github.com/abdivasiyev/project_template/internal/services/v1/driver_service"
+	entityV1 "github.com/abdivasiyev/project_template/internal/services/v1/entity_service"
 	fileV1 "github.com/abdivasiyev/project_template/internal/services/v1/file_service"
+	formV1 "github.com/abdivasiyev/project_template/internal/services/v1/form_service"
 	jobV1 "github.com/abdivasiyev/project_template/internal/services/v1/job_service"
 	middlewareV1 "github.com/abdivasiyev/project_template/internal/services/v1/middleware_service"
 	roleV1 "github.com/abdivasiyev/project_template/internal/services/v1/role_service"
+	sessionV1 "github.com/abdivasiyev/project_template/internal/services/v1/session_service"
+	statusV1 "github.com/abdivasiyev/project_template/internal/services/v1/status_service"
+	stepV1 "github.com/abdivasiyev/project_template/internal/services/v1/step_service"
+	trailerV1 "github.com/abdivasiyev/project_template/internal/services/v1/trailer_service"
+	truckV1 "github.com/abdivasiyev/project_template/internal/services/v1/truck_service"
 	userV1 "github.com/abdivasiyev/project_template/internal/services/v1/user_service"
 	"go.uber.org/fx"
 )
@@ -19,4 +33,18 @@ var Module = fx.Options(
 	roleV1.Module,
 	userV1.Module,
 	appV1.Module,
+	truckV1.Module,
+	trailerV1.Module,
+	carV1.Module,
+	driverV1.Module,
+	formV1.Module,
+	stepV1.Module,
+	departmentV1.Module,
+	companyV1.Module,
+	dashboardV1.Module,
+	entityV1.Module,
+	assignmentV1.Module,
+	statusV1.Module,
+	commentV1.Module,
+	sessionV1.Module,
 )
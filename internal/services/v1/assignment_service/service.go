@@ -0,0 +1,94 @@
+package assignment_service
+
+import (
+	"context"
+
+	"github.com/abdivasiyev/project_template/config"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	v1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/sentry"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var Module = fx.Provide(NewService)
+
+type service struct {
+	environment          string
+	log                  logger.Logger
+	sentry               sentry.Handler
+	assignmentRepository repository.Assignment
+}
+
+type Params struct {
+	fx.In
+	Config               config.Config
+	Log                  logger.Logger
+	Sentry               sentry.Handler
+	AssignmentRepository repository.Assignment
+}
+
+func NewService(params Params) v1.AssignmentServiceV1 {
+	return &service{
+		environment:          params.Config.GetString(config.EnvironmentKey),
+		log:                  params.Log,
+		sentry:               params.Sentry,
+		assignmentRepository: params.AssignmentRepository,
+	}
+}
+
+// Assign makes driver active driver of truck or trailer and returns active assignment
+func (s *service) Assign(ctx context.Context, req models.AssignDriverRequest) (models.GetAssignmentResponse, error) {
+	req.ID = uuid.New().String()
+
+	if err := s.assignmentRepository.Assign(ctx, req); err != nil {
+		if !errors.Is(err, models.ErrNotFound) && !errors.Is(err, models.ErrConflict) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not assign driver", zap.Error(err), zap.Any("req", req))
+		}
+		return models.GetAssignmentResponse{}, errors.Wrap(err, "could not assign driver")
+	}
+
+	history, err := s.GetHistory(ctx, models.GetAssignmentHistoryRequest{
+		PageRequest: models.PageRequest{Page: 1, Limit: 1},
+		DriverID:    req.DriverID,
+		EntityType:  req.EntityType,
+		EntityID:    req.EntityID,
+		Active:      true,
+	})
+	if err != nil {
+		return models.GetAssignmentResponse{}, err
+	}
+
+	if len(history.Assignments) == 0 {
+		return models.GetAssignmentResponse{}, models.ErrNotFound
+	}
+
+	return history.Assignments[0], nil
+}
+
+func (s *service) Unassign(ctx context.Context, req models.UnassignDriverRequest) error {
+	err := s.assignmentRepository.Unassign(ctx, req)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not unassign driver", zap.Error(err), zap.Any("req", req))
+		}
+	}
+	return err
+}
+
+func (s *service) GetHistory(ctx context.Context, req models.GetAssignmentHistoryRequest) (models.GetAssignmentHistoryResponse, error) {
+	response, err := s.assignmentRepository.GetHistory(ctx, req)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get assignment history", zap.Error(err), zap.Any("req", req))
+		}
+	}
+	return response, err
+}
@@ -0,0 +1,185 @@
+package auth_service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abdivasiyev/project_template/config"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/pkg/helpers"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultLoginMaxAttempts   = 5
+	defaultLoginIPMaxAttempts = 20
+	defaultLoginLockout       = time.Minute
+	defaultLoginMaxLockout    = time.Hour
+	// loginAttemptsDuration is how long failures are remembered after the last one,
+	// number of reached limits is derived from failures, so it is remembered as long as them
+	loginAttemptsDuration = 24 * time.Hour
+)
+
+// lockoutPolicy limits failed logins per username and per ip address,
+// each next lockout is twice longer than previous one
+type lockoutPolicy struct {
+	maxAttempts   int
+	ipMaxAttempts int
+	lockout       time.Duration
+	maxLockout    time.Duration
+}
+
+func newLockoutPolicy(cfg config.Config) lockoutPolicy {
+	policy := lockoutPolicy{
+		maxAttempts:   cfg.GetInt(config.SecurityLoginMaxAttemptsKey),
+		ipMaxAttempts: cfg.GetInt(config.SecurityLoginIPMaxAttemptsKey),
+		lockout:       cfg.GetDuration(config.SecurityLoginLockoutKey),
+		maxLockout:    cfg.GetDuration(config.SecurityLoginMaxLockoutKey),
+	}
+
+	// empty environment variables must not lock every login
+	if policy.maxAttempts <= 0 {
+		policy.maxAttempts = defaultLoginMaxAttempts
+	}
+	if policy.ipMaxAttempts <= 0 {
+		policy.ipMaxAttempts = defaultLoginIPMaxAttempts
+	}
+	if policy.lockout <= 0 {
+		policy.lockout = defaultLoginLockout
+	}
+	if policy.maxLockout < policy.lockout {
+		policy.maxLockout = defaultLoginMaxLockout
+	}
+
+	return policy
+}
+
+// lockoutDuration doubles base lockout for every previous lockout
+func (p lockoutPolicy) lockoutDuration(lockouts int) time.Duration {
+	duration := p.lockout
+
+	for i := 1; i < lockouts && duration < p.maxLockout; i++ {
+		duration *= 2
+	}
+
+	if duration > p.maxLockout {
+		return p.maxLockout
+	}
+
+	return duration
+}
+
+// Unlock resets failed login counters of user or ip address
+func (s *service) Unlock(ctx context.Context, req models.UnlockLoginRequest) (models.SuccessResponse, error) {
+	var keys []string
+
+	if !helpers.IsEmpty(req.UserID) {
+		user, err := s.userRepository.Get(ctx, req.UserID)
+		if err != nil {
+			if !errors.Is(err, models.ErrNotFound) {
+				s.sentry.HandleError(err)
+				s.log.Error("could not get user", zap.Error(err), zap.Any("req", req))
+			}
+			return models.SuccessResponse{}, err
+		}
+
+		keys = append(keys, loginUsernameKey(user.Username), loginLockedKey(loginUsernameKey(user.Username)))
+	}
+
+	if !helpers.IsEmpty(req.IP) {
+		keys = append(keys, loginIPKey(req.IP), loginLockedKey(loginIPKey(req.IP)))
+	}
+
+	if err := s.cache.Delete(ctx, keys...); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not delete login attempts", zap.Error(err), zap.Any("req", req))
+		return models.SuccessResponse{}, errors.Wrap(err, "could not unlock login")
+	}
+
+	s.securityEvent("login_unlocked", zap.String("userID", req.UserID), zap.String("ip", req.IP), zap.String("unlockedBy", req.UnlockedBy))
+
+	return models.SuccessResponse{Ok: true}, nil
+}
+
+// checkLoginLocked returns models.ErrTooManyTries while username or ip address is locked
+func (s *service) checkLoginLocked(ctx context.Context, username, ip string) error {
+	for _, key := range []string{loginUsernameKey(username), loginIPKey(ip)} {
+		if _, err := s.cache.Get(ctx, loginLockedKey(key)); err != nil {
+			if !errors.Is(err, models.ErrNotFound) {
+				s.sentry.HandleError(err)
+				s.log.Error("could not get login lockout", zap.Error(err), zap.String("key", key))
+			}
+			continue
+		}
+
+		s.securityEvent("login_locked_attempt", zap.String("username", username), zap.String("ip", ip))
+		return models.ErrTooManyTries
+	}
+
+	return nil
+}
+
+// recordLoginFailure counts failure for both username and ip address
+func (s *service) recordLoginFailure(ctx context.Context, username, ip, reason string) {
+	s.securityEvent("login_failure", zap.String("username", username), zap.String("ip", ip), zap.String("reason", reason))
+
+	s.incrementLoginFailures(ctx, loginUsernameKey(username), s.lockout.maxAttempts, zap.String("username", username))
+	s.incrementLoginFailures(ctx, loginIPKey(ip), s.lockout.ipMaxAttempts, zap.String("ip", ip))
+}
+
+// incrementLoginFailures counts failure atomically, so parallel requests can not lose failures,
+// every maxAttempts failures lock key for lockout derived from number of reached limits
+func (s *service) incrementLoginFailures(ctx context.Context, key string, maxAttempts int, field zap.Field) {
+	duration := loginAttemptsDuration
+	if s.lockout.maxLockout > duration {
+		duration = s.lockout.maxLockout
+	}
+
+	failures, err := s.cache.Incr(ctx, key, duration)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not increment login failures", zap.Error(err), zap.String("key", key))
+		return
+	}
+
+	// only one request gets failure which reaches the limit
+	if failures%int64(maxAttempts) != 0 {
+		return
+	}
+
+	lockouts := int(failures / int64(maxAttempts))
+	lockout := s.lockout.lockoutDuration(lockouts)
+
+	if err = s.cache.Set(ctx, loginLockedKey(key), lockouts, lockout); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not save login lockout", zap.Error(err), zap.String("key", key))
+		return
+	}
+
+	s.securityEvent("login_locked", field, zap.Int("lockouts", lockouts), zap.Duration("duration", lockout))
+}
+
+// resetLoginFailures forgets failures of username after successful login,
+// ip counter is kept, so attacker can not reset it with own account
+func (s *service) resetLoginFailures(ctx context.Context, username string) {
+	if err := s.cache.Delete(ctx, loginUsernameKey(username)); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not delete login attempts", zap.Error(err), zap.String("username", username))
+	}
+}
+
+func loginUsernameKey(username string) string {
+	return fmt.Sprintf("auth:login:username:%s", strings.ToLower(username))
+}
+
+func loginIPKey(ip string) string {
+	return fmt.Sprintf("auth:login:ip:%s", ip)
+}
+
+// loginLockedKey is kept while username or ip address of login counter key is locked
+func loginLockedKey(key string) string {
+	return key + ":locked"
+}
@@ -0,0 +1,90 @@
+package auth_service
+
+import (
+	"context"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/pkg/helpers"
+	"github.com/abdivasiyev/project_template/pkg/security/jwt"
+	"github.com/abdivasiyev/project_template/pkg/validator"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+func (s *service) Logout(ctx context.Context, request models.LogoutRequest) (models.SuccessResponse, error) {
+	payload, err := s.security.VerifyPayload(ctx, request.AccessToken, false)
+	if err != nil {
+		return models.SuccessResponse{}, models.ErrUnauthorized
+	}
+
+	user := payload.User
+
+	if !helpers.IsEmpty(request.RefreshToken) {
+		tokenUser, err := s.security.VerifyToken(ctx, request.RefreshToken, true)
+		if err != nil {
+			if errors.Is(err, jwt.ErrInvalidToken) || errors.Is(err, jwt.ErrExpiredToken) || errors.Is(err, jwt.ErrRevokedToken) {
+				return models.SuccessResponse{}, validator.NewValidationError("refresh_token", "invalid refresh token")
+			}
+
+			s.sentry.HandleError(err)
+			s.log.Error("could not verify refresh token", zap.Error(err))
+			return models.SuccessResponse{}, errors.Wrap(err, "could not verify refresh token")
+		}
+
+		if tokenUser.ID != user.ID {
+			return models.SuccessResponse{}, validator.NewValidationError("refresh_token", "invalid refresh token")
+		}
+
+		if err = s.security.RevokeToken(ctx, request.RefreshToken); err != nil {
+			s.sentry.HandleError(err)
+			s.log.Error("could not revoke refresh token", zap.Error(err), zap.String("userID", user.ID))
+			return models.SuccessResponse{}, errors.Wrap(err, "could not revoke refresh token")
+		}
+	}
+
+	if err = s.security.RevokeToken(ctx, request.AccessToken); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not revoke access token", zap.Error(err), zap.String("userID", user.ID))
+		return models.SuccessResponse{}, errors.Wrap(err, "could not revoke access token")
+	}
+
+	if !helpers.IsEmpty(payload.Family) {
+		if err = s.revokeSession(ctx, payload.Family, user.ID); err != nil {
+			return models.SuccessResponse{}, err
+		}
+	}
+
+	return models.SuccessResponse{Ok: true}, nil
+}
+
+func (s *service) LogoutAll(ctx context.Context, userID string) (models.SuccessResponse, error) {
+	if err := s.security.RevokeUserTokens(ctx, userID); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not revoke user tokens", zap.Error(err), zap.String("userID", userID))
+		return models.SuccessResponse{}, errors.Wrap(err, "could not revoke user tokens")
+	}
+
+	if err := s.sessionRepository.RevokeByUser(ctx, userID); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not revoke user sessions", zap.Error(err), zap.String("userID", userID))
+		return models.SuccessResponse{}, errors.Wrap(err, "could not revoke user sessions")
+	}
+
+	return models.SuccessResponse{Ok: true}, nil
+}
+
+func (s *service) revokeSession(ctx context.Context, sessionID, userID string) error {
+	if err := s.security.RevokeFamily(ctx, sessionID); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not revoke token family", zap.Error(err), zap.String("sessionID", sessionID))
+		return errors.Wrap(err, "could not revoke token family")
+	}
+
+	err := s.sessionRepository.Revoke(ctx, models.RevokeSessionRequest{ID: sessionID, UserID: userID})
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		s.sentry.HandleError(err)
+		s.log.Error("could not revoke session", zap.Error(err), zap.String("sessionID", sessionID))
+		return errors.Wrap(err, "could not revoke session")
+	}
+
+	return nil
+}
@@ -144,6 +144,9 @@ func (s *service) ResetPassword(ctx context.Context, req models.ResetPasswordReq
 }
 
 func (s *service) updateUserPassword(ctx context.Context, cacheKey, userID, password string) error {
+	// user is not authenticated while resetting password, so user is not limited to company
+	ctx = helpers.WithSystemTenant(ctx)
+
 	user, err := s.userRepository.Get(ctx, userID)
 	if err != nil {
 		if !errors.Is(err, models.ErrNotFound) {
@@ -11,6 +11,7 @@ import (
 
 	"github.com/abdivasiyev/project_template/internal/models"
 	"github.com/abdivasiyev/project_template/internal/repository"
+	"github.com/abdivasiyev/project_template/pkg/helpers"
 	"github.com/abdivasiyev/project_template/pkg/logger"
 	"github.com/abdivasiyev/project_template/pkg/security"
 	"github.com/abdivasiyev/project_template/pkg/security/jwt"
@@ -27,9 +28,12 @@ type service struct {
 	sentry               sentry.Handler
 	userRepository       repository.User
 	permissionRepository repository.Permission
+	sessionRepository    repository.Session
+	twoFactorRepository  repository.TwoFactor
 	cache                storage.Cacher
 	mailer               mailer.Mailer
 	security             security.Handler
+	lockout              lockoutPolicy
 }
 
 type Params struct {
@@ -39,6 +43,8 @@ type Params struct {
 	Sentry               sentry.Handler
 	UserRepository       repository.User
 	PermissionRepository repository.Permission
+	SessionRepository    repository.Session
+	TwoFactorRepository  repository.TwoFactor
 	Cache                storage.Cacher
 	Mailer               mailer.Mailer
 	Security             security.Handler
@@ -51,17 +57,25 @@ func NewService(params Params) v1.AuthServiceV1 {
 		sentry:               params.Sentry,
 		userRepository:       params.UserRepository,
 		permissionRepository: params.PermissionRepository,
+		sessionRepository:    params.SessionRepository,
+		twoFactorRepository:  params.TwoFactorRepository,
 		security:             params.Security,
 		cache:                params.Cache,
 		mailer:               params.Mailer,
+		lockout:              newLockoutPolicy(params.Config),
 	}
 }
 
 func (s *service) Login(ctx context.Context, request models.LoginRequest) (models.AuthenticationResponse, error) {
+	if err := s.checkLoginLocked(ctx, request.Username, request.IP); err != nil {
+		return models.AuthenticationResponse{}, err
+	}
+
 	user, err := s.userRepository.GetByUsername(ctx, request.Username)
 
 	if errors.Is(err, models.ErrNotFound) {
 		s.log.Warn("user not found with username", zap.Any("username", request.Username))
+		s.recordLoginFailure(ctx, request.Username, request.IP, "user_not_found")
 		return models.AuthenticationResponse{}, customValidator.NewValidationError("username", "incorrect username or password")
 	} else if err != nil {
 		s.sentry.HandleError(err)
@@ -77,18 +91,43 @@ func (s *service) Login(ctx context.Context, request models.LoginRequest) (model
 	}
 
 	if !valid {
-		s.log.Warn("password for user is not valid", zap.Any("request", request))
+		s.log.Warn("password for user is not valid", zap.String("username", request.Username))
+		s.recordLoginFailure(ctx, request.Username, request.IP, "invalid_password")
 		return models.AuthenticationResponse{}, customValidator.NewValidationError("username", "incorrect username or password")
 	}
 
-	accessToken, refreshToken, err := s.security.GenerateToken(user)
+	twoFactor, err := s.twoFactorRepository.Get(ctx, user.ID)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		s.sentry.HandleError(err)
+		s.log.Error("could not get two factor", zap.Error(err), zap.String("userID", user.ID))
+		return models.AuthenticationResponse{}, errors.Wrap(err, "could not get two factor")
+	}
+
+	if err == nil && twoFactor.Enabled {
+		return s.createTwoFactorChallenge(ctx, user)
+	}
+
+	s.resetLoginFailures(ctx, request.Username)
+
+	return s.authenticate(ctx, user, request.UserAgent, request.IP)
+}
+
+// authenticate starts new session of user
+func (s *service) authenticate(ctx context.Context, user models.GetUserResponse, userAgent, ip string) (models.AuthenticationResponse, error) {
+	accessToken, refreshToken, err := s.security.GenerateToken(ctx, user)
 
 	if err != nil {
 		s.sentry.HandleError(err)
-		s.log.Error("could not generate token", zap.Any("request", request), zap.Error(err))
+		s.log.Error("could not generate token", zap.String("userID", user.ID), zap.Error(err))
 		return models.AuthenticationResponse{}, errors.Wrap(err, "could not generate token")
 	}
 
+	if err = s.saveSession(ctx, user.ID, accessToken, refreshToken, userAgent, ip); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not save session", zap.Error(err), zap.String("userID", user.ID))
+		return models.AuthenticationResponse{}, errors.Wrap(err, "could not save session")
+	}
+
 	permissions, err := s.permissionRepository.GetByUser(ctx, user.ID)
 	if err != nil {
 		s.sentry.HandleError(err)
@@ -103,9 +142,9 @@ func (s *service) Login(ctx context.Context, request models.LoginRequest) (model
 }
 
 func (s *service) Refresh(ctx context.Context, request models.RefreshTokenRequest) (models.AuthenticationResponse, error) {
-	tokenUser, err := s.security.VerifyToken(request.Token, true)
+	payload, err := s.security.VerifyPayload(ctx, request.Token, true)
 	if err != nil {
-		if errors.Is(err, jwt.ErrInvalidToken) {
+		if errors.Is(err, jwt.ErrInvalidToken) || errors.Is(err, jwt.ErrRevokedToken) {
 			return models.AuthenticationResponse{}, customValidator.NewValidationError("token", "invalid refresh token")
 		}
 
@@ -118,7 +157,7 @@ func (s *service) Refresh(ctx context.Context, request models.RefreshTokenReques
 		return models.AuthenticationResponse{}, errors.Wrap(err, "could not verify token")
 	}
 
-	user, err := s.userRepository.GetByUsername(ctx, tokenUser.Username)
+	user, err := s.userRepository.GetByUsername(ctx, payload.User.Username)
 
 	if errors.Is(err, models.ErrNotFound) {
 		return models.AuthenticationResponse{}, customValidator.NewValidationError("token", "user not exists")
@@ -128,14 +167,34 @@ func (s *service) Refresh(ctx context.Context, request models.RefreshTokenReques
 		return models.AuthenticationResponse{}, errors.Wrap(err, "could not get user")
 	}
 
-	accessToken, refreshToken, err := s.security.GenerateToken(user)
+	accessToken, refreshToken, err := s.security.RotateToken(ctx, request.Token, user)
 
 	if err != nil {
+		if errors.Is(err, jwt.ErrReusedToken) {
+			s.securityEvent("refresh_token_reuse", zap.String("userID", user.ID), zap.String("username", user.Username), zap.String("sessionID", payload.Family))
+			// refresh is not authenticated request, session is limited to user of token instead of company
+			if err = s.sessionRepository.Revoke(helpers.WithSystemTenant(ctx), models.RevokeSessionRequest{ID: payload.Family, UserID: user.ID}); err != nil && !errors.Is(err, models.ErrNotFound) {
+				s.sentry.HandleError(err)
+				s.log.Error("could not revoke session", zap.Error(err), zap.String("sessionID", payload.Family))
+			}
+			return models.AuthenticationResponse{}, customValidator.NewValidationError("token", "invalid refresh token")
+		}
+
+		if errors.Is(err, jwt.ErrInvalidToken) || errors.Is(err, jwt.ErrRevokedToken) {
+			return models.AuthenticationResponse{}, customValidator.NewValidationError("token", "invalid refresh token")
+		}
+
 		s.sentry.HandleError(err)
 		s.log.Error("could not generate token", zap.Error(err), zap.Any("request", request))
 		return models.AuthenticationResponse{}, errors.Wrap(err, "could not generate token")
 	}
 
+	if err = s.saveSession(ctx, user.ID, accessToken, refreshToken, request.UserAgent, request.IP); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not save session", zap.Error(err), zap.String("userID", user.ID))
+		return models.AuthenticationResponse{}, errors.Wrap(err, "could not save session")
+	}
+
 	permissions, err := s.permissionRepository.GetByUser(ctx, user.ID)
 	if err != nil {
 		s.sentry.HandleError(err)
@@ -148,3 +207,32 @@ func (s *service) Refresh(ctx context.Context, request models.RefreshTokenReques
 		Permissions:  permissions,
 	}, nil
 }
+
+// saveSession links session to token family and keeps ids of its latest tokens
+func (s *service) saveSession(ctx context.Context, userID, accessToken, refreshToken, userAgent, ip string) error {
+	accessPayload, err := s.security.VerifyPayload(ctx, accessToken, false)
+	if err != nil {
+		return err
+	}
+
+	refreshPayload, err := s.security.VerifyPayload(ctx, refreshToken, true)
+	if err != nil {
+		return err
+	}
+
+	return s.sessionRepository.Save(ctx, models.SaveSessionRequest{
+		ID:             refreshPayload.Family,
+		UserID:         userID,
+		UserAgent:      userAgent,
+		IP:             ip,
+		AccessTokenID:  accessPayload.Id,
+		RefreshTokenID: refreshPayload.Id,
+		ExpiresAt:      refreshPayload.ExpiresAt,
+	})
+}
+
+// securityEvent logs suspicious authentication activity with event name,
+// so it can be filtered from regular logs
+func (s *service) securityEvent(event string, fields ...zap.Field) {
+	s.log.Warn("security event", append(fields, zap.String("event", event))...)
+}
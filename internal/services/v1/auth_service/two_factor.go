@@ -0,0 +1,364 @@
+package auth_service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/pkg/helpers"
+	"github.com/abdivasiyev/project_template/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	twoFactorChallengeDuration    = 5 * time.Minute
+	twoFactorChallengeMaxAttempts = 5
+	// usedTotpCodeDuration covers all time steps accepted by security.ValidateTotp
+	usedTotpCodeDuration = 90 * time.Second
+	recoveryCodesCount   = 10
+	recoveryCodeLength   = 10
+	totpCodeLength       = 6
+)
+
+func (s *service) SetupTwoFactor(ctx context.Context, userID string) (models.SetupTwoFactorResponse, error) {
+	user, err := s.userRepository.Get(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get user", zap.Error(err), zap.String("userID", userID))
+		}
+		return models.SetupTwoFactorResponse{}, err
+	}
+
+	secret, uri, err := s.security.GenerateTotpSecret(user.Username)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not generate totp secret", zap.Error(err), zap.String("userID", userID))
+		return models.SetupTwoFactorResponse{}, errors.Wrap(err, "could not generate totp secret")
+	}
+
+	encryptedSecret, err := s.security.EncryptTotpSecret(secret)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not encrypt totp secret", zap.Error(err), zap.String("userID", userID))
+		return models.SetupTwoFactorResponse{}, errors.Wrap(err, "could not encrypt totp secret")
+	}
+
+	if err = s.twoFactorRepository.SaveSecret(ctx, userID, encryptedSecret); err != nil {
+		if !errors.Is(err, models.ErrConflict) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not save totp secret", zap.Error(err), zap.String("userID", userID))
+		}
+		return models.SetupTwoFactorResponse{}, err
+	}
+
+	return models.SetupTwoFactorResponse{
+		Secret: secret,
+		URI:    uri,
+	}, nil
+}
+
+// ConfirmTwoFactor enables two-factor authentication after first valid code,
+// recovery codes are returned only once
+func (s *service) ConfirmTwoFactor(ctx context.Context, req models.TwoFactorCodeRequest) (models.RecoveryCodesResponse, error) {
+	twoFactor, err := s.twoFactorRepository.Get(ctx, req.UserID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.RecoveryCodesResponse{}, validator.NewValidationError("code", "two factor setup is not started")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not get two factor", zap.Error(err), zap.String("userID", req.UserID))
+		return models.RecoveryCodesResponse{}, err
+	}
+
+	if twoFactor.Enabled {
+		return models.RecoveryCodesResponse{}, models.ErrConflict
+	}
+
+	valid, err := s.security.ValidateTotp(twoFactor.Secret, req.Code)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not validate totp code", zap.Error(err), zap.String("userID", req.UserID))
+		return models.RecoveryCodesResponse{}, errors.Wrap(err, "could not validate totp code")
+	}
+
+	if !valid {
+		return models.RecoveryCodesResponse{}, validator.NewValidationError("code", "invalid code")
+	}
+
+	codes, hashes, err := s.generateRecoveryCodes()
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not generate recovery codes", zap.Error(err), zap.String("userID", req.UserID))
+		return models.RecoveryCodesResponse{}, errors.Wrap(err, "could not generate recovery codes")
+	}
+
+	if err = s.twoFactorRepository.Enable(ctx, req.UserID, hashes); err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not enable two factor", zap.Error(err), zap.String("userID", req.UserID))
+		}
+		return models.RecoveryCodesResponse{}, err
+	}
+
+	return models.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableTwoFactor requires valid totp or recovery code
+func (s *service) DisableTwoFactor(ctx context.Context, req models.TwoFactorCodeRequest) (models.SuccessResponse, error) {
+	twoFactor, err := s.twoFactorRepository.Get(ctx, req.UserID)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get two factor", zap.Error(err), zap.String("userID", req.UserID))
+		}
+		return models.SuccessResponse{}, err
+	}
+
+	if !twoFactor.Enabled {
+		return models.SuccessResponse{}, models.ErrNotFound
+	}
+
+	valid, err := s.checkTwoFactorCode(ctx, twoFactor, req.Code)
+	if err != nil {
+		return models.SuccessResponse{}, err
+	}
+
+	if !valid {
+		s.securityEvent("two_factor_failure", zap.String("userID", req.UserID))
+		return models.SuccessResponse{}, validator.NewValidationError("code", "invalid code")
+	}
+
+	if err = s.twoFactorRepository.Disable(ctx, req.UserID); err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not disable two factor", zap.Error(err), zap.String("userID", req.UserID))
+		}
+		return models.SuccessResponse{}, err
+	}
+
+	return models.SuccessResponse{Ok: true}, nil
+}
+
+// VerifyTwoFactor completes login started with password when two-factor authentication is enabled
+func (s *service) VerifyTwoFactor(ctx context.Context, req models.VerifyTwoFactorRequest) (models.AuthenticationResponse, error) {
+	var (
+		challenge models.TwoFactorChallenge
+		key       = twoFactorChallengeKey(req.ChallengeID)
+	)
+
+	if err := s.cache.GetObj(ctx, key, &challenge); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.AuthenticationResponse{}, validator.NewValidationError("challenge_id", "challenge is expired")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not get two factor challenge", zap.Error(err))
+		return models.AuthenticationResponse{}, err
+	}
+
+	if err := s.checkLoginLocked(ctx, challenge.Username, req.IP); err != nil {
+		return models.AuthenticationResponse{}, err
+	}
+
+	duration := time.Until(time.Unix(challenge.ExpiresAt, 0))
+	if duration <= 0 {
+		s.dropTwoFactorChallenge(ctx, req.ChallengeID)
+		return models.AuthenticationResponse{}, validator.NewValidationError("challenge_id", "challenge is expired")
+	}
+
+	// every submitted code is counted before it is checked, so parallel guesses can not exceed the limit
+	attempts, err := s.cache.Incr(ctx, twoFactorAttemptsKey(req.ChallengeID), duration)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not count two factor attempts", zap.Error(err))
+		return models.AuthenticationResponse{}, errors.Wrap(err, "could not count two factor attempts")
+	}
+
+	if attempts > twoFactorChallengeMaxAttempts {
+		s.dropTwoFactorChallenge(ctx, req.ChallengeID)
+		return models.AuthenticationResponse{}, validator.NewValidationError("challenge_id", "challenge is expired")
+	}
+
+	twoFactor, err := s.twoFactorRepository.Get(ctx, challenge.UserID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.AuthenticationResponse{}, validator.NewValidationError("challenge_id", "two factor is not enabled")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not get two factor", zap.Error(err), zap.String("userID", challenge.UserID))
+		return models.AuthenticationResponse{}, err
+	}
+
+	valid, err := s.checkTwoFactorCode(ctx, twoFactor, req.Code)
+	if err != nil {
+		return models.AuthenticationResponse{}, err
+	}
+
+	if !valid {
+		s.recordLoginFailure(ctx, challenge.Username, req.IP, "invalid_two_factor_code")
+		if attempts >= twoFactorChallengeMaxAttempts {
+			s.dropTwoFactorChallenge(ctx, req.ChallengeID)
+		}
+		return models.AuthenticationResponse{}, validator.NewValidationError("code", "invalid code")
+	}
+
+	// challenge is claimed atomically, so only one request completes login with it
+	if _, err = s.cache.GetDel(ctx, key); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.AuthenticationResponse{}, validator.NewValidationError("challenge_id", "challenge is expired")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not claim two factor challenge", zap.Error(err))
+		return models.AuthenticationResponse{}, errors.Wrap(err, "could not claim two factor challenge")
+	}
+
+	if err = s.cache.Delete(ctx, twoFactorAttemptsKey(req.ChallengeID)); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not delete two factor attempts", zap.Error(err))
+	}
+
+	s.resetLoginFailures(ctx, challenge.Username)
+
+	user, err := s.userRepository.GetByUsername(ctx, challenge.Username)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.AuthenticationResponse{}, validator.NewValidationError("challenge_id", "user not exists")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not get user", zap.Error(err), zap.String("userID", challenge.UserID))
+		return models.AuthenticationResponse{}, errors.Wrap(err, "could not get user")
+	}
+
+	return s.authenticate(ctx, user, req.UserAgent, req.IP)
+}
+
+// createTwoFactorChallenge replaces tokens in login response with challenge for second step
+func (s *service) createTwoFactorChallenge(ctx context.Context, user models.GetUserResponse) (models.AuthenticationResponse, error) {
+	challengeID := uuid.New().String()
+
+	if err := s.cache.SetObj(ctx, twoFactorChallengeKey(challengeID), models.TwoFactorChallenge{
+		UserID:    user.ID,
+		Username:  user.Username,
+		ExpiresAt: time.Now().Add(twoFactorChallengeDuration).Unix(),
+	}, twoFactorChallengeDuration); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not save two factor challenge", zap.Error(err), zap.String("userID", user.ID))
+		return models.AuthenticationResponse{}, errors.Wrap(err, "could not save two factor challenge")
+	}
+
+	return models.AuthenticationResponse{
+		TwoFactorRequired: true,
+		ChallengeID:       challengeID,
+	}, nil
+}
+
+// dropTwoFactorChallenge deletes challenge after too many attempts
+func (s *service) dropTwoFactorChallenge(ctx context.Context, challengeID string) {
+	if err := s.cache.Delete(ctx, twoFactorChallengeKey(challengeID), twoFactorAttemptsKey(challengeID)); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not delete two factor challenge", zap.Error(err))
+	}
+}
+
+// checkTwoFactorCode accepts totp code once within its validity window, or any unused recovery code
+func (s *service) checkTwoFactorCode(ctx context.Context, twoFactor models.GetTwoFactorResponse, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+
+	if len(code) == totpCodeLength {
+		valid, err := s.security.ValidateTotp(twoFactor.Secret, code)
+		if err != nil {
+			s.sentry.HandleError(err)
+			s.log.Error("could not validate totp code", zap.Error(err), zap.String("userID", twoFactor.UserID))
+			return false, errors.Wrap(err, "could not validate totp code")
+		}
+
+		if !valid {
+			return false, nil
+		}
+
+		// code is claimed atomically, only the first request gets counter of one
+		key := fmt.Sprintf("auth:2fa:used:%s:%s", twoFactor.UserID, code)
+		used, err := s.cache.Incr(ctx, key, usedTotpCodeDuration)
+		if err != nil {
+			s.sentry.HandleError(err)
+			s.log.Error("could not save used totp code", zap.Error(err))
+			return false, errors.Wrap(err, "could not save used totp code")
+		}
+
+		return used == 1, nil
+	}
+
+	recoveryCodes, err := s.twoFactorRepository.GetRecoveryCodes(ctx, twoFactor.UserID)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not get recovery codes", zap.Error(err), zap.String("userID", twoFactor.UserID))
+		return false, err
+	}
+
+	code = normalizeRecoveryCode(code)
+
+	for _, recoveryCode := range recoveryCodes {
+		valid, err := s.security.CompareHash(code, recoveryCode.CodeHash)
+		if err != nil {
+			s.sentry.HandleError(err)
+			s.log.Error("could not compare recovery code", zap.Error(err), zap.String("userID", twoFactor.UserID))
+			return false, errors.Wrap(err, "could not compare recovery code")
+		}
+
+		if !valid {
+			continue
+		}
+
+		if err = s.twoFactorRepository.UseRecoveryCode(ctx, recoveryCode.ID); err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				return false, nil
+			}
+			s.sentry.HandleError(err)
+			s.log.Error("could not use recovery code", zap.Error(err), zap.String("userID", twoFactor.UserID))
+			return false, err
+		}
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// generateRecoveryCodes returns codes formatted for user and their hashes
+func (s *service) generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodesCount)
+	hashes := make([]string, 0, recoveryCodesCount)
+
+	for i := 0; i < recoveryCodesCount; i++ {
+		code, err := helpers.RandomString(recoveryCodeLength)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		hash, err := s.security.GenerateHash(code)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		codes = append(codes, code[:recoveryCodeLength/2]+"-"+code[recoveryCodeLength/2:])
+		hashes = append(hashes, hash)
+	}
+
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(code, "-", ""))
+}
+
+func twoFactorChallengeKey(challengeID string) string {
+	return fmt.Sprintf("auth:2fa:challenge:%s", challengeID)
+}
+
+func twoFactorAttemptsKey(challengeID string) string {
+	return fmt.Sprintf("auth:2fa:attempts:%s", challengeID)
+}
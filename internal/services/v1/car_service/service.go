@@ -0,0 +1,158 @@
+package car_service
+
+import (
+	"context"
+
+	"github.com/abdivasiyev/project_template/config"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	v1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/helpers"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/sentry"
+	"github.com/abdivasiyev/project_template/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var Module = fx.Provide(NewService)
+
+type service struct {
+	environment   string
+	log           logger.Logger
+	sentry        sentry.Handler
+	carRepository repository.Car
+}
+
+type Params struct {
+	fx.In
+	Config        config.Config
+	Log           logger.Logger
+	Sentry        sentry.Handler
+	CarRepository repository.Car
+}
+
+func NewService(params Params) v1.CarServiceV1 {
+	return &service{
+		environment:   params.Config.GetString(config.EnvironmentKey),
+		log:           params.Log,
+		sentry:        params.Sentry,
+		carRepository: params.CarRepository,
+	}
+}
+
+func (s *service) Create(ctx context.Context, req models.CreateCarRequest) (models.GetCarResponse, error) {
+	req.ID = uuid.New().String()
+
+	if err := s.carRepository.Create(ctx, req); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not create car", zap.Error(err), zap.Any("req", req))
+		return models.GetCarResponse{}, errors.Wrap(err, "could not create car")
+	}
+
+	return s.Get(ctx, req.ID)
+}
+
+func (s *service) Update(ctx context.Context, req models.CreateCarRequest) (models.GetCarResponse, error) {
+	if err := s.carRepository.Update(ctx, req); err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not update car", zap.Error(err), zap.Any("req", req))
+		}
+		return models.GetCarResponse{}, errors.Wrap(err, "could not update car")
+	}
+
+	return s.Get(ctx, req.ID)
+}
+
+func (s *service) Delete(ctx context.Context, id string) error {
+	err := s.carRepository.Delete(ctx, id)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not delete car", zap.Error(err), zap.String("carID", id))
+		}
+	}
+	return err
+}
+
+func (s *service) Get(ctx context.Context, id string) (models.GetCarResponse, error) {
+	response, err := s.carRepository.Get(ctx, id)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get car", zap.Error(err), zap.String("carID", id))
+		}
+	}
+	return response, err
+}
+
+func (s *service) GetAll(ctx context.Context, req models.GetAllCarsRequest) (models.GetAllCarsResponse, error) {
+	response, err := s.carRepository.GetAll(ctx, req)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get all cars", zap.Error(err), zap.Any("req", req))
+		}
+	}
+	return response, err
+}
+
+// CreatePickupDropOff records car key handover, pickup of booked car
+// and drop-off of available car are rejected with models.ErrConflict
+func (s *service) CreatePickupDropOff(ctx context.Context, req models.CarPickupDropOffRequest) (models.GetCarPickupDropOffResponse, error) {
+	if _, err := s.Get(ctx, req.CarID); err != nil {
+		return models.GetCarPickupDropOffResponse{}, err
+	}
+
+	pickupDate, err := helpers.ParseTime(req.PickupDate, config.DateTimeFormat, config.DateFormat)
+	if err != nil {
+		return models.GetCarPickupDropOffResponse{}, validator.NewValidationError("pickup_date", "invalid date format")
+	}
+
+	dropOffDate, err := helpers.ParseTime(req.DropOffDate, config.DateTimeFormat, config.DateFormat)
+	if err != nil {
+		return models.GetCarPickupDropOffResponse{}, validator.NewValidationError("drop_off_date", "invalid date format")
+	}
+
+	if dropOffDate.Before(pickupDate) {
+		return models.GetCarPickupDropOffResponse{}, validator.NewValidationError("drop_off_date", "drop-off date must be after pickup date")
+	}
+
+	req.ID = uuid.New().String()
+	req.PickupDate = pickupDate.Format(config.DateTimeFormat)
+	req.DropOffDate = dropOffDate.Format(config.DateTimeFormat)
+
+	if err = s.carRepository.CreatePickupDropOff(ctx, req); err != nil {
+		if !errors.Is(err, models.ErrConflict) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not create car pickup/drop-off", zap.Error(err), zap.Any("req", req))
+		}
+		return models.GetCarPickupDropOffResponse{}, errors.Wrap(err, "could not create car pickup/drop-off")
+	}
+
+	response, err := s.carRepository.GetPickupDropOff(ctx, req.ID)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not get car pickup/drop-off", zap.Error(err), zap.String("pickupDropOffID", req.ID))
+	}
+
+	return response, err
+}
+
+func (s *service) GetPickupDropOffHistory(ctx context.Context, req models.GetCarPickupDropOffHistoryRequest) (models.GetCarPickupDropOffHistoryResponse, error) {
+	if _, err := s.Get(ctx, req.CarID); err != nil {
+		return models.GetCarPickupDropOffHistoryResponse{}, err
+	}
+
+	response, err := s.carRepository.GetPickupDropOffHistory(ctx, req)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get car pickup/drop-off history", zap.Error(err), zap.Any("req", req))
+		}
+	}
+	return response, err
+}
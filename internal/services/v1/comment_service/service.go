@@ -0,0 +1,186 @@
+package comment_service
+
+import (
+	"context"
+
+	"github.com/abdivasiyev/project_template/config"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	v1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/sentry"
+	"github.com/abdivasiyev/project_template/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var Module = fx.Provide(NewService)
+
+const entityTypeDriverStep = "driver_step"
+
+type service struct {
+	environment       string
+	log               logger.Logger
+	sentry            sentry.Handler
+	commentRepository repository.Comment
+	stepService       v1.StepServiceV1
+}
+
+type Params struct {
+	fx.In
+	Config            config.Config
+	Log               logger.Logger
+	Sentry            sentry.Handler
+	CommentRepository repository.Comment
+	StepService       v1.StepServiceV1
+}
+
+func NewService(params Params) v1.CommentServiceV1 {
+	return &service{
+		environment:       params.Config.GetString(config.EnvironmentKey),
+		log:               params.Log,
+		sentry:            params.Sentry,
+		commentRepository: params.CommentRepository,
+		stepService:       params.StepService,
+	}
+}
+
+func (s *service) Create(ctx context.Context, req models.CreateCommentRequest) (models.GetCommentResponse, error) {
+	if err := s.checkEntity(ctx, req.EntityType, req.EntityID, req.DriverID, req.CreatedBy); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.GetCommentResponse{}, validator.NewValidationError("entity_id", "entity not found")
+		}
+		return models.GetCommentResponse{}, err
+	}
+
+	req.ID = uuid.New().String()
+	req.MentionIDs = unique(req.MentionIDs)
+
+	if err := s.commentRepository.Create(ctx, req); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.GetCommentResponse{}, validator.NewValidationError("mention_ids", "user not found")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not create comment", zap.Error(err), zap.Any("req", req))
+		return models.GetCommentResponse{}, errors.Wrap(err, "could not create comment")
+	}
+
+	return s.Get(ctx, req.ID, req.CreatedBy)
+}
+
+// Update changes comment text and mentions, only author can update comment
+func (s *service) Update(ctx context.Context, req models.UpdateCommentRequest) (models.GetCommentResponse, error) {
+	comment, err := s.Get(ctx, req.ID, req.UpdatedBy)
+	if err != nil {
+		return models.GetCommentResponse{}, err
+	}
+
+	if comment.AuthorID != req.UpdatedBy {
+		return models.GetCommentResponse{}, models.ErrForbidden
+	}
+
+	req.MentionIDs = unique(req.MentionIDs)
+
+	if err = s.commentRepository.Update(ctx, req); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.GetCommentResponse{}, validator.NewValidationError("mention_ids", "user not found")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not update comment", zap.Error(err), zap.Any("req", req))
+		return models.GetCommentResponse{}, errors.Wrap(err, "could not update comment")
+	}
+
+	return s.Get(ctx, req.ID, req.UpdatedBy)
+}
+
+// Delete removes comment, only author can delete comment
+func (s *service) Delete(ctx context.Context, id, userID string) error {
+	comment, err := s.Get(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if comment.AuthorID != userID {
+		return models.ErrForbidden
+	}
+
+	err = s.commentRepository.Delete(ctx, id)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not delete comment", zap.Error(err), zap.String("commentID", id))
+		}
+	}
+	return err
+}
+
+func (s *service) Get(ctx context.Context, id, userID string) (models.GetCommentResponse, error) {
+	response, err := s.commentRepository.Get(ctx, id)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get comment", zap.Error(err), zap.String("commentID", id))
+		}
+		return models.GetCommentResponse{}, err
+	}
+
+	if err = s.checkEntity(ctx, response.EntityType, response.EntityID, response.DriverID, userID); err != nil {
+		return models.GetCommentResponse{}, err
+	}
+
+	return response, nil
+}
+
+func (s *service) GetAll(ctx context.Context, req models.GetAllCommentsRequest) (models.GetAllCommentsResponse, error) {
+	if err := s.checkEntity(ctx, req.EntityType, req.EntityID, req.DriverID, req.UserID); err != nil {
+		return models.GetAllCommentsResponse{}, err
+	}
+
+	response, err := s.commentRepository.GetAll(ctx, req)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get all comments", zap.Error(err), zap.Any("req", req))
+		}
+	}
+	return response, err
+}
+
+// checkEntity checks that commented entity is visible to user,
+// comments of driver step are visible only with view access to step department
+func (s *service) checkEntity(ctx context.Context, entityType, entityID, driverID, userID string) error {
+	err := s.commentRepository.CheckEntity(ctx, entityType, entityID, driverID)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not check commented entity", zap.Error(err), zap.String("entityType", entityType), zap.String("entityID", entityID))
+		}
+		return err
+	}
+
+	if entityType == entityTypeDriverStep {
+		if _, err = s.stepService.Get(ctx, entityID, userID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func unique(ids []string) []string {
+	var (
+		result = make([]string, 0, len(ids))
+		seen   = make(map[string]bool, len(ids))
+	)
+
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+
+	return result
+}
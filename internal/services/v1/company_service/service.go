@@ -0,0 +1,99 @@
+package company_service
+
+import (
+	"context"
+
+	"github.com/abdivasiyev/project_template/config"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	v1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/sentry"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var Module = fx.Provide(NewService)
+
+type service struct {
+	environment       string
+	log               logger.Logger
+	sentry            sentry.Handler
+	companyRepository repository.Company
+}
+
+type Params struct {
+	fx.In
+	Config            config.Config
+	Log               logger.Logger
+	Sentry            sentry.Handler
+	CompanyRepository repository.Company
+}
+
+func NewService(params Params) v1.CompanyServiceV1 {
+	return &service{
+		environment:       params.Config.GetString(config.EnvironmentKey),
+		log:               params.Log,
+		sentry:            params.Sentry,
+		companyRepository: params.CompanyRepository,
+	}
+}
+
+func (s *service) Create(ctx context.Context, req models.CreateCompanyRequest) (models.GetCompanyResponse, error) {
+	req.ID = uuid.New().String()
+
+	if err := s.companyRepository.Create(ctx, req); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not create company", zap.Error(err), zap.Any("req", req))
+		return models.GetCompanyResponse{}, errors.Wrap(err, "could not create company")
+	}
+
+	return s.Get(ctx, req.ID)
+}
+
+func (s *service) Update(ctx context.Context, req models.UpdateCompanyRequest) (models.GetCompanyResponse, error) {
+	if err := s.companyRepository.Update(ctx, req); err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not update company", zap.Error(err), zap.Any("req", req))
+		}
+		return models.GetCompanyResponse{}, errors.Wrap(err, "could not update company")
+	}
+
+	return s.Get(ctx, req.ID)
+}
+
+func (s *service) Delete(ctx context.Context, id string) error {
+	err := s.companyRepository.Delete(ctx, id)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not delete company", zap.Error(err), zap.String("companyID", id))
+		}
+	}
+	return err
+}
+
+func (s *service) Get(ctx context.Context, id string) (models.GetCompanyResponse, error) {
+	response, err := s.companyRepository.Get(ctx, id)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get company", zap.Error(err), zap.String("companyID", id))
+		}
+	}
+	return response, err
+}
+
+func (s *service) GetAll(ctx context.Context, req models.GetAllCompaniesRequest) (models.GetAllCompaniesResponse, error) {
+	response, err := s.companyRepository.GetAll(ctx, req)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get all companies", zap.Error(err), zap.Any("req", req))
+		}
+	}
+	return response, err
+}
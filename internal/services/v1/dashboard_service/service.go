@@ -0,0 +1,84 @@
+package dashboard_service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/abdivasiyev/project_template/config"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	v1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/helpers"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/sentry"
+	"github.com/abdivasiyev/project_template/pkg/storage"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// growthPeriod is length of period which is compared with previous one for growth
+const growthPeriod = 30 * 24 * time.Hour
+
+var Module = fx.Provide(NewService)
+
+type service struct {
+	environment         string
+	log                 logger.Logger
+	sentry              sentry.Handler
+	cache               storage.Cacher
+	dashboardRepository repository.Dashboard
+}
+
+type Params struct {
+	fx.In
+	Config              config.Config
+	Log                 logger.Logger
+	Sentry              sentry.Handler
+	Cache               storage.Cacher
+	DashboardRepository repository.Dashboard
+}
+
+func NewService(params Params) v1.DashboardServiceV1 {
+	return &service{
+		environment:         params.Config.GetString(config.EnvironmentKey),
+		log:                 params.Log,
+		sentry:              params.Sentry,
+		cache:               params.Cache,
+		dashboardRepository: params.DashboardRepository,
+	}
+}
+
+// Get returns statistics of tenant, statistics are cached per tenant
+func (s *service) Get(ctx context.Context) (models.GetDashboardResponse, error) {
+	var (
+		response models.GetDashboardResponse
+		err      error
+		tenant   = helpers.GetTenant(ctx)
+		key      = fmt.Sprintf("dashboard:%s", tenant.CompanyID)
+	)
+
+	if tenant.All {
+		key = "dashboard:all"
+	}
+
+	if err = s.cache.GetObj(ctx, key, &response); err == nil {
+		return response, nil
+	}
+
+	periodStart := time.Now().Add(-growthPeriod)
+
+	response, err = s.dashboardRepository.Get(ctx, periodStart, periodStart.Add(-growthPeriod))
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not get dashboard", zap.Error(err), zap.Any("tenant", tenant))
+		return models.GetDashboardResponse{}, err
+	}
+
+	if err = s.cache.SetObj(ctx, key, response, config.AverageCacheTime); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not save dashboard to cache", zap.Error(err))
+	}
+
+	return response, nil
+}
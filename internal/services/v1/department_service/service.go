@@ -0,0 +1,130 @@
+package department_service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/abdivasiyev/project_template/config"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	v1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/sentry"
+	"github.com/abdivasiyev/project_template/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var Module = fx.Provide(NewService)
+
+type service struct {
+	environment          string
+	log                  logger.Logger
+	sentry               sentry.Handler
+	departmentRepository repository.Department
+}
+
+type Params struct {
+	fx.In
+	Config               config.Config
+	Log                  logger.Logger
+	Sentry               sentry.Handler
+	DepartmentRepository repository.Department
+}
+
+func NewService(params Params) v1.DepartmentServiceV1 {
+	return &service{
+		environment:          params.Config.GetString(config.EnvironmentKey),
+		log:                  params.Log,
+		sentry:               params.Sentry,
+		departmentRepository: params.DepartmentRepository,
+	}
+}
+
+func (s *service) Create(ctx context.Context, req models.CreateDepartmentRequest) (models.GetDepartmentResponse, error) {
+	req.ID = uuid.New().String()
+
+	if err := s.departmentRepository.Create(ctx, req); err != nil {
+		if errors.Is(err, models.ErrConflict) {
+			return models.GetDepartmentResponse{}, validator.NewValidationError("alias", "department with this alias already exists")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not create department", zap.Error(err), zap.Any("req", req))
+		return models.GetDepartmentResponse{}, errors.Wrap(err, "could not create department")
+	}
+
+	return s.Get(ctx, req.ID)
+}
+
+func (s *service) Update(ctx context.Context, req models.CreateDepartmentRequest) (models.GetDepartmentResponse, error) {
+	if err := s.departmentRepository.Update(ctx, req); err != nil {
+		if errors.Is(err, models.ErrConflict) {
+			return models.GetDepartmentResponse{}, validator.NewValidationError("alias", "department with this alias already exists")
+		}
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not update department", zap.Error(err), zap.Any("req", req))
+		}
+		return models.GetDepartmentResponse{}, errors.Wrap(err, "could not update department")
+	}
+
+	return s.Get(ctx, req.ID)
+}
+
+func (s *service) Delete(ctx context.Context, id string) error {
+	err := s.departmentRepository.Delete(ctx, id)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not delete department", zap.Error(err), zap.String("departmentID", id))
+		}
+	}
+	return err
+}
+
+// Reorder puts given departments first in requested order and renumbers all departments without gaps
+func (s *service) Reorder(ctx context.Context, req models.ReorderDepartmentsRequest) (models.GetAllDepartmentsResponse, error) {
+	seen := make(map[string]bool, len(req.IDs))
+
+	for i, id := range req.IDs {
+		if seen[id] {
+			return models.GetAllDepartmentsResponse{}, validator.NewValidationError(fmt.Sprintf("ids[%d]", i), "department is duplicated")
+		}
+		seen[id] = true
+	}
+
+	if err := s.departmentRepository.Reorder(ctx, req.IDs); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.GetAllDepartmentsResponse{}, validator.NewValidationError("ids", "department not found")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not reorder departments", zap.Error(err), zap.Any("req", req))
+		return models.GetAllDepartmentsResponse{}, errors.Wrap(err, "could not reorder departments")
+	}
+
+	return s.GetAll(ctx, models.GetAllDepartmentsRequest{})
+}
+
+func (s *service) Get(ctx context.Context, id string) (models.GetDepartmentResponse, error) {
+	response, err := s.departmentRepository.Get(ctx, id)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get department", zap.Error(err), zap.String("departmentID", id))
+		}
+	}
+	return response, err
+}
+
+func (s *service) GetAll(ctx context.Context, req models.GetAllDepartmentsRequest) (models.GetAllDepartmentsResponse, error) {
+	response, err := s.departmentRepository.GetAll(ctx, req)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get all departments", zap.Error(err), zap.Any("req", req))
+		}
+	}
+	return response, err
+}
@@ -0,0 +1,116 @@
+package driver_service
+
+import (
+	"context"
+
+	"github.com/abdivasiyev/project_template/config"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	v1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/sentry"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var Module = fx.Provide(NewService)
+
+type service struct {
+	environment      string
+	log              logger.Logger
+	sentry           sentry.Handler
+	driverRepository repository.Driver
+}
+
+type Params struct {
+	fx.In
+	Config           config.Config
+	Log              logger.Logger
+	Sentry           sentry.Handler
+	DriverRepository repository.Driver
+}
+
+func NewService(params Params) v1.DriverServiceV1 {
+	return &service{
+		environment:      params.Config.GetString(config.EnvironmentKey),
+		log:              params.Log,
+		sentry:           params.Sentry,
+		driverRepository: params.DriverRepository,
+	}
+}
+
+func (s *service) Create(ctx context.Context, req models.CreateDriverRequest) (models.GetDriverResponse, error) {
+	req.ID = uuid.New().String()
+
+	if err := s.driverRepository.Create(ctx, req); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not create driver", zap.Error(err), zap.Any("req", req))
+		return models.GetDriverResponse{}, errors.Wrap(err, "could not create driver")
+	}
+
+	return s.Get(ctx, req.ID)
+}
+
+func (s *service) Update(ctx context.Context, req models.CreateDriverRequest) (models.GetDriverResponse, error) {
+	if err := s.driverRepository.Update(ctx, req); err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not update driver", zap.Error(err), zap.Any("req", req))
+		}
+		return models.GetDriverResponse{}, errors.Wrap(err, "could not update driver")
+	}
+
+	return s.Get(ctx, req.ID)
+}
+
+func (s *service) Get(ctx context.Context, id string) (models.GetDriverResponse, error) {
+	response, err := s.driverRepository.Get(ctx, id)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get driver", zap.Error(err), zap.String("driverID", id))
+		}
+		return response, err
+	}
+
+	statistics, err := s.driverRepository.GetDepartmentStatistics(ctx, []string{id})
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not get driver department statistics", zap.Error(err), zap.String("driverID", id))
+		return models.GetDriverResponse{}, err
+	}
+	response.DepartmentStatistics = statistics[id]
+
+	return response, nil
+}
+
+func (s *service) GetAll(ctx context.Context, req models.GetAllDriversRequest) (models.GetAllDriversResponse, error) {
+	response, err := s.driverRepository.GetAll(ctx, req)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get all drivers", zap.Error(err), zap.Any("req", req))
+		}
+		return response, err
+	}
+
+	driverIDs := make([]string, 0, len(response.Drivers))
+	for _, driver := range response.Drivers {
+		driverIDs = append(driverIDs, driver.ID)
+	}
+
+	statistics, err := s.driverRepository.GetDepartmentStatistics(ctx, driverIDs)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not get drivers department statistics", zap.Error(err), zap.Any("req", req))
+		return models.GetAllDriversResponse{}, err
+	}
+
+	for i := range response.Drivers {
+		response.Drivers[i].DepartmentStatistics = statistics[response.Drivers[i].ID]
+	}
+
+	return response, nil
+}
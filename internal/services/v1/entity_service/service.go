@@ -0,0 +1,86 @@
+package entity_service
+
+import (
+	"context"
+
+	"github.com/abdivasiyev/project_template/config"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	v1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/sentry"
+	"github.com/abdivasiyev/project_template/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var Module = fx.Provide(NewService)
+
+type service struct {
+	environment      string
+	log              logger.Logger
+	sentry           sentry.Handler
+	entityRepository repository.Entity
+}
+
+type Params struct {
+	fx.In
+	Config           config.Config
+	Log              logger.Logger
+	Sentry           sentry.Handler
+	EntityRepository repository.Entity
+}
+
+func NewService(params Params) v1.EntityServiceV1 {
+	return &service{
+		environment:      params.Config.GetString(config.EnvironmentKey),
+		log:              params.Log,
+		sentry:           params.Sentry,
+		entityRepository: params.EntityRepository,
+	}
+}
+
+// Create adds new value of lookup entity type which allows adding items
+func (s *service) Create(ctx context.Context, req models.CreateEntityRequest) (models.GetEntityResponse, error) {
+	canAddItem, err := s.entityRepository.CanAddItem(ctx, req.EntityType)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.GetEntityResponse{}, validator.NewValidationError("entity_type", "unknown entity type")
+		}
+		return models.GetEntityResponse{}, err
+	}
+
+	if !canAddItem {
+		return models.GetEntityResponse{}, validator.NewValidationError("entity_type", "items can not be added to entity type")
+	}
+
+	req.ID = uuid.New().String()
+
+	if err = s.entityRepository.Create(ctx, req); err != nil {
+		if errors.Is(err, models.ErrConflict) {
+			return models.GetEntityResponse{}, validator.NewValidationError("name", "item with this name already exists")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not create entity", zap.Error(err), zap.Any("req", req))
+		return models.GetEntityResponse{}, errors.Wrap(err, "could not create entity")
+	}
+
+	return models.GetEntityResponse{
+		ID:   req.ID,
+		Name: req.Name,
+	}, nil
+}
+
+func (s *service) GetAll(ctx context.Context, req models.GetEntitiesRequest) (models.GetEntitiesResponse, error) {
+	response, err := s.entityRepository.GetAll(ctx, req)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return response, validator.NewValidationError("entity_type", "unknown entity type")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not get entities", zap.Error(err), zap.Any("req", req))
+	}
+	return response, err
+}
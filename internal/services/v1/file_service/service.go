@@ -25,33 +25,42 @@ import (
 var Module = fx.Provide(NewService)
 
 type service struct {
-	environment    string
-	uploadPath     string
-	cdnURL         string
-	log            logger.Logger
-	sentry         sentry.Handler
-	fileRepository repository.File
-	cache          storage.Cacher
+	environment       string
+	uploadPath        string
+	cdnURL            string
+	log               logger.Logger
+	sentry            sentry.Handler
+	fileRepository    repository.File
+	driverRepository  repository.Driver
+	truckRepository   repository.Truck
+	trailerRepository repository.Trailer
+	cache             storage.Cacher
 }
 
 type Params struct {
 	fx.In
-	Config         config.Config
-	Log            logger.Logger
-	Sentry         sentry.Handler
-	FileRepository repository.File
-	Cache          storage.Cacher
+	Config            config.Config
+	Log               logger.Logger
+	Sentry            sentry.Handler
+	FileRepository    repository.File
+	DriverRepository  repository.Driver
+	TruckRepository   repository.Truck
+	TrailerRepository repository.Trailer
+	Cache             storage.Cacher
 }
 
 func NewService(params Params) v1.FileServiceV1 {
 	return &service{
-		environment:    params.Config.GetString(config.EnvironmentKey),
-		log:            params.Log,
-		sentry:         params.Sentry,
-		fileRepository: params.FileRepository,
-		uploadPath:     params.Config.GetString(config.UploadPathKey),
-		cdnURL:         params.Config.GetString(config.CdnURLKey),
-		cache:          params.Cache,
+		environment:       params.Config.GetString(config.EnvironmentKey),
+		log:               params.Log,
+		sentry:            params.Sentry,
+		fileRepository:    params.FileRepository,
+		driverRepository:  params.DriverRepository,
+		truckRepository:   params.TruckRepository,
+		trailerRepository: params.TrailerRepository,
+		uploadPath:        params.Config.GetString(config.UploadPathKey),
+		cdnURL:            params.Config.GetString(config.CdnURLKey),
+		cache:             params.Cache,
 	}
 }
 
@@ -0,0 +1,225 @@
+package file_service
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/pkg/helpers"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const zipFileCacheTime = 24 * time.Hour
+
+// GenerateZipFile starts generation of zip archive with all files of entity,
+// returned request id is used to poll status of generation via GetZipFile
+func (s *service) GenerateZipFile(ctx context.Context, req models.GenerateZipFileRequest) (models.GenerateZipFileResponse, error) {
+	if err := s.checkEntity(ctx, req.EntityType, req.EntityID); err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get entity", zap.Error(err), zap.Any("req", req))
+		}
+		return models.GenerateZipFileResponse{}, errors.Wrap(err, "could not get entity")
+	}
+
+	tenant := helpers.GetTenant(ctx)
+
+	internalReq := models.GenerateZipInternalRequest{
+		EntityID:   req.EntityID,
+		EntityType: req.EntityType,
+		RequestID:  uuid.New().String(),
+		CompanyID:  tenant.CompanyID,
+		UserID:     tenant.UserID,
+		Status:     models.DriverFileJobStatusInit,
+	}
+
+	if err := s.setZipStatus(ctx, internalReq); err != nil {
+		return models.GenerateZipFileResponse{}, err
+	}
+
+	// generation outlives request, so only tenant of request is kept
+	go s.generateZipFile(helpers.WithTenant(context.Background(), helpers.GetTenant(ctx)), internalReq)
+
+	return models.GenerateZipFileResponse{
+		RequestID: internalReq.RequestID,
+	}, nil
+}
+
+// GetZipFile returns status of zip generation, file id and url are filled when status is finish.
+// Status is available only to user who requested generation within the same company
+func (s *service) GetZipFile(ctx context.Context, requestID string) (models.GetFileResponse, error) {
+	var status models.ZipFileStatus
+
+	if err := s.cache.GetObj(ctx, zipFileKey(requestID), &status); err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get zip file status", zap.Error(err), zap.String("requestID", requestID))
+		}
+		return models.GetFileResponse{}, errors.Wrap(err, "could not get zip file status")
+	}
+
+	tenant := helpers.GetTenant(ctx)
+	if status.UserID != tenant.UserID || status.CompanyID != tenant.CompanyID {
+		return models.GetFileResponse{}, models.ErrNotFound
+	}
+
+	return status.File, nil
+}
+
+func (s *service) checkEntity(ctx context.Context, entityType, entityID string) error {
+	var err error
+
+	switch entityType {
+	case "driver":
+		_, err = s.driverRepository.Get(ctx, entityID)
+	case "truck":
+		_, err = s.truckRepository.Get(ctx, entityID)
+	case "trailer":
+		_, err = s.trailerRepository.Get(ctx, entityID)
+	default:
+		err = models.ErrNotFound
+	}
+
+	return err
+}
+
+func (s *service) generateZipFile(ctx context.Context, req models.GenerateZipInternalRequest) {
+	req.Status = models.DriverFileJobStatusInProcess
+	if err := s.setZipStatus(ctx, req); err != nil {
+		return
+	}
+
+	file, err := s.writeZipFile(ctx, req)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not generate zip file", zap.Error(err), zap.Any("req", req))
+
+		req.Status, req.JobError = models.DriverFileJobStatusError, err
+		_ = s.setZipStatus(ctx, req)
+		return
+	}
+
+	req.Status, req.GeneratedFileID = models.DriverFileJobStatusFinish, file.FileID
+	if err = s.setZipStatus(ctx, req); err != nil {
+		// job must not stay in process when finished file can not be reported
+		req.Status, req.JobError = models.DriverFileJobStatusError, err
+		_ = s.setZipStatus(ctx, req)
+	}
+}
+
+func (s *service) writeZipFile(ctx context.Context, req models.GenerateZipInternalRequest) (models.GetFileResponse, error) {
+	categories, err := s.fileRepository.GetFilesForZipping(ctx, req.EntityType, req.EntityID)
+	if err != nil {
+		return models.GetFileResponse{}, errors.Wrap(err, "could not get files for zipping")
+	}
+
+	var (
+		fileID   = uuid.New().String()
+		fileName = fmt.Sprintf("%s.zip", uuid.New().String())
+		filePath = fmt.Sprintf("%s/%s", s.uploadPath, fileName)
+		fileURL  = fmt.Sprintf("%s/%s", s.cdnURL, fileName)
+	)
+
+	out, err := os.Create(filePath)
+	if err != nil {
+		return models.GetFileResponse{}, errors.Wrap(err, "could not create zip file")
+	}
+	defer out.Close()
+
+	writer := zip.NewWriter(out)
+
+	for _, category := range categories {
+		for _, name := range category.FileNames {
+			if err = s.addZipEntry(writer, path.Join(category.CategoryName, name), fmt.Sprintf("%s/%s", s.uploadPath, name)); err != nil {
+				_ = writer.Close()
+				_ = os.Remove(filePath)
+				return models.GetFileResponse{}, err
+			}
+		}
+	}
+
+	if err = writer.Close(); err != nil {
+		_ = os.Remove(filePath)
+		return models.GetFileResponse{}, errors.Wrap(err, "could not close zip writer")
+	}
+
+	response := models.GetFileResponse{
+		FileID:   fileID,
+		FileName: fileName,
+		FileURL:  fileURL,
+	}
+
+	if err = s.fileRepository.Create(ctx, response); err != nil {
+		_ = os.Remove(filePath)
+		return models.GetFileResponse{}, errors.Wrap(err, "could not save zip file")
+	}
+
+	return response, nil
+}
+
+// addZipEntry copies uploaded file into archive, files missing on disk are skipped
+func (s *service) addZipEntry(writer *zip.Writer, name, filePath string) error {
+	src, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		s.log.Warn("file for zipping does not exist", zap.String("path", filePath))
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "could not open file")
+	}
+	defer src.Close()
+
+	dst, err := writer.Create(name)
+	if err != nil {
+		return errors.Wrap(err, "could not create zip entry")
+	}
+
+	_, err = io.Copy(dst, src)
+
+	return errors.Wrap(err, "could not copy file to zip")
+}
+
+func (s *service) setZipStatus(ctx context.Context, req models.GenerateZipInternalRequest) error {
+	response := models.GetFileResponse{
+		FileStatus: req.Status.String(),
+	}
+
+	if req.JobError != nil {
+		response.FileError = "could not generate zip file"
+	}
+
+	if req.Status == models.DriverFileJobStatusFinish {
+		file, err := s.fileRepository.Get(ctx, req.GeneratedFileID)
+		if err != nil {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get generated file", zap.Error(err), zap.Any("req", req))
+			return errors.Wrap(err, "could not get generated file")
+		}
+
+		response.FileID, response.FileName, response.FileURL = file.FileID, file.FileName, file.FileURL
+	}
+
+	status := models.ZipFileStatus{
+		File:      response,
+		CompanyID: req.CompanyID,
+		UserID:    req.UserID,
+	}
+
+	err := s.cache.SetObj(ctx, zipFileKey(req.RequestID), status, zipFileCacheTime)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not cache zip file status", zap.Error(err), zap.Any("req", req))
+	}
+
+	return errors.Wrap(err, "could not cache zip file status")
+}
+
+func zipFileKey(requestID string) string {
+	return "file:zip:" + requestID
+}
@@ -0,0 +1,375 @@
+package form_service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/abdivasiyev/project_template/config"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	v1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/helpers"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/sentry"
+	"github.com/abdivasiyev/project_template/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const fieldTypeFile = "file"
+
+var aliasReplacer = regexp.MustCompile(`[^a-z0-9]+`)
+
+var Module = fx.Provide(NewService)
+
+type service struct {
+	environment      string
+	log              logger.Logger
+	sentry           sentry.Handler
+	formRepository   repository.Form
+	driverRepository repository.Driver
+	fileRepository   repository.File
+	stepService      v1.StepServiceV1
+}
+
+type Params struct {
+	fx.In
+	Config           config.Config
+	Log              logger.Logger
+	Sentry           sentry.Handler
+	FormRepository   repository.Form
+	DriverRepository repository.Driver
+	FileRepository   repository.File
+	StepService      v1.StepServiceV1
+}
+
+func NewService(params Params) v1.FormServiceV1 {
+	return &service{
+		environment:      params.Config.GetString(config.EnvironmentKey),
+		log:              params.Log,
+		sentry:           params.Sentry,
+		formRepository:   params.FormRepository,
+		driverRepository: params.DriverRepository,
+		fileRepository:   params.FileRepository,
+		stepService:      params.StepService,
+	}
+}
+
+func (s *service) Create(ctx context.Context, req models.CreateFormRequest) (models.GetFormResponse, error) {
+	req.ID = uuid.New().String()
+
+	if err := s.formRepository.Create(ctx, req); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.GetFormResponse{}, validator.NewValidationError("groups", "form group not found")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not create form", zap.Error(err), zap.Any("req", req))
+		return models.GetFormResponse{}, errors.Wrap(err, "could not create form")
+	}
+
+	return s.createVersion(ctx, req.ID)
+}
+
+func (s *service) Update(ctx context.Context, req models.CreateFormRequest) (models.GetFormResponse, error) {
+	if _, err := s.Get(ctx, models.GetFormRequest{ID: req.ID}); err != nil {
+		return models.GetFormResponse{}, err
+	}
+
+	if err := s.formRepository.Update(ctx, req); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.GetFormResponse{}, validator.NewValidationError("groups", "form group not found")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not update form", zap.Error(err), zap.Any("req", req))
+		return models.GetFormResponse{}, errors.Wrap(err, "could not update form")
+	}
+
+	return s.createVersion(ctx, req.ID)
+}
+
+// createVersion stores snapshot of current form tree, so previous versions stay available
+func (s *service) createVersion(ctx context.Context, id string) (models.GetFormResponse, error) {
+	form, err := s.Get(ctx, models.GetFormRequest{ID: id})
+	if err != nil {
+		return models.GetFormResponse{}, err
+	}
+
+	if err = s.formRepository.CreateVersion(ctx, form); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not create form version", zap.Error(err), zap.String("formID", id))
+		return models.GetFormResponse{}, err
+	}
+
+	return form, nil
+}
+
+func (s *service) Delete(ctx context.Context, id string) error {
+	err := s.formRepository.Delete(ctx, id)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not delete form", zap.Error(err), zap.String("formID", id))
+		}
+	}
+	return err
+}
+
+// Get returns current form tree or its snapshot when version is requested
+func (s *service) Get(ctx context.Context, req models.GetFormRequest) (models.GetFormResponse, error) {
+	var (
+		response models.GetFormResponse
+		err      error
+	)
+
+	if req.Version > 0 {
+		response, err = s.formRepository.GetVersion(ctx, req.ID, req.Version)
+	} else {
+		response, err = s.formRepository.Get(ctx, req.ID)
+	}
+
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get form", zap.Error(err), zap.Any("req", req))
+		}
+	}
+	return response, err
+}
+
+func (s *service) GetAll(ctx context.Context, req models.GetAllFormRequest) (models.GetAllFormResponse, error) {
+	response, err := s.formRepository.GetAll(ctx, req)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get all forms", zap.Error(err), zap.Any("req", req))
+		}
+	}
+	return response, err
+}
+
+func (s *service) GetVersions(ctx context.Context, id string) (models.GetAllFormVersionsResponse, error) {
+	if _, err := s.Get(ctx, models.GetFormRequest{ID: id}); err != nil {
+		return models.GetAllFormVersionsResponse{}, err
+	}
+
+	response, err := s.formRepository.GetVersions(ctx, id)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not get form versions", zap.Error(err), zap.String("formID", id))
+	}
+	return response, err
+}
+
+func (s *service) CreateGroup(ctx context.Context, req models.CreateFormGroupRequest) (models.GetFormGroupResponse, error) {
+	if err := validateFields("fields", req.Fields); err != nil {
+		return models.GetFormGroupResponse{}, err
+	}
+
+	req.ID = uuid.New().String()
+	req.Alias = toAlias(req.Alias, req.Title)
+
+	if err := s.formRepository.CreateGroup(ctx, req); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not create form group", zap.Error(err), zap.Any("req", req))
+		return models.GetFormGroupResponse{}, errors.Wrap(err, "could not create form group")
+	}
+
+	return s.GetGroup(ctx, req.ID)
+}
+
+// UpdateGroup replaces group fields and creates new version of every form using this group
+func (s *service) UpdateGroup(ctx context.Context, req models.CreateFormGroupRequest) (models.GetFormGroupResponse, error) {
+	if err := validateFields("fields", req.Fields); err != nil {
+		return models.GetFormGroupResponse{}, err
+	}
+
+	req.Alias = toAlias(req.Alias, req.Title)
+
+	formIDs, err := s.formRepository.UpdateGroup(ctx, req)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not update form group", zap.Error(err), zap.Any("req", req))
+		}
+		return models.GetFormGroupResponse{}, errors.Wrap(err, "could not update form group")
+	}
+
+	for _, formID := range formIDs {
+		if _, err = s.createVersion(ctx, formID); err != nil {
+			return models.GetFormGroupResponse{}, err
+		}
+	}
+
+	return s.GetGroup(ctx, req.ID)
+}
+
+func (s *service) GetGroup(ctx context.Context, id string) (models.GetFormGroupResponse, error) {
+	response, err := s.formRepository.GetGroup(ctx, id)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get form group", zap.Error(err), zap.String("groupID", id))
+		}
+	}
+	return response, err
+}
+
+// SetFieldValues validates submitted values against rules of step form fields and saves them
+func (s *service) SetFieldValues(ctx context.Context, req models.SetFormFieldValue) (models.SuccessResponse, error) {
+	if _, err := s.driverRepository.Get(ctx, req.DriverID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.SuccessResponse{}, validator.NewValidationError("driver_id", "driver not found")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not get driver", zap.Error(err), zap.String("driverID", req.DriverID))
+		return models.SuccessResponse{}, err
+	}
+
+	access, err := s.stepService.HasAccess(ctx, models.HasAccessRequest{
+		StepID:   req.StepID,
+		DriverID: req.DriverID,
+		UserID:   req.CreatedBy,
+	})
+	if err != nil {
+		return models.SuccessResponse{}, err
+	}
+
+	if !access.CanEdit {
+		return models.SuccessResponse{}, models.ErrForbidden
+	}
+
+	fields, err := s.formRepository.GetStepFields(ctx, req.StepID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.SuccessResponse{}, validator.NewValidationError("step_id", "step not found")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not get step fields", zap.Error(err), zap.String("stepID", req.StepID))
+		return models.SuccessResponse{}, err
+	}
+
+	if err = s.validateFieldValues(ctx, fields, req.Fields); err != nil {
+		return models.SuccessResponse{}, err
+	}
+
+	if err = s.formRepository.SetFieldValues(ctx, req); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not set field values", zap.Error(err), zap.Any("req", req))
+		return models.SuccessResponse{}, errors.Wrap(err, "could not set field values")
+	}
+
+	return models.SuccessResponse{Ok: true}, nil
+}
+
+// GetFieldValueHistory returns revisions of field value, user must be able to view every step the revisions were made in
+func (s *service) GetFieldValueHistory(ctx context.Context, req models.GetFormFieldValueHistoryRequest) (models.GetFormFieldValueHistoryResponse, error) {
+	response, err := s.formRepository.GetFieldValueHistory(ctx, req)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get field value history", zap.Error(err), zap.Any("req", req))
+		}
+		return response, err
+	}
+
+	checked := make(map[string]bool)
+
+	for _, revision := range response.Revisions {
+		if checked[revision.StepID] {
+			continue
+		}
+
+		access, err := s.stepService.HasAccess(ctx, models.HasAccessRequest{
+			StepID:   revision.StepID,
+			DriverID: req.DriverID,
+			UserID:   req.UserID,
+		})
+		if err != nil {
+			return models.GetFormFieldValueHistoryResponse{}, err
+		}
+
+		if !access.CanView {
+			return models.GetFormFieldValueHistoryResponse{}, models.ErrForbidden
+		}
+
+		checked[revision.StepID] = true
+	}
+
+	return response, nil
+}
+
+// RevertFieldValue restores values of earlier revision as new revision, so revert is also kept in history
+func (s *service) RevertFieldValue(ctx context.Context, req models.RevertFormFieldValueRequest) (models.SuccessResponse, error) {
+	stepID, err := s.formRepository.GetFieldRevisionStepID(ctx, req)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.SuccessResponse{}, validator.NewValidationError("revision_id", "revision not found")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not get field revision", zap.Error(err), zap.Any("req", req))
+		return models.SuccessResponse{}, err
+	}
+
+	access, err := s.stepService.HasAccess(ctx, models.HasAccessRequest{
+		StepID:   stepID,
+		DriverID: req.DriverID,
+		UserID:   req.CreatedBy,
+	})
+	if err != nil {
+		return models.SuccessResponse{}, err
+	}
+
+	if !access.CanEdit {
+		return models.SuccessResponse{}, models.ErrForbidden
+	}
+
+	if err = s.formRepository.RevertFieldValue(ctx, req); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.SuccessResponse{}, validator.NewValidationError("revision_id", "revision not found")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not revert field value", zap.Error(err), zap.Any("req", req))
+		return models.SuccessResponse{}, errors.Wrap(err, "could not revert field value")
+	}
+
+	return models.SuccessResponse{Ok: true}, nil
+}
+
+// validateFields checks validation rules of field tree before saving
+func validateFields(path string, fields []models.CreateFormFieldRequest) error {
+	for i, field := range fields {
+		fieldPath := fmt.Sprintf("%s[%d]", path, i)
+
+		if !helpers.IsEmpty(field.Validation.Regex) {
+			if _, err := regexp.Compile(field.Validation.Regex); err != nil {
+				return validator.NewValidationError(fieldPath+".validation.regex", "invalid regular expression")
+			}
+		}
+
+		if field.Validation.Max > 0 && field.Validation.Min > field.Validation.Max {
+			return validator.NewValidationError(fieldPath+".validation.min", "min can not be greater than max")
+		}
+
+		if len(field.Validation.AllowedFiles) > 0 && field.Type != fieldTypeFile {
+			return validator.NewValidationError(fieldPath+".validation.allowed_files", "allowed files can be set only for file fields")
+		}
+
+		if err := validateFields(fieldPath+".child_fields", field.ChildFields); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func toAlias(alias, title string) string {
+	if !helpers.IsEmpty(alias) {
+		return alias
+	}
+
+	return strings.Trim(aliasReplacer.ReplaceAllString(strings.ToLower(title), "-"), "-")
+}
@@ -0,0 +1,193 @@
+package form_service
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/pkg/validator"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	fieldTypeText     = "text"
+	fieldTypeTextarea = "textarea"
+)
+
+// validateFieldValues checks every submitted value against validation rules of its field,
+// errors of all fields are returned together keyed by field id
+func (s *service) validateFieldValues(ctx context.Context, fields []models.GetFormFieldResponse, values []models.FormFieldWithValue) error {
+	var (
+		validationErrors validator.ValidationErrors
+		fieldsByID       = make(map[string]models.GetFormFieldResponse, len(fields))
+		fileIDs          []string
+	)
+
+	for _, field := range fields {
+		fieldsByID[field.ID] = field
+	}
+
+	for i, value := range values {
+		field, ok := fieldsByID[value.FieldID]
+		if !ok {
+			validationErrors = append(validationErrors, validator.NewValidationError(
+				fmt.Sprintf("fields[%d].field_id", i),
+				"field does not belong to step form",
+			))
+			continue
+		}
+
+		if field.Type == fieldTypeFile {
+			for _, fileID := range value.Values {
+				if _, err := uuid.Parse(fileID); err == nil {
+					fileIDs = append(fileIDs, fileID)
+				}
+			}
+		}
+	}
+
+	fileNames := make(map[string]string, len(fileIDs))
+
+	if len(fileIDs) > 0 {
+		files, err := s.fileRepository.GetByIDs(ctx, fileIDs)
+		if err != nil {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get files", zap.Error(err), zap.Strings("fileIDs", fileIDs))
+			return err
+		}
+
+		for _, file := range files {
+			fileNames[file.FileID] = file.FileName
+		}
+	}
+
+	for _, value := range values {
+		field, ok := fieldsByID[value.FieldID]
+		if !ok {
+			continue
+		}
+
+		if message := validateFieldValue(field, value.Values, fileNames); message != "" {
+			validationErrors = append(validationErrors, validator.NewValidationError(field.ID, message))
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		return validationErrors
+	}
+
+	return nil
+}
+
+// validateFieldValue returns first broken rule of field or empty string when values are valid
+func validateFieldValue(field models.GetFormFieldResponse, values []string, fileNames map[string]string) string {
+	var (
+		rules  = field.Validation
+		filled int
+	)
+
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			filled++
+			continue
+		}
+
+		if !rules.Blank {
+			return "value can not be blank"
+		}
+	}
+
+	if rules.Required && filled == 0 {
+		return "field is required"
+	}
+
+	if !field.Multiple && len(values) > 1 {
+		return "field accepts only one value"
+	}
+
+	switch field.Type {
+	case fieldTypeText, fieldTypeTextarea:
+		for _, value := range values {
+			length := utf8.RuneCountInString(value)
+
+			if rules.Min > 0 && length < rules.Min {
+				return fmt.Sprintf("value must be at least %d characters long", rules.Min)
+			}
+
+			if rules.Max > 0 && length > rules.Max {
+				return fmt.Sprintf("value must be at most %d characters long", rules.Max)
+			}
+		}
+	default:
+		if len(values) > 0 && rules.Min > 0 && len(values) < rules.Min {
+			return fmt.Sprintf("at least %d values must be given", rules.Min)
+		}
+
+		if rules.Max > 0 && len(values) > rules.Max {
+			return fmt.Sprintf("at most %d values can be given", rules.Max)
+		}
+	}
+
+	if field.Type == fieldTypeFile {
+		for _, value := range values {
+			fileName, ok := fileNames[value]
+			if !ok {
+				return "file not found"
+			}
+
+			if len(rules.AllowedFiles) > 0 && !isAllowedFile(fileName, rules.AllowedFiles) {
+				return fmt.Sprintf("file type is not allowed, allowed types: %s", strings.Join(rules.AllowedFiles, ", "))
+			}
+		}
+
+		return ""
+	}
+
+	if rules.Regex != "" {
+		re, err := regexp.Compile("^(?:" + rules.Regex + ")$")
+		if err != nil {
+			return "field has invalid validation rule"
+		}
+
+		for _, value := range values {
+			if value != "" && !re.MatchString(value) {
+				return "value has invalid format"
+			}
+		}
+	}
+
+	return ""
+}
+
+// isAllowedFile compares extension of stored file name with allowed extensions,
+// extensions may be given with or without leading dot and as comma separated list
+func isAllowedFile(fileName string, allowedFiles []string) bool {
+	extension := strings.ToLower(filepath.Ext(fileName))
+	if extension == "" {
+		return false
+	}
+
+	for _, allowed := range allowedFiles {
+		for _, allowedExtension := range strings.Split(allowed, ",") {
+			allowedExtension = strings.ToLower(strings.TrimSpace(allowedExtension))
+			if allowedExtension == "" {
+				continue
+			}
+
+			if !strings.HasPrefix(allowedExtension, ".") {
+				allowedExtension = "." + allowedExtension
+			}
+
+			if allowedExtension == extension {
+				return true
+			}
+		}
+	}
+
+	return false
+}
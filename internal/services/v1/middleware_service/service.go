@@ -11,6 +11,7 @@ import (
 	"github.com/abdivasiyev/project_template/pkg/helpers"
 	"github.com/abdivasiyev/project_template/pkg/logger"
 	"github.com/abdivasiyev/project_template/pkg/security"
+	"github.com/abdivasiyev/project_template/pkg/security/jwt"
 	"github.com/abdivasiyev/project_template/pkg/sentry"
 	"github.com/abdivasiyev/project_template/pkg/storage"
 	"go.uber.org/fx"
@@ -35,6 +36,8 @@ type service struct {
 	security             security.Handler
 	permissionRepository repository.Permission
 	roleRepository       repository.Role
+	companyRepository    repository.Company
+	sessionRepository    repository.Session
 	cache                storage.Cacher
 }
 
@@ -45,6 +48,8 @@ type Params struct {
 	Sentry               sentry.Handler
 	PermissionRepository repository.Permission
 	RoleRepository       repository.Role
+	CompanyRepository    repository.Company
+	SessionRepository    repository.Session
 	Security             security.Handler
 	Cache                storage.Cacher
 }
@@ -57,6 +62,8 @@ func New(params Params) v1.MiddlewareServiceV1 {
 		security:             params.Security,
 		permissionRepository: params.PermissionRepository,
 		roleRepository:       params.RoleRepository,
+		companyRepository:    params.CompanyRepository,
+		sessionRepository:    params.SessionRepository,
 		cache:                params.Cache,
 	}
 }
@@ -241,12 +248,81 @@ func (s *service) CheckAuth(ctx context.Context, token string) (models.GetUserRe
 
 	token = tokens[1]
 
-	user, err := s.security.VerifyToken(token, false)
+	payload, err := s.security.VerifyPayload(ctx, token, false)
 	if err != nil {
+		if errors.Is(err, jwt.ErrRevokedToken) {
+			s.log.Warn("revoked access token used", zap.Error(err))
+			return models.GetUserResponse{}, models.ErrUnauthorized
+		}
 		s.sentry.HandleError(err)
 		s.log.Error("could not verify access token: %v", zap.Error(err))
 		return models.GetUserResponse{}, err
 	}
 
-	return user, nil
+	if !helpers.IsEmpty(payload.Family) {
+		s.touchSession(ctx, payload.Family)
+	}
+
+	return payload.User, nil
+}
+
+// touchSession updates last seen time of session at most once a minute
+func (s *service) touchSession(ctx context.Context, sessionID string) {
+	key := fmt.Sprintf("session:seen:%s", sessionID)
+
+	if _, err := s.cache.Get(ctx, key); err == nil {
+		return
+	}
+
+	if err := s.sessionRepository.Touch(ctx, sessionID); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not update session last seen time", zap.Error(err), zap.String("sessionID", sessionID))
+		return
+	}
+
+	if err := s.cache.Set(ctx, key, time.Now().Unix(), 1*time.Minute); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not save to cache", zap.Error(err))
+	}
+}
+
+// GetTenant limits user to company from token, only admins can switch to requested company
+func (s *service) GetTenant(ctx context.Context, user models.GetUserResponse, companyID string) (models.Tenant, error) {
+	tenant := models.Tenant{
+		UserID:    user.ID,
+		CompanyID: user.Company.ID,
+	}
+
+	isAdmin, err := s.roleRepository.IsAdmin(ctx, user.ID)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not check is admin", zap.Error(err), zap.String("userId", user.ID))
+		return models.Tenant{}, err
+	}
+
+	if !isAdmin {
+		if !helpers.IsEmpty(companyID) && companyID != user.Company.ID {
+			return models.Tenant{}, models.ErrForbidden
+		}
+		return tenant, nil
+	}
+
+	if helpers.IsEmpty(companyID) {
+		tenant.CompanyID = ""
+		tenant.All = true
+		return tenant, nil
+	}
+
+	// tenant is not resolved yet, admins can switch to any company
+	if _, err = s.companyRepository.Get(helpers.WithSystemTenant(ctx), companyID); err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get company", zap.Error(err), zap.String("companyId", companyID))
+		}
+		return models.Tenant{}, err
+	}
+
+	tenant.CompanyID = companyID
+
+	return tenant, nil
 }
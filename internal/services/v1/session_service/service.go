@@ -0,0 +1,76 @@
+package session_service
+
+import (
+	"context"
+
+	"github.com/abdivasiyev/project_template/config"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	v1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/security"
+	"github.com/abdivasiyev/project_template/pkg/sentry"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var Module = fx.Provide(NewService)
+
+type service struct {
+	environment       string
+	log               logger.Logger
+	sentry            sentry.Handler
+	security          security.Handler
+	sessionRepository repository.Session
+}
+
+type Params struct {
+	fx.In
+	Config            config.Config
+	Log               logger.Logger
+	Sentry            sentry.Handler
+	Security          security.Handler
+	SessionRepository repository.Session
+}
+
+func NewService(params Params) v1.SessionServiceV1 {
+	return &service{
+		environment:       params.Config.GetString(config.EnvironmentKey),
+		log:               params.Log,
+		sentry:            params.Sentry,
+		security:          params.Security,
+		sessionRepository: params.SessionRepository,
+	}
+}
+
+// Revoke closes session and revokes all its tokens,
+// session of another user can be revoked only when request user id is empty
+func (s *service) Revoke(ctx context.Context, req models.RevokeSessionRequest) error {
+	if err := s.sessionRepository.Revoke(ctx, req); err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not revoke session", zap.Error(err), zap.Any("req", req))
+		}
+		return err
+	}
+
+	if err := s.security.RevokeFamily(ctx, req.ID); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not revoke session tokens", zap.Error(err), zap.Any("req", req))
+		return errors.Wrap(err, "could not revoke session tokens")
+	}
+
+	return nil
+}
+
+func (s *service) GetAll(ctx context.Context, req models.GetAllSessionsRequest) (models.GetAllSessionsResponse, error) {
+	response, err := s.sessionRepository.GetAll(ctx, req)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not get sessions", zap.Error(err), zap.Any("req", req))
+		return models.GetAllSessionsResponse{}, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,198 @@
+package status_service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/abdivasiyev/project_template/config"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	v1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/sentry"
+	"github.com/abdivasiyev/project_template/pkg/storage"
+	"github.com/abdivasiyev/project_template/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// statusCacheTime is long because catalog is invalidated on every write
+const statusCacheTime = 7 * 24 * time.Hour
+
+var Module = fx.Provide(NewService)
+
+type service struct {
+	environment      string
+	log              logger.Logger
+	sentry           sentry.Handler
+	statusRepository repository.Status
+	cache            storage.Cacher
+}
+
+type Params struct {
+	fx.In
+	Config           config.Config
+	Log              logger.Logger
+	Sentry           sentry.Handler
+	StatusRepository repository.Status
+	Cache            storage.Cacher
+}
+
+func NewService(params Params) v1.StatusServiceV1 {
+	return &service{
+		environment:      params.Config.GetString(config.EnvironmentKey),
+		log:              params.Log,
+		sentry:           params.Sentry,
+		statusRepository: params.StatusRepository,
+		cache:            params.Cache,
+	}
+}
+
+func (s *service) Create(ctx context.Context, req models.CreateStatusRequest) (models.GetStatusResponse, error) {
+	req.ID = uuid.New().String()
+
+	if err := s.statusRepository.Create(ctx, req); err != nil {
+		if errors.Is(err, models.ErrConflict) {
+			return models.GetStatusResponse{}, validator.NewValidationError("alias", "status with this alias already exists")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not create status", zap.Error(err), zap.Any("req", req))
+		return models.GetStatusResponse{}, errors.Wrap(err, "could not create status")
+	}
+
+	s.invalidate(ctx, req.EntityType)
+
+	return s.Get(ctx, req.ID)
+}
+
+func (s *service) Update(ctx context.Context, req models.UpdateStatusRequest) (models.GetStatusResponse, error) {
+	status, entityType, err := s.statusRepository.Get(ctx, req.ID)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get status", zap.Error(err), zap.Any("req", req))
+		}
+		return models.GetStatusResponse{}, errors.Wrap(err, "could not get status")
+	}
+
+	if status.IsSystem && status.Alias != req.Alias {
+		return models.GetStatusResponse{}, validator.NewValidationError("alias", "alias of system status can not be changed")
+	}
+
+	if err = s.statusRepository.Update(ctx, req); err != nil {
+		if errors.Is(err, models.ErrConflict) {
+			return models.GetStatusResponse{}, validator.NewValidationError("alias", "status with this alias already exists")
+		}
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not update status", zap.Error(err), zap.Any("req", req))
+		}
+		return models.GetStatusResponse{}, errors.Wrap(err, "could not update status")
+	}
+
+	s.invalidate(ctx, entityType)
+
+	return s.Get(ctx, req.ID)
+}
+
+func (s *service) Delete(ctx context.Context, id string) error {
+	_, entityType, err := s.statusRepository.Get(ctx, id)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get status", zap.Error(err), zap.String("statusID", id))
+		}
+		return err
+	}
+
+	if err = s.statusRepository.Delete(ctx, id); err != nil {
+		if !errors.Is(err, models.ErrNotFound) && !errors.Is(err, models.ErrConflict) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not delete status", zap.Error(err), zap.String("statusID", id))
+		}
+		return err
+	}
+
+	s.invalidate(ctx, entityType)
+
+	return nil
+}
+
+// Reorder puts given statuses first in requested order and renumbers all statuses of entity type without gaps
+func (s *service) Reorder(ctx context.Context, req models.ReorderStatusesRequest) (models.GetAllStatusesResponse, error) {
+	seen := make(map[string]bool, len(req.IDs))
+
+	for i, id := range req.IDs {
+		if seen[id] {
+			return models.GetAllStatusesResponse{}, validator.NewValidationError(fmt.Sprintf("ids[%d]", i), "status is duplicated")
+		}
+		seen[id] = true
+	}
+
+	if err := s.statusRepository.Reorder(ctx, req.EntityType, req.IDs); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.GetAllStatusesResponse{}, validator.NewValidationError("ids", "status not found")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not reorder statuses", zap.Error(err), zap.Any("req", req))
+		return models.GetAllStatusesResponse{}, errors.Wrap(err, "could not reorder statuses")
+	}
+
+	s.invalidate(ctx, req.EntityType)
+
+	return s.GetAll(ctx, models.GetAllStatusesRequest{EntityType: req.EntityType})
+}
+
+func (s *service) Get(ctx context.Context, id string) (models.GetStatusResponse, error) {
+	response, _, err := s.statusRepository.Get(ctx, id)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get status", zap.Error(err), zap.String("statusID", id))
+		}
+	}
+	return response, err
+}
+
+// GetAll returns statuses of entity type ordered by sequence, catalog is cached per entity type
+func (s *service) GetAll(ctx context.Context, req models.GetAllStatusesRequest) (models.GetAllStatusesResponse, error) {
+	var (
+		response models.GetAllStatusesResponse
+		err      error
+		key      = statusKey(req.EntityType)
+	)
+
+	if err = s.cache.GetObj(ctx, key, &response); err == nil {
+		return response, nil
+	}
+
+	response.Statuses, err = s.statusRepository.GetByEntityType(ctx, req.EntityType)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not get all statuses", zap.Error(err), zap.Any("req", req))
+		return models.GetAllStatusesResponse{}, err
+	}
+
+	response.Count = len(response.Statuses)
+
+	if err = s.cache.SetObj(ctx, key, response, statusCacheTime); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not cache statuses", zap.Error(err), zap.Any("req", req))
+	}
+
+	return response, nil
+}
+
+func (s *service) invalidate(ctx context.Context, entityType string) {
+	if err := s.cache.Delete(ctx, statusKey(entityType)); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not invalidate statuses", zap.Error(err), zap.String("entityType", entityType))
+	}
+}
+
+func statusKey(entityType string) string {
+	return "status:" + entityType
+}
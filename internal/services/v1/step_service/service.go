@@ -0,0 +1,515 @@
+package step_service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/abdivasiyev/project_template/config"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	v1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/helpers"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/sentry"
+	"github.com/abdivasiyev/project_template/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const stepEntityType = "step"
+
+var Module = fx.Provide(NewService)
+
+type service struct {
+	environment      string
+	log              logger.Logger
+	sentry           sentry.Handler
+	stepRepository   repository.Step
+	statusRepository repository.Status
+	driverRepository repository.Driver
+	formRepository   repository.Form
+	roleRepository   repository.Role
+}
+
+type Params struct {
+	fx.In
+	Config           config.Config
+	Log              logger.Logger
+	Sentry           sentry.Handler
+	StepRepository   repository.Step
+	StatusRepository repository.Status
+	DriverRepository repository.Driver
+	FormRepository   repository.Form
+	RoleRepository   repository.Role
+}
+
+func NewService(params Params) v1.StepServiceV1 {
+	return &service{
+		environment:      params.Config.GetString(config.EnvironmentKey),
+		log:              params.Log,
+		sentry:           params.Sentry,
+		stepRepository:   params.StepRepository,
+		statusRepository: params.StatusRepository,
+		driverRepository: params.DriverRepository,
+		formRepository:   params.FormRepository,
+		roleRepository:   params.RoleRepository,
+	}
+}
+
+func (s *service) Create(ctx context.Context, req models.CreateStepRequest) (models.GetStepResponse, error) {
+	access, err := s.departmentAccess(ctx, req.UserID, req.DepartmentID)
+	if err != nil {
+		return models.GetStepResponse{}, err
+	}
+
+	if !access.CanCreate {
+		return models.GetStepResponse{}, models.ErrForbidden
+	}
+
+	if err := s.validateForm(ctx, req.FormID); err != nil {
+		return models.GetStepResponse{}, err
+	}
+
+	req.ID = uuid.New().String()
+
+	if err := s.stepRepository.Create(ctx, req); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.GetStepResponse{}, validator.NewValidationError("department_id", "department not found")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not create step", zap.Error(err), zap.Any("req", req))
+		return models.GetStepResponse{}, errors.Wrap(err, "could not create step")
+	}
+
+	return s.get(ctx, req.ID)
+}
+
+func (s *service) Update(ctx context.Context, req models.CreateStepRequest) (models.GetStepResponse, error) {
+	step, err := s.get(ctx, req.ID)
+	if err != nil {
+		return models.GetStepResponse{}, err
+	}
+
+	// step can be moved to another department only when user can edit both of them
+	for _, departmentID := range []string{step.DepartmentID, req.DepartmentID} {
+		access, err := s.departmentAccess(ctx, req.UserID, departmentID)
+		if err != nil {
+			return models.GetStepResponse{}, err
+		}
+
+		if !access.CanEdit {
+			return models.GetStepResponse{}, models.ErrForbidden
+		}
+	}
+
+	if err := s.validateForm(ctx, req.FormID); err != nil {
+		return models.GetStepResponse{}, err
+	}
+
+	if err := s.stepRepository.Update(ctx, req); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.GetStepResponse{}, validator.NewValidationError("department_id", "department not found")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not update step", zap.Error(err), zap.Any("req", req))
+		return models.GetStepResponse{}, errors.Wrap(err, "could not update step")
+	}
+
+	return s.get(ctx, req.ID)
+}
+
+func (s *service) validateForm(ctx context.Context, formID string) error {
+	if helpers.IsEmpty(formID) {
+		return nil
+	}
+
+	if _, err := s.formRepository.Get(ctx, formID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return validator.NewValidationError("form_id", "form not found")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not get form", zap.Error(err), zap.String("formID", formID))
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) Delete(ctx context.Context, id, userID string) error {
+	step, err := s.get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	access, err := s.departmentAccess(ctx, userID, step.DepartmentID)
+	if err != nil {
+		return err
+	}
+
+	if !access.CanDelete {
+		return models.ErrForbidden
+	}
+
+	err = s.stepRepository.Delete(ctx, id)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not delete step", zap.Error(err), zap.String("stepID", id))
+		}
+	}
+	return err
+}
+
+func (s *service) Get(ctx context.Context, id, userID string) (models.GetStepResponse, error) {
+	response, err := s.get(ctx, id)
+	if err != nil {
+		return response, err
+	}
+
+	access, err := s.departmentAccess(ctx, userID, response.DepartmentID)
+	if err != nil {
+		return models.GetStepResponse{}, err
+	}
+
+	if !access.CanView {
+		return models.GetStepResponse{}, models.ErrForbidden
+	}
+
+	return response, nil
+}
+
+func (s *service) get(ctx context.Context, id string) (models.GetStepResponse, error) {
+	response, err := s.stepRepository.Get(ctx, id)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get step", zap.Error(err), zap.String("stepID", id))
+		}
+	}
+	return response, err
+}
+
+func (s *service) GetAll(ctx context.Context, departmentID, userID string) (models.GetAllStepsResponse, error) {
+	access, err := s.departmentAccess(ctx, userID, departmentID)
+	if err != nil {
+		return models.GetAllStepsResponse{}, err
+	}
+
+	if !access.CanView {
+		return models.GetAllStepsResponse{}, models.ErrForbidden
+	}
+
+	response, err := s.stepRepository.GetAll(ctx, departmentID)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get all steps", zap.Error(err), zap.String("departmentID", departmentID))
+		}
+	}
+	return response, err
+}
+
+// GetAllGroupByStatus returns kanban view of department steps of driver,
+// steps which are not started yet are placed into the first step status
+func (s *service) GetAllGroupByStatus(ctx context.Context, req models.GetAllStepsRequest) (models.GetAllStepsGroupByStatusResponse, error) {
+	var response models.GetAllStepsGroupByStatusResponse
+
+	access, err := s.departmentAccess(ctx, req.UserID, req.DepartmentID)
+	if err != nil {
+		return response, err
+	}
+
+	if !access.CanView {
+		return response, models.ErrForbidden
+	}
+
+	if _, err = s.driverRepository.Get(ctx, req.DriverID); err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get driver", zap.Error(err), zap.String("driverID", req.DriverID))
+		}
+		return response, err
+	}
+
+	statuses, err := s.statusRepository.GetByEntityType(ctx, stepEntityType)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not get step statuses", zap.Error(err))
+		return response, err
+	}
+
+	steps, err := s.stepRepository.GetDriverSteps(ctx, req)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not get driver steps", zap.Error(err), zap.Any("req", req))
+		return response, err
+	}
+
+	indexes := make(map[string]int, len(statuses))
+	for i, status := range statuses {
+		indexes[status.ID] = i
+		response.Steps = append(response.Steps, models.GetStepGroupByStatusResponse{
+			Status: status,
+			Steps:  []models.GetStepResponse{},
+		})
+	}
+
+	if len(response.Steps) == 0 {
+		return response, nil
+	}
+
+	for _, step := range steps {
+		// not started steps and steps with unknown status fall into the first column
+		index := indexes[step.StatusID]
+		response.Steps[index].Steps = append(response.Steps[index].Steps, step)
+	}
+
+	response.Count = len(steps)
+
+	return response, nil
+}
+
+// UpdateStatus moves driver step into given status
+func (s *service) UpdateStatus(ctx context.Context, req models.UpdateStepStatusRequest) (models.SuccessResponse, error) {
+	if _, err := s.driverRepository.Get(ctx, req.DriverID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.SuccessResponse{}, validator.NewValidationError("driver_id", "driver not found")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not get driver", zap.Error(err), zap.String("driverID", req.DriverID))
+		return models.SuccessResponse{}, err
+	}
+
+	step, err := s.get(ctx, req.StepID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.SuccessResponse{}, validator.NewValidationError("step_id", "step not found")
+		}
+		return models.SuccessResponse{}, err
+	}
+
+	access, err := s.departmentAccess(ctx, req.UpdatedBy, step.DepartmentID)
+	if err != nil {
+		return models.SuccessResponse{}, err
+	}
+
+	if !access.CanEdit {
+		return models.SuccessResponse{}, models.ErrForbidden
+	}
+
+	statuses, err := s.statusRepository.GetByEntityType(ctx, stepEntityType)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not get step statuses", zap.Error(err))
+		return models.SuccessResponse{}, err
+	}
+
+	status, ok := findStatus(statuses, req.StatusID)
+	if !ok {
+		return models.SuccessResponse{}, validator.NewValidationError("status_id", "status not found")
+	}
+
+	currentStatusID, err := s.stepRepository.GetDriverStepStatus(ctx, req.DriverID, req.StepID)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not get driver step status", zap.Error(err), zap.Any("req", req))
+		return models.SuccessResponse{}, err
+	}
+
+	if helpers.IsEmpty(currentStatusID) {
+		currentStatusID = statuses[0].ID
+	}
+
+	if currentStatusID == req.StatusID {
+		return models.SuccessResponse{Ok: true}, nil
+	}
+
+	allowed, err := s.statusRepository.IsTransitionAllowed(ctx, currentStatusID, req.StatusID, req.UpdatedBy)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not check status transition", zap.Error(err), zap.Any("req", req))
+		return models.SuccessResponse{}, err
+	}
+
+	if !allowed {
+		currentStatus, _ := findStatus(statuses, currentStatusID)
+		return models.SuccessResponse{}, validator.NewValidationError(
+			"status_id",
+			fmt.Sprintf("step can not be moved from %s to %s", currentStatus.Name, status.Name),
+		)
+	}
+
+	req.CurrentStatusID = currentStatusID
+
+	if err = s.stepRepository.UpdateStatus(ctx, req); err != nil {
+		if errors.Is(err, models.ErrConflict) {
+			return models.SuccessResponse{}, validator.NewValidationError("status_id", "status of step was changed, try again")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not update step status", zap.Error(err), zap.Any("req", req))
+		return models.SuccessResponse{}, errors.Wrap(err, "could not update step status")
+	}
+
+	return models.SuccessResponse{Ok: true}, nil
+}
+
+// HasAccess returns what user can do with given step of driver
+func (s *service) HasAccess(ctx context.Context, req models.HasAccessRequest) (models.HasAccessResponse, error) {
+	if _, err := s.driverRepository.Get(ctx, req.DriverID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.HasAccessResponse{}, validator.NewValidationError("driver_id", "driver not found")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not get driver", zap.Error(err), zap.String("driverID", req.DriverID))
+		return models.HasAccessResponse{}, err
+	}
+
+	step, err := s.get(ctx, req.StepID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.HasAccessResponse{}, validator.NewValidationError("step_id", "step not found")
+		}
+		return models.HasAccessResponse{}, err
+	}
+
+	return s.departmentAccess(ctx, req.UserID, step.DepartmentID)
+}
+
+// departmentAccess merges access flags of user roles for department, admin can do everything
+func (s *service) departmentAccess(ctx context.Context, userID, departmentID string) (models.HasAccessResponse, error) {
+	isAdmin, err := s.roleRepository.IsAdmin(ctx, userID)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not check is admin", zap.Error(err), zap.String("userID", userID))
+		return models.HasAccessResponse{}, err
+	}
+
+	if isAdmin {
+		return models.HasAccessResponse{
+			CanView:   true,
+			CanEdit:   true,
+			CanCreate: true,
+			CanDelete: true,
+		}, nil
+	}
+
+	response, err := s.stepRepository.GetDepartmentAccess(ctx, userID, departmentID)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not get department access", zap.Error(err), zap.String("userID", userID), zap.String("departmentID", departmentID))
+	}
+	return response, err
+}
+
+func (s *service) SetDepartmentAccess(ctx context.Context, req models.SetDepartmentAccessRequest) (models.GetAllDepartmentAccessResponse, error) {
+	if _, err := s.roleRepository.Get(ctx, req.RoleID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.GetAllDepartmentAccessResponse{}, validator.NewValidationError("role_id", "role not found")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not get role", zap.Error(err), zap.String("roleID", req.RoleID))
+		return models.GetAllDepartmentAccessResponse{}, err
+	}
+
+	if err := s.stepRepository.SetDepartmentAccess(ctx, req); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.GetAllDepartmentAccessResponse{}, validator.NewValidationError("department_id", "department not found")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not set department access", zap.Error(err), zap.Any("req", req))
+		return models.GetAllDepartmentAccessResponse{}, errors.Wrap(err, "could not set department access")
+	}
+
+	return s.GetDepartmentAccesses(ctx, req.DepartmentID)
+}
+
+func (s *service) GetDepartmentAccesses(ctx context.Context, departmentID string) (models.GetAllDepartmentAccessResponse, error) {
+	response, err := s.stepRepository.GetDepartmentAccesses(ctx, departmentID)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not get department accesses", zap.Error(err), zap.String("departmentID", departmentID))
+	}
+	return response, err
+}
+
+// CreateTransition allows moving step from one status to another, optionally only for given role
+func (s *service) CreateTransition(ctx context.Context, req models.CreateStatusTransitionRequest) (models.GetStatusTransitionResponse, error) {
+	statuses, err := s.statusRepository.GetByEntityType(ctx, stepEntityType)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not get step statuses", zap.Error(err))
+		return models.GetStatusTransitionResponse{}, err
+	}
+
+	if _, ok := findStatus(statuses, req.FromStatusID); !ok {
+		return models.GetStatusTransitionResponse{}, validator.NewValidationError("from_status_id", "status not found")
+	}
+
+	if _, ok := findStatus(statuses, req.ToStatusID); !ok {
+		return models.GetStatusTransitionResponse{}, validator.NewValidationError("to_status_id", "status not found")
+	}
+
+	if !helpers.IsEmpty(req.RoleID) {
+		if _, err = s.roleRepository.Get(ctx, req.RoleID); err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				return models.GetStatusTransitionResponse{}, validator.NewValidationError("role_id", "role not found")
+			}
+			s.sentry.HandleError(err)
+			s.log.Error("could not get role", zap.Error(err), zap.String("roleID", req.RoleID))
+			return models.GetStatusTransitionResponse{}, err
+		}
+	}
+
+	req.ID = uuid.New().String()
+
+	if err = s.statusRepository.CreateTransition(ctx, req); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not create status transition", zap.Error(err), zap.Any("req", req))
+		return models.GetStatusTransitionResponse{}, errors.Wrap(err, "could not create status transition")
+	}
+
+	transitions, err := s.GetTransitions(ctx)
+	if err != nil {
+		return models.GetStatusTransitionResponse{}, err
+	}
+
+	for _, transition := range transitions.Transitions {
+		if transition.ID == req.ID {
+			return transition, nil
+		}
+	}
+
+	return models.GetStatusTransitionResponse{}, models.ErrNotFound
+}
+
+func (s *service) DeleteTransition(ctx context.Context, id string) error {
+	err := s.statusRepository.DeleteTransition(ctx, id)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not delete status transition", zap.Error(err), zap.String("transitionID", id))
+		}
+	}
+	return err
+}
+
+func (s *service) GetTransitions(ctx context.Context) (models.GetAllStatusTransitionsResponse, error) {
+	response, err := s.statusRepository.GetTransitions(ctx, stepEntityType)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not get status transitions", zap.Error(err))
+	}
+	return response, err
+}
+
+func findStatus(statuses []models.GetStatusResponse, statusID string) (models.GetStatusResponse, bool) {
+	for _, status := range statuses {
+		if status.ID == statusID {
+			return status, true
+		}
+	}
+
+	return models.GetStatusResponse{}, false
+}
@@ -0,0 +1,208 @@
+package trailer_service
+
+import (
+	"context"
+
+	"github.com/abdivasiyev/project_template/config"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	v1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/sentry"
+	"github.com/abdivasiyev/project_template/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var Module = fx.Provide(NewService)
+
+type service struct {
+	environment          string
+	log                  logger.Logger
+	sentry               sentry.Handler
+	trailerRepository    repository.Trailer
+	driverRepository     repository.Driver
+	assignmentRepository repository.Assignment
+}
+
+type Params struct {
+	fx.In
+	Config               config.Config
+	Log                  logger.Logger
+	Sentry               sentry.Handler
+	TrailerRepository    repository.Trailer
+	DriverRepository     repository.Driver
+	AssignmentRepository repository.Assignment
+}
+
+func NewService(params Params) v1.TrailerServiceV1 {
+	return &service{
+		environment:          params.Config.GetString(config.EnvironmentKey),
+		log:                  params.Log,
+		sentry:               params.Sentry,
+		trailerRepository:    params.TrailerRepository,
+		driverRepository:     params.DriverRepository,
+		assignmentRepository: params.AssignmentRepository,
+	}
+}
+
+func (s *service) Create(ctx context.Context, req models.CreateTrailerRequest) (models.GetTrailerResponse, error) {
+	req.ID = uuid.New().String()
+
+	if err := s.validateDriver(ctx, req.DriverID); err != nil {
+		return models.GetTrailerResponse{}, err
+	}
+
+	// trailer is saved together with assignment of driver, so failed assignment does not leave trailer behind
+	if err := s.trailerRepository.Create(ctx, req); err != nil {
+		if !errors.Is(err, models.ErrNotFound) && !errors.Is(err, models.ErrConflict) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not create trailer", zap.Error(err), zap.Any("req", req))
+		}
+		return models.GetTrailerResponse{}, errors.Wrap(err, "could not create trailer")
+	}
+
+	return s.Get(ctx, req.ID)
+}
+
+func (s *service) Update(ctx context.Context, req models.CreateTrailerRequest) (models.GetTrailerResponse, error) {
+	if err := s.validateDriver(ctx, req.DriverID); err != nil {
+		return models.GetTrailerResponse{}, err
+	}
+
+	if err := s.trailerRepository.Update(ctx, req); err != nil {
+		if !errors.Is(err, models.ErrNotFound) && !errors.Is(err, models.ErrConflict) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not update trailer", zap.Error(err), zap.Any("req", req))
+		}
+		return models.GetTrailerResponse{}, errors.Wrap(err, "could not update trailer")
+	}
+
+	return s.Get(ctx, req.ID)
+}
+
+func (s *service) validateDriver(ctx context.Context, driverID string) error {
+	if driverID == "" {
+		return nil
+	}
+
+	if _, err := s.driverRepository.Get(ctx, driverID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return validator.NewValidationError("driver_id", "driver not found")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not get driver", zap.Error(err), zap.String("driverID", driverID))
+		return errors.Wrap(err, "could not get driver")
+	}
+
+	return nil
+}
+
+func (s *service) Delete(ctx context.Context, id string) error {
+	err := s.trailerRepository.Delete(ctx, id)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not delete trailer", zap.Error(err), zap.String("trailerID", id))
+		}
+		return err
+	}
+
+	err = s.assignmentRepository.Unassign(ctx, models.UnassignDriverRequest{
+		EntityType: "trailer",
+		EntityID:   id,
+	})
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		s.sentry.HandleError(err)
+		s.log.Error("could not close assignment of deleted trailer", zap.Error(err), zap.String("trailerID", id))
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) Get(ctx context.Context, id string) (models.GetTrailerResponse, error) {
+	response, err := s.trailerRepository.Get(ctx, id)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get trailer", zap.Error(err), zap.String("trailerID", id))
+		}
+	}
+	return response, err
+}
+
+func (s *service) GetAll(ctx context.Context, req models.GetAllTrailersRequest) (models.GetAllTrailersResponse, error) {
+	response, err := s.trailerRepository.GetAll(ctx, req)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get all trailers", zap.Error(err), zap.Any("req", req))
+		}
+	}
+	return response, err
+}
+
+func (s *service) CreateInspection(ctx context.Context, req models.CreateTrailerInspectionRequest) (models.GetTrailerInspectionResponse, error) {
+	trailer, err := s.Get(ctx, req.TrailerID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.GetTrailerInspectionResponse{}, validator.NewValidationError("trailer_id", "trailer not found")
+		}
+		return models.GetTrailerInspectionResponse{}, err
+	}
+
+	req.ID = uuid.New().String()
+
+	if err = s.trailerRepository.CreateInspection(ctx, req); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not create trailer inspection", zap.Error(err), zap.Any("req", req))
+		return models.GetTrailerInspectionResponse{}, errors.Wrap(err, "could not create trailer inspection")
+	}
+
+	response, err := s.trailerRepository.GetInspection(ctx, req.ID)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not get trailer inspection", zap.Error(err), zap.String("inspectionID", req.ID))
+		return models.GetTrailerInspectionResponse{}, err
+	}
+	response.Trailer = trailer
+
+	return response, nil
+}
+
+func (s *service) GetLatestInspection(ctx context.Context, req models.GetTrailerInspectionRequest) (models.GetTrailerInspectionResponse, error) {
+	trailer, err := s.Get(ctx, req.TrailerID)
+	if err != nil {
+		return models.GetTrailerInspectionResponse{}, err
+	}
+
+	response, err := s.trailerRepository.GetLatestInspection(ctx, req.TrailerID)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get latest trailer inspection", zap.Error(err), zap.Any("req", req))
+		}
+		return models.GetTrailerInspectionResponse{}, err
+	}
+	response.Trailer = trailer
+
+	return response, nil
+}
+
+func (s *service) GetPickUpDropOffHistory(ctx context.Context, req models.GetTrailerPickUpDropOffHistoryRequest) (models.GetTrailerPickUpDropOffHistoryResponse, error) {
+	if _, err := s.Get(ctx, req.TrailerID); err != nil {
+		return models.GetTrailerPickUpDropOffHistoryResponse{}, err
+	}
+
+	response, err := s.trailerRepository.GetPickUpDropOffHistory(ctx, req)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get trailer pickup/drop-off history", zap.Error(err), zap.Any("req", req))
+		}
+	}
+	return response, err
+}
@@ -0,0 +1,266 @@
+package truck_service
+
+import (
+	"context"
+
+	"github.com/abdivasiyev/project_template/config"
+	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/internal/repository"
+	v1 "github.com/abdivasiyev/project_template/internal/services/v1"
+	"github.com/abdivasiyev/project_template/pkg/logger"
+	"github.com/abdivasiyev/project_template/pkg/sentry"
+	"github.com/abdivasiyev/project_template/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var Module = fx.Provide(NewService)
+
+const (
+	inspectionTypePickUp  = "pickup"
+	inspectionTypeDropOff = "drop-off"
+)
+
+type service struct {
+	environment          string
+	log                  logger.Logger
+	sentry               sentry.Handler
+	truckRepository      repository.Truck
+	fileRepository       repository.File
+	driverRepository     repository.Driver
+	assignmentRepository repository.Assignment
+}
+
+type Params struct {
+	fx.In
+	Config               config.Config
+	Log                  logger.Logger
+	Sentry               sentry.Handler
+	TruckRepository      repository.Truck
+	FileRepository       repository.File
+	DriverRepository     repository.Driver
+	AssignmentRepository repository.Assignment
+}
+
+func NewService(params Params) v1.TruckServiceV1 {
+	return &service{
+		environment:          params.Config.GetString(config.EnvironmentKey),
+		log:                  params.Log,
+		sentry:               params.Sentry,
+		truckRepository:      params.TruckRepository,
+		fileRepository:       params.FileRepository,
+		driverRepository:     params.DriverRepository,
+		assignmentRepository: params.AssignmentRepository,
+	}
+}
+
+func (s *service) Create(ctx context.Context, req models.CreateTruckRequest) (models.GetTruckResponse, error) {
+	req.ID = uuid.New().String()
+
+	if err := s.validateDriver(ctx, req.DriverID); err != nil {
+		return models.GetTruckResponse{}, err
+	}
+
+	// truck is saved together with assignment of driver, so failed assignment does not leave truck behind
+	if err := s.truckRepository.Create(ctx, req); err != nil {
+		if !errors.Is(err, models.ErrNotFound) && !errors.Is(err, models.ErrConflict) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not create truck", zap.Error(err), zap.Any("req", req))
+		}
+		return models.GetTruckResponse{}, errors.Wrap(err, "could not create truck")
+	}
+
+	return s.Get(ctx, req.ID)
+}
+
+func (s *service) Update(ctx context.Context, req models.CreateTruckRequest) (models.GetTruckResponse, error) {
+	if err := s.validateDriver(ctx, req.DriverID); err != nil {
+		return models.GetTruckResponse{}, err
+	}
+
+	if err := s.truckRepository.Update(ctx, req); err != nil {
+		if !errors.Is(err, models.ErrNotFound) && !errors.Is(err, models.ErrConflict) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not update truck", zap.Error(err), zap.Any("req", req))
+		}
+		return models.GetTruckResponse{}, errors.Wrap(err, "could not update truck")
+	}
+
+	return s.Get(ctx, req.ID)
+}
+
+func (s *service) validateDriver(ctx context.Context, driverID string) error {
+	if driverID == "" {
+		return nil
+	}
+
+	if _, err := s.driverRepository.Get(ctx, driverID); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return validator.NewValidationError("driver_id", "driver not found")
+		}
+		s.sentry.HandleError(err)
+		s.log.Error("could not get driver", zap.Error(err), zap.String("driverID", driverID))
+		return errors.Wrap(err, "could not get driver")
+	}
+
+	return nil
+}
+
+func (s *service) Delete(ctx context.Context, id string) error {
+	err := s.truckRepository.Delete(ctx, id)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not delete truck", zap.Error(err), zap.String("truckID", id))
+		}
+		return err
+	}
+
+	err = s.assignmentRepository.Unassign(ctx, models.UnassignDriverRequest{
+		EntityType: "truck",
+		EntityID:   id,
+	})
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		s.sentry.HandleError(err)
+		s.log.Error("could not close assignment of deleted truck", zap.Error(err), zap.String("truckID", id))
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) Get(ctx context.Context, id string) (models.GetTruckResponse, error) {
+	response, err := s.truckRepository.Get(ctx, id)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get truck", zap.Error(err), zap.String("truckID", id))
+		}
+	}
+	return response, err
+}
+
+func (s *service) GetAll(ctx context.Context, req models.GetAllTrucksRequest) (models.GetAllTrucksResponse, error) {
+	response, err := s.truckRepository.GetAll(ctx, req)
+	if err != nil {
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not get all trucks", zap.Error(err), zap.Any("req", req))
+		}
+	}
+	return response, err
+}
+
+func (s *service) CreateInspection(ctx context.Context, req models.CreateTruckInspectionRequest) (models.GetTruckInspectionResponse, error) {
+	truck, err := s.Get(ctx, req.TruckID)
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return models.GetTruckInspectionResponse{}, validator.NewValidationError("truck_id", "truck not found")
+		}
+		return models.GetTruckInspectionResponse{}, err
+	}
+
+	if err = s.validateInspectionFiles(ctx, req); err != nil {
+		return models.GetTruckInspectionResponse{}, err
+	}
+
+	req.ID = uuid.New().String()
+
+	if err = s.truckRepository.CreateInspection(ctx, req); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not create truck inspection", zap.Error(err), zap.Any("req", req))
+		return models.GetTruckInspectionResponse{}, errors.Wrap(err, "could not create truck inspection")
+	}
+
+	response, err := s.truckRepository.GetInspection(ctx, req.ID)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not get truck inspection", zap.Error(err), zap.String("inspectionID", req.ID))
+		return models.GetTruckInspectionResponse{}, err
+	}
+	response.Truck = truck
+
+	return response, nil
+}
+
+// validateInspectionFiles checks that every image and signature of inspection is uploaded file
+func (s *service) validateInspectionFiles(ctx context.Context, req models.CreateTruckInspectionRequest) error {
+	fields := []struct {
+		name string
+		ids  []string
+	}{
+		{name: "odometer_images", ids: req.OdometerImages},
+		{name: "fuel_level_images", ids: req.FuelLevelImages},
+		{name: "driver_side_images", ids: req.DriverSideImages},
+		{name: "front_side_images", ids: req.FrontSideImages},
+		{name: "passenger_side_images", ids: req.PassengerSideImages},
+		{name: "back_side_images", ids: req.BackSideImages},
+		{name: "tire_images", ids: req.TireImages},
+		{name: "damage_images", ids: req.DamageImages},
+		{name: "driver_signature_id", ids: []string{req.DriverSignatureID}},
+		{name: "company_representative_signature_id", ids: []string{req.CompanyRepresentativeSignatureID}},
+	}
+
+	var ids []string
+	for _, field := range fields {
+		ids = append(ids, field.ids...)
+	}
+
+	missing, err := s.fileRepository.FindMissing(ctx, ids)
+	if err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not check inspection files", zap.Error(err), zap.Strings("ids", ids))
+		return errors.Wrap(err, "could not check inspection files")
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	missingSet := make(map[string]struct{}, len(missing))
+	for _, id := range missing {
+		missingSet[id] = struct{}{}
+	}
+
+	for _, field := range fields {
+		for _, id := range field.ids {
+			if _, ok := missingSet[id]; ok {
+				return validator.NewValidationError(field.name, "file "+id+" not found")
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *service) GetLatestInspections(ctx context.Context, req models.GetTruckInspectionRequest) (models.GetTruckLatestInspectionsResponse, error) {
+	var response models.GetTruckLatestInspectionsResponse
+
+	truck, err := s.Get(ctx, req.TruckID)
+	if err != nil {
+		return response, err
+	}
+
+	for _, inspectionType := range []string{inspectionTypePickUp, inspectionTypeDropOff} {
+		inspection, err := s.truckRepository.GetLatestInspection(ctx, req.TruckID, inspectionType)
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				continue
+			}
+			s.sentry.HandleError(err)
+			s.log.Error("could not get latest truck inspection", zap.Error(err), zap.Any("req", req), zap.String("inspectionType", inspectionType))
+			return models.GetTruckLatestInspectionsResponse{}, err
+		}
+		inspection.Truck = truck
+
+		if inspectionType == inspectionTypePickUp {
+			response.PickUp = &inspection
+		} else {
+			response.DropOff = &inspection
+		}
+	}
+
+	return response, nil
+}
@@ -27,6 +27,7 @@ type service struct {
 	userRepository       repository.User
 	security             security.Handler
 	permissionRepository repository.Permission
+	sessionRepository    repository.Session
 }
 
 type Params struct {
@@ -37,6 +38,7 @@ type Params struct {
 	UserRepository       repository.User
 	Security             security.Handler
 	PermissionRepository repository.Permission
+	SessionRepository    repository.Session
 }
 
 func NewService(params Params) v1.UserServiceV1 {
@@ -47,6 +49,7 @@ func NewService(params Params) v1.UserServiceV1 {
 		userRepository:       params.UserRepository,
 		security:             params.Security,
 		permissionRepository: params.PermissionRepository,
+		sessionRepository:    params.SessionRepository,
 	}
 }
 
@@ -57,8 +60,23 @@ func (s *service) Delete(ctx context.Context, id string) error {
 			s.sentry.HandleError(err)
 			s.log.Error("could not delete user", zap.Error(err), zap.Any("userID", id))
 		}
+		return err
 	}
-	return err
+
+	// deleted user must not be able to use already issued tokens
+	if err = s.security.RevokeUserTokens(ctx, id); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not revoke user tokens", zap.Error(err), zap.Any("userID", id))
+		return errors.Wrap(err, "could not revoke user tokens")
+	}
+
+	if err = s.sessionRepository.RevokeByUser(ctx, id); err != nil {
+		s.sentry.HandleError(err)
+		s.log.Error("could not revoke user sessions", zap.Error(err), zap.Any("userID", id))
+		return errors.Wrap(err, "could not revoke user sessions")
+	}
+
+	return nil
 }
 
 func (s *service) UpdateProfile(ctx context.Context, req models.UpdateProfileRequest) (models.GetUserResponse, error) {
@@ -96,8 +114,10 @@ func (s *service) Update(ctx context.Context, req models.UpdateUserRequest) (mod
 	req.NewPassword = newPasswordHash
 
 	if err = s.userRepository.Update(ctx, req); err != nil {
-		s.sentry.HandleError(err)
-		s.log.Error("could not update user", zap.Error(err), zap.Any("req", req))
+		if !errors.Is(err, models.ErrNotFound) {
+			s.sentry.HandleError(err)
+			s.log.Error("could not update user", zap.Error(err), zap.Any("req", req))
+		}
 		return models.GetUserResponse{}, errors.Wrap(err, "could not create user")
 	}
 
@@ -21,6 +21,7 @@ type MiddlewareServiceV1 interface {
 	Log(ctx context.Context, statusCode int, request *http.Request, clientIP string, startTime, endTime time.Time, errors []error, timeFormat string)
 	HasAccess(ctx context.Context, userID, path, method string, fn func(queryParam string) string) error
 	CheckAuth(ctx context.Context, token string) (models.GetUserResponse, error)
+	GetTenant(ctx context.Context, user models.GetUserResponse, companyID string) (models.Tenant, error)
 }
 
 type UserServiceV1 interface {
@@ -45,9 +46,141 @@ type AuthServiceV1 interface {
 	ResetPassword(ctx context.Context, req models.ResetPasswordRequest) (models.SuccessResponse, error)
 	Login(ctx context.Context, request models.LoginRequest) (models.AuthenticationResponse, error)
 	Refresh(ctx context.Context, request models.RefreshTokenRequest) (models.AuthenticationResponse, error)
+	Logout(ctx context.Context, request models.LogoutRequest) (models.SuccessResponse, error)
+	LogoutAll(ctx context.Context, userID string) (models.SuccessResponse, error)
+	VerifyTwoFactor(ctx context.Context, req models.VerifyTwoFactorRequest) (models.AuthenticationResponse, error)
+	SetupTwoFactor(ctx context.Context, userID string) (models.SetupTwoFactorResponse, error)
+	ConfirmTwoFactor(ctx context.Context, req models.TwoFactorCodeRequest) (models.RecoveryCodesResponse, error)
+	DisableTwoFactor(ctx context.Context, req models.TwoFactorCodeRequest) (models.SuccessResponse, error)
+	Unlock(ctx context.Context, req models.UnlockLoginRequest) (models.SuccessResponse, error)
 }
 
 type FileServiceV1 interface {
 	UploadFile(ctx context.Context, file *multipart.FileHeader) (models.GetFileResponse, error)
 	GetFile(ctx context.Context, id string) (models.GetFileResponse, string, error)
+	GenerateZipFile(ctx context.Context, req models.GenerateZipFileRequest) (models.GenerateZipFileResponse, error)
+	GetZipFile(ctx context.Context, requestID string) (models.GetFileResponse, error)
+}
+
+type TruckServiceV1 interface {
+	Create(ctx context.Context, req models.CreateTruckRequest) (models.GetTruckResponse, error)
+	Update(ctx context.Context, req models.CreateTruckRequest) (models.GetTruckResponse, error)
+	Delete(ctx context.Context, id string) error
+	Get(ctx context.Context, id string) (models.GetTruckResponse, error)
+	GetAll(ctx context.Context, req models.GetAllTrucksRequest) (models.GetAllTrucksResponse, error)
+	CreateInspection(ctx context.Context, req models.CreateTruckInspectionRequest) (models.GetTruckInspectionResponse, error)
+	GetLatestInspections(ctx context.Context, req models.GetTruckInspectionRequest) (models.GetTruckLatestInspectionsResponse, error)
+}
+
+type TrailerServiceV1 interface {
+	Create(ctx context.Context, req models.CreateTrailerRequest) (models.GetTrailerResponse, error)
+	Update(ctx context.Context, req models.CreateTrailerRequest) (models.GetTrailerResponse, error)
+	Delete(ctx context.Context, id string) error
+	Get(ctx context.Context, id string) (models.GetTrailerResponse, error)
+	GetAll(ctx context.Context, req models.GetAllTrailersRequest) (models.GetAllTrailersResponse, error)
+	CreateInspection(ctx context.Context, req models.CreateTrailerInspectionRequest) (models.GetTrailerInspectionResponse, error)
+	GetLatestInspection(ctx context.Context, req models.GetTrailerInspectionRequest) (models.GetTrailerInspectionResponse, error)
+	GetPickUpDropOffHistory(ctx context.Context, req models.GetTrailerPickUpDropOffHistoryRequest) (models.GetTrailerPickUpDropOffHistoryResponse, error)
+}
+
+type CarServiceV1 interface {
+	Create(ctx context.Context, req models.CreateCarRequest) (models.GetCarResponse, error)
+	Update(ctx context.Context, req models.CreateCarRequest) (models.GetCarResponse, error)
+	Delete(ctx context.Context, id string) error
+	Get(ctx context.Context, id string) (models.GetCarResponse, error)
+	GetAll(ctx context.Context, req models.GetAllCarsRequest) (models.GetAllCarsResponse, error)
+	CreatePickupDropOff(ctx context.Context, req models.CarPickupDropOffRequest) (models.GetCarPickupDropOffResponse, error)
+	GetPickupDropOffHistory(ctx context.Context, req models.GetCarPickupDropOffHistoryRequest) (models.GetCarPickupDropOffHistoryResponse, error)
+}
+
+type DriverServiceV1 interface {
+	Create(ctx context.Context, req models.CreateDriverRequest) (models.GetDriverResponse, error)
+	Update(ctx context.Context, req models.CreateDriverRequest) (models.GetDriverResponse, error)
+	Get(ctx context.Context, id string) (models.GetDriverResponse, error)
+	GetAll(ctx context.Context, req models.GetAllDriversRequest) (models.GetAllDriversResponse, error)
+}
+
+type FormServiceV1 interface {
+	Create(ctx context.Context, req models.CreateFormRequest) (models.GetFormResponse, error)
+	Update(ctx context.Context, req models.CreateFormRequest) (models.GetFormResponse, error)
+	Delete(ctx context.Context, id string) error
+	Get(ctx context.Context, req models.GetFormRequest) (models.GetFormResponse, error)
+	GetAll(ctx context.Context, req models.GetAllFormRequest) (models.GetAllFormResponse, error)
+	GetVersions(ctx context.Context, id string) (models.GetAllFormVersionsResponse, error)
+	CreateGroup(ctx context.Context, req models.CreateFormGroupRequest) (models.GetFormGroupResponse, error)
+	UpdateGroup(ctx context.Context, req models.CreateFormGroupRequest) (models.GetFormGroupResponse, error)
+	GetGroup(ctx context.Context, id string) (models.GetFormGroupResponse, error)
+	SetFieldValues(ctx context.Context, req models.SetFormFieldValue) (models.SuccessResponse, error)
+	GetFieldValueHistory(ctx context.Context, req models.GetFormFieldValueHistoryRequest) (models.GetFormFieldValueHistoryResponse, error)
+	RevertFieldValue(ctx context.Context, req models.RevertFormFieldValueRequest) (models.SuccessResponse, error)
+}
+
+type CommentServiceV1 interface {
+	Create(ctx context.Context, req models.CreateCommentRequest) (models.GetCommentResponse, error)
+	Update(ctx context.Context, req models.UpdateCommentRequest) (models.GetCommentResponse, error)
+	Delete(ctx context.Context, id, userID string) error
+	Get(ctx context.Context, id, userID string) (models.GetCommentResponse, error)
+	GetAll(ctx context.Context, req models.GetAllCommentsRequest) (models.GetAllCommentsResponse, error)
+}
+
+type SessionServiceV1 interface {
+	Revoke(ctx context.Context, req models.RevokeSessionRequest) error
+	GetAll(ctx context.Context, req models.GetAllSessionsRequest) (models.GetAllSessionsResponse, error)
+}
+
+type StatusServiceV1 interface {
+	Create(ctx context.Context, req models.CreateStatusRequest) (models.GetStatusResponse, error)
+	Update(ctx context.Context, req models.UpdateStatusRequest) (models.GetStatusResponse, error)
+	Delete(ctx context.Context, id string) error
+	Reorder(ctx context.Context, req models.ReorderStatusesRequest) (models.GetAllStatusesResponse, error)
+	Get(ctx context.Context, id string) (models.GetStatusResponse, error)
+	GetAll(ctx context.Context, req models.GetAllStatusesRequest) (models.GetAllStatusesResponse, error)
+}
+
+type AssignmentServiceV1 interface {
+	Assign(ctx context.Context, req models.AssignDriverRequest) (models.GetAssignmentResponse, error)
+	Unassign(ctx context.Context, req models.UnassignDriverRequest) error
+	GetHistory(ctx context.Context, req models.GetAssignmentHistoryRequest) (models.GetAssignmentHistoryResponse, error)
+}
+
+type EntityServiceV1 interface {
+	Create(ctx context.Context, req models.CreateEntityRequest) (models.GetEntityResponse, error)
+	GetAll(ctx context.Context, req models.GetEntitiesRequest) (models.GetEntitiesResponse, error)
+}
+
+type DashboardServiceV1 interface {
+	Get(ctx context.Context) (models.GetDashboardResponse, error)
+}
+
+type CompanyServiceV1 interface {
+	Create(ctx context.Context, req models.CreateCompanyRequest) (models.GetCompanyResponse, error)
+	Update(ctx context.Context, req models.UpdateCompanyRequest) (models.GetCompanyResponse, error)
+	Delete(ctx context.Context, id string) error
+	Get(ctx context.Context, id string) (models.GetCompanyResponse, error)
+	GetAll(ctx context.Context, req models.GetAllCompaniesRequest) (models.GetAllCompaniesResponse, error)
+}
+
+type DepartmentServiceV1 interface {
+	Create(ctx context.Context, req models.CreateDepartmentRequest) (models.GetDepartmentResponse, error)
+	Update(ctx context.Context, req models.CreateDepartmentRequest) (models.GetDepartmentResponse, error)
+	Delete(ctx context.Context, id string) error
+	Reorder(ctx context.Context, req models.ReorderDepartmentsRequest) (models.GetAllDepartmentsResponse, error)
+	Get(ctx context.Context, id string) (models.GetDepartmentResponse, error)
+	GetAll(ctx context.Context, req models.GetAllDepartmentsRequest) (models.GetAllDepartmentsResponse, error)
+}
+
+type StepServiceV1 interface {
+	Create(ctx context.Context, req models.CreateStepRequest) (models.GetStepResponse, error)
+	Update(ctx context.Context, req models.CreateStepRequest) (models.GetStepResponse, error)
+	Delete(ctx context.Context, id, userID string) error
+	Get(ctx context.Context, id, userID string) (models.GetStepResponse, error)
+	GetAll(ctx context.Context, departmentID, userID string) (models.GetAllStepsResponse, error)
+	GetAllGroupByStatus(ctx context.Context, req models.GetAllStepsRequest) (models.GetAllStepsGroupByStatusResponse, error)
+	UpdateStatus(ctx context.Context, req models.UpdateStepStatusRequest) (models.SuccessResponse, error)
+	CreateTransition(ctx context.Context, req models.CreateStatusTransitionRequest) (models.GetStatusTransitionResponse, error)
+	DeleteTransition(ctx context.Context, id string) error
+	GetTransitions(ctx context.Context) (models.GetAllStatusTransitionsResponse, error)
+	HasAccess(ctx context.Context, req models.HasAccessRequest) (models.HasAccessResponse, error)
+	SetDepartmentAccess(ctx context.Context, req models.SetDepartmentAccessRequest) (models.GetAllDepartmentAccessResponse, error)
+	GetDepartmentAccesses(ctx context.Context, departmentID string) (models.GetAllDepartmentAccessResponse, error)
 }
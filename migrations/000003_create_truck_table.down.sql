@@ -0,0 +1,3 @@
+drop table if exists truck;
+drop table if exists driver;
+drop table if exists status;
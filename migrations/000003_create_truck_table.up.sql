@@ -0,0 +1,53 @@
+create table if not exists status
+(
+    id          uuid primary key not null default uuid_generate_v4(),
+    entity_type varchar          not null,
+    alias       varchar          not null,
+    name        varchar          not null,
+    sequence    integer          not null default 0,
+    color       varchar          not null default '#000000',
+    created_at  timestamp        not null default current_timestamp,
+    updated_at  timestamp,
+    deleted_at  timestamp
+);
+
+create unique index if not exists idx_status_entity_type_alias on status (entity_type, alias) where deleted_at is null;
+
+create table if not exists driver
+(
+    id         uuid primary key not null,
+    first_name varchar          not null,
+    last_name  varchar          not null,
+    email      varchar          not null,
+    phone      varchar          not null,
+    image_id   uuid references file (id),
+    created_at timestamp        not null default current_timestamp,
+    updated_at timestamp,
+    deleted_at timestamp
+);
+
+create table if not exists truck
+(
+    id                 uuid primary key not null,
+    make               varchar          not null,
+    model              varchar          not null,
+    number             varchar          not null,
+    year_made          integer          not null,
+    milage             numeric          not null default 0,
+    plate_number       varchar          not null,
+    status_id          uuid references status (id),
+    driver_id          uuid references driver (id),
+    driver_assigned_at timestamp,
+    created_at         timestamp        not null default current_timestamp,
+    updated_at         timestamp,
+    deleted_at         timestamp
+);
+
+create unique index if not exists idx_truck_number on truck (number) where deleted_at is null;
+create index if not exists idx_truck_status_id on truck (status_id);
+
+insert into status (id, entity_type, alias, name, sequence, color)
+values ('992433f2-578b-4f3c-8189-1e065ce4a4be', 'truck', 'active', 'Active', 1, '#27AE60'),
+       ('d0b0b091-f522-4756-b322-4237f4d50c99', 'truck', 'in_repair', 'In repair', 2, '#F2994A'),
+       ('04c93289-f08a-4236-9f4f-e01f4252961b', 'truck', 'inactive', 'Inactive', 3, '#EB5757')
+on conflict (id) do nothing;
@@ -0,0 +1,4 @@
+delete from status where entity_type = 'trailer';
+drop table if exists trailer_inspection_comment;
+drop table if exists trailer_inspection;
+drop table if exists trailer;
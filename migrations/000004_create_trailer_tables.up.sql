@@ -0,0 +1,58 @@
+create table if not exists trailer
+(
+    id                 uuid primary key not null,
+    make               varchar          not null,
+    number             varchar          not null,
+    year_made          integer          not null,
+    trailer_type       varchar          not null,
+    plate_number       varchar          not null,
+    status_id          uuid references status (id),
+    driver_id          uuid references driver (id),
+    driver_assigned_at timestamp,
+    created_at         timestamp        not null default current_timestamp,
+    updated_at         timestamp,
+    deleted_at         timestamp
+);
+
+create unique index if not exists idx_trailer_number on trailer (number) where deleted_at is null;
+create index if not exists idx_trailer_status_id on trailer (status_id);
+
+create table if not exists trailer_inspection
+(
+    id                                  uuid primary key not null,
+    trailer_id                          uuid             not null references trailer (id),
+    driver_id                           uuid             not null references driver (id),
+    inspection_type                     varchar          not null,
+    is_empty                            boolean          not null default false,
+    location                            varchar          not null,
+    fuel_level_images                   uuid[]           not null default '{}',
+    left_side_images                    uuid[]           not null default '{}',
+    front_side_images                   uuid[]           not null default '{}',
+    right_side_images                   uuid[]           not null default '{}',
+    back_side_images                    uuid[]           not null default '{}',
+    in_side_images                      uuid[]           not null default '{}',
+    tire_images                         uuid[]           not null default '{}',
+    damage_images                       uuid[]           not null default '{}',
+    need_repair_devices                 varchar[]        not null default '{}',
+    driver_signature_id                 uuid             not null references file (id),
+    company_representative_signature_id uuid             not null references file (id),
+    created_by                          uuid references "user" (id),
+    created_at                          timestamp        not null default current_timestamp
+);
+
+create index if not exists idx_trailer_inspection_trailer_id on trailer_inspection (trailer_id, created_at);
+
+create table if not exists trailer_inspection_comment
+(
+    id            uuid primary key not null default uuid_generate_v4(),
+    inspection_id uuid             not null references trailer_inspection (id),
+    created_by    uuid references "user" (id),
+    text          text             not null,
+    created_at    timestamp        not null default current_timestamp
+);
+
+insert into status (id, entity_type, alias, name, sequence, color)
+values ('4fcd170c-4601-441c-9b87-35229273a79c', 'trailer', 'active', 'Active', 1, '#27AE60'),
+       ('c80907db-18b0-49f9-9e2b-181734131494', 'trailer', 'in_repair', 'In repair', 2, '#F2994A'),
+       ('f5158b0f-695a-4db5-94bc-103a90356361', 'trailer', 'inactive', 'Inactive', 3, '#EB5757')
+on conflict (id) do nothing;
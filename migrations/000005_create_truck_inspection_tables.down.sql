@@ -0,0 +1,2 @@
+drop table if exists truck_inspection_comment;
+drop table if exists truck_inspection;
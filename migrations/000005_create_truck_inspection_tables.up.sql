@@ -0,0 +1,33 @@
+create table if not exists truck_inspection
+(
+    id                                  uuid primary key not null,
+    truck_id                            uuid             not null references truck (id),
+    driver_id                           uuid             not null references driver (id),
+    inspection_type                     varchar          not null,
+    location                            varchar          not null,
+    odometer_images                     uuid[]           not null default '{}',
+    fuel_level_images                   uuid[]           not null default '{}',
+    driver_side_images                  uuid[]           not null default '{}',
+    front_side_images                   uuid[]           not null default '{}',
+    passenger_side_images               uuid[]           not null default '{}',
+    back_side_images                    uuid[]           not null default '{}',
+    tire_images                         uuid[]           not null default '{}',
+    damage_images                       uuid[]           not null default '{}',
+    incab_devices                       varchar[]        not null default '{}',
+    external_displayed                  varchar[]        not null default '{}',
+    driver_signature_id                 uuid             not null references file (id),
+    company_representative_signature_id uuid             not null references file (id),
+    created_by                          uuid references "user" (id),
+    created_at                          timestamp        not null default current_timestamp
+);
+
+create index if not exists idx_truck_inspection_truck_id on truck_inspection (truck_id, inspection_type, created_at);
+
+create table if not exists truck_inspection_comment
+(
+    id            uuid primary key not null default uuid_generate_v4(),
+    inspection_id uuid             not null references truck_inspection (id),
+    created_by    uuid references "user" (id),
+    text          text             not null,
+    created_at    timestamp        not null default current_timestamp
+);
@@ -0,0 +1,3 @@
+drop table if exists car_pickup_dropoff;
+drop table if exists car;
+delete from status where entity_type = 'car';
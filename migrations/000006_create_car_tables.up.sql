@@ -0,0 +1,40 @@
+create table if not exists car
+(
+    id           uuid primary key not null,
+    make         varchar          not null,
+    model        varchar          not null,
+    year         integer          not null,
+    color        varchar          not null,
+    plate_number varchar          not null,
+    image_id     uuid references file (id),
+    status_id    uuid references status (id),
+    created_at   timestamp        not null default current_timestamp,
+    updated_at   timestamp,
+    deleted_at   timestamp
+);
+
+create unique index if not exists idx_car_plate_number on car (plate_number) where deleted_at is null;
+
+create table if not exists car_pickup_dropoff
+(
+    id              uuid primary key not null,
+    car_id          uuid             not null references car (id),
+    full_name       varchar          not null,
+    phone_number    varchar          not null,
+    company_id      uuid references company (id),
+    key_given_by    varchar          not null,
+    key_given_by_id uuid references "user" (id),
+    location        varchar          not null,
+    odometer        varchar          not null,
+    pickup_date     timestamp        not null,
+    drop_off_date   timestamp        not null,
+    type            varchar          not null,
+    created_at      timestamp        not null default current_timestamp
+);
+
+create index if not exists idx_car_pickup_dropoff_car_id on car_pickup_dropoff (car_id, created_at);
+
+insert into status (id, entity_type, alias, name, sequence, color)
+values ('bfe1039f-447d-46ac-b11e-55415cbb1564', 'car', 'available', 'Available', 1, '#27AE60'),
+       ('8629aa4b-95b0-4ac7-a6d0-3aabefc4683c', 'car', 'booked', 'Booked', 2, '#EB5757')
+on conflict (id) do nothing;
@@ -0,0 +1,9 @@
+drop table if exists driver_step;
+drop table if exists step;
+drop table if exists department;
+
+alter table driver
+    drop column if exists status_id,
+    drop column if exists on_board_date;
+
+delete from status where entity_type in ('driver', 'step');
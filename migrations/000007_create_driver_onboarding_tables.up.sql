@@ -0,0 +1,57 @@
+create table if not exists department
+(
+    id          uuid primary key not null,
+    alias       varchar          not null,
+    name        varchar          not null,
+    sequence    integer          not null default 0,
+    start_color varchar          not null default '#000000',
+    end_color   varchar          not null default '#000000',
+    created_at  timestamp        not null default current_timestamp,
+    updated_at  timestamp,
+    deleted_at  timestamp
+);
+
+create unique index if not exists idx_department_alias on department (alias) where deleted_at is null;
+
+create table if not exists step
+(
+    id            uuid primary key not null,
+    department_id uuid             not null references department (id),
+    alias         varchar          not null,
+    name          varchar          not null,
+    sequence      integer          not null default 0,
+    created_at    timestamp        not null default current_timestamp,
+    updated_at    timestamp,
+    deleted_at    timestamp
+);
+
+create unique index if not exists idx_step_department_id_alias on step (department_id, alias) where deleted_at is null;
+
+create table if not exists driver_step
+(
+    driver_id  uuid      not null references driver (id),
+    step_id    uuid      not null references step (id),
+    status_id  uuid      not null references status (id),
+    created_at timestamp not null default current_timestamp,
+    updated_at timestamp,
+    primary key (driver_id, step_id)
+);
+
+alter table driver
+    add column if not exists status_id     uuid references status (id),
+    add column if not exists on_board_date timestamp;
+
+create index if not exists idx_driver_status_id on driver (status_id);
+
+insert into status (id, entity_type, alias, name, sequence, color)
+values ('6b0f2f0e-0c39-4c43-a1f1-2f4be3a0b5a1', 'driver', 'pending', 'Pending', 1, '#F2C94C'),
+       ('a3c4a0de-55d4-4b8e-9a54-6f1f0c6a7d3e', 'driver', 'in_process', 'In process', 2, '#2F80ED'),
+       ('f0d7b3c5-2c61-4f0e-8d0b-93e7a1c4e6b2', 'driver', 'active', 'Active', 3, '#27AE60'),
+       ('1e5c8a47-7b1d-4c9e-b6f3-0a2d9e8c5f14', 'step', 'to_do', 'To do', 1, '#BDBDBD'),
+       ('7d2e6b90-3f4a-4e1c-9c85-5b1a7f0e2d36', 'step', 'in_progress', 'In progress', 2, '#2F80ED'),
+       ('c9a1f3e2-8b6d-4a7c-b0e5-4d2f1a9c3e87', 'step', 'completed', 'Completed', 3, '#27AE60')
+on conflict (id) do nothing;
+
+update driver
+set status_id = '6b0f2f0e-0c39-4c43-a1f1-2f4be3a0b5a1'
+where status_id is null;
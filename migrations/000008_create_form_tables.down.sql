@@ -0,0 +1,5 @@
+drop table if exists form_version;
+drop table if exists form_field;
+drop table if exists form_group_relation;
+drop table if exists form_group;
+drop table if exists form;
@@ -0,0 +1,67 @@
+create table if not exists form
+(
+    id         uuid primary key not null,
+    title      varchar          not null,
+    version    integer          not null default 1,
+    created_at timestamp        not null default current_timestamp,
+    updated_at timestamp,
+    deleted_at timestamp
+);
+
+create table if not exists form_group
+(
+    id         uuid primary key not null,
+    title      varchar          not null,
+    alias      varchar          not null,
+    sequence   integer          not null default 0,
+    created_at timestamp        not null default current_timestamp,
+    updated_at timestamp,
+    deleted_at timestamp
+);
+
+create table if not exists form_group_relation
+(
+    form_id  uuid not null references form (id),
+    group_id uuid not null references form_group (id),
+    primary key (form_id, group_id)
+);
+
+create index if not exists idx_form_group_relation_group_id on form_group_relation (group_id);
+
+create table if not exists form_field
+(
+    id             uuid primary key not null,
+    group_id       uuid             not null references form_group (id),
+    parent_id      uuid references form_field (id),
+    label          varchar          not null,
+    label_position varchar          not null default 'top',
+    icon           varchar,
+    alias          varchar,
+    hint           varchar,
+    warning        varchar,
+    sequence       integer          not null default 0,
+    type           varchar          not null,
+    validation     jsonb            not null default '{}',
+    multiple       boolean          not null default false,
+    list_type      varchar,
+    can_search     boolean          not null default false,
+    can_add_item   boolean          not null default false,
+    can_open_popup boolean          not null default false,
+    grouped        boolean          not null default false,
+    is_signature   boolean          not null default false,
+    action         varchar,
+    created_at     timestamp        not null default current_timestamp,
+    updated_at     timestamp,
+    deleted_at     timestamp
+);
+
+create index if not exists idx_form_field_group_id on form_field (group_id) where deleted_at is null;
+
+create table if not exists form_version
+(
+    form_id    uuid      not null references form (id),
+    version    integer   not null,
+    body       jsonb     not null,
+    created_at timestamp not null default current_timestamp,
+    primary key (form_id, version)
+);
@@ -0,0 +1,4 @@
+drop table if exists form_field_value;
+
+alter table step
+    drop column if exists form_id;
@@ -0,0 +1,16 @@
+alter table step
+    add column if not exists form_id uuid references form (id);
+
+create table if not exists form_field_value
+(
+    id         uuid primary key not null,
+    driver_id  uuid             not null references driver (id),
+    step_id    uuid             not null references step (id),
+    field_id   uuid             not null references form_field (id),
+    value      text             not null,
+    created_by uuid references "user" (id),
+    created_at timestamp        not null default current_timestamp,
+    deleted_at timestamp
+);
+
+create index if not exists idx_form_field_value_driver_id_step_id on form_field_value (driver_id, step_id) where deleted_at is null;
@@ -0,0 +1,4 @@
+alter table form_field_value
+    drop column if exists revision_id;
+
+drop table if exists form_field_revision;
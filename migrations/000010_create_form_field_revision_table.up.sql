@@ -0,0 +1,31 @@
+create table if not exists form_field_revision
+(
+    id         uuid primary key not null,
+    driver_id  uuid             not null references driver (id),
+    step_id    uuid             not null references step (id),
+    field_id   uuid             not null references form_field (id),
+    created_by uuid references "user" (id),
+    created_at timestamp        not null default current_timestamp
+);
+
+create index if not exists idx_form_field_revision_driver_id_field_id on form_field_revision (driver_id, field_id, created_at);
+
+alter table form_field_value
+    add column if not exists revision_id uuid references form_field_revision (id);
+
+insert into form_field_revision (id, driver_id, step_id, field_id, created_by, created_at)
+select uuid_generate_v4(), driver_id, step_id, field_id, (array_agg(created_by))[1], created_at
+from form_field_value
+where revision_id is null
+group by driver_id, step_id, field_id, created_at;
+
+update form_field_value v
+set revision_id = r.id
+from form_field_revision r
+where v.revision_id is null
+  and r.driver_id = v.driver_id
+  and r.step_id = v.step_id
+  and r.field_id = v.field_id
+  and r.created_at = v.created_at;
+
+create index if not exists idx_form_field_value_revision_id on form_field_value (revision_id);
@@ -0,0 +1,19 @@
+create table if not exists status_transition
+(
+    id             uuid primary key not null,
+    from_status_id uuid             not null references status (id),
+    to_status_id   uuid             not null references status (id),
+    role_id        uuid references role (id),
+    created_at     timestamp        not null default current_timestamp,
+    deleted_at     timestamp
+);
+
+create index if not exists idx_status_transition_from_status_id on status_transition (from_status_id, to_status_id) where deleted_at is null;
+
+-- to_do -> in_progress -> completed, started and completed steps can be moved back
+insert into status_transition (id, from_status_id, to_status_id)
+values ('0b6c1a3e-8f4d-4e2a-9c71-3d5e2f8a1b04', '1e5c8a47-7b1d-4c9e-b6f3-0a2d9e8c5f14', '7d2e6b90-3f4a-4e1c-9c85-5b1a7f0e2d36'),
+       ('5f2a9d7c-1e3b-4c8a-b6d0-7a4e1c9f2b35', '7d2e6b90-3f4a-4e1c-9c85-5b1a7f0e2d36', 'c9a1f3e2-8b6d-4a7c-b0e5-4d2f1a9c3e87'),
+       ('9c4e7b21-6a5d-4f3e-8b12-0d9a6c3e5f71', '7d2e6b90-3f4a-4e1c-9c85-5b1a7f0e2d36', '1e5c8a47-7b1d-4c9e-b6f3-0a2d9e8c5f14'),
+       ('e3d8a6f5-2b7c-4a9e-9d41-6f0b3c8e2a19', 'c9a1f3e2-8b6d-4a7c-b0e5-4d2f1a9c3e87', '7d2e6b90-3f4a-4e1c-9c85-5b1a7f0e2d36')
+on conflict (id) do nothing;
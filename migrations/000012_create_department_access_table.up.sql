@@ -0,0 +1,12 @@
+create table if not exists department_access
+(
+    department_id uuid      not null references department (id),
+    role_id       uuid      not null references role (id),
+    can_view      boolean   not null default false,
+    can_edit      boolean   not null default false,
+    can_create    boolean   not null default false,
+    can_delete    boolean   not null default false,
+    created_at    timestamp not null default current_timestamp,
+    updated_at    timestamp,
+    primary key (department_id, role_id)
+);
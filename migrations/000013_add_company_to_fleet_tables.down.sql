@@ -0,0 +1,13 @@
+drop index if exists idx_user_company_id;
+
+alter table driver
+    drop column if exists company_id;
+
+alter table car
+    drop column if exists company_id;
+
+alter table trailer
+    drop column if exists company_id;
+
+alter table truck
+    drop column if exists company_id;
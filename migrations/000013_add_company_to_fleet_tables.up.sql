@@ -0,0 +1,17 @@
+alter table truck
+    add column if not exists company_id uuid references company (id);
+
+alter table trailer
+    add column if not exists company_id uuid references company (id);
+
+alter table car
+    add column if not exists company_id uuid references company (id);
+
+alter table driver
+    add column if not exists company_id uuid references company (id);
+
+create index if not exists idx_truck_company_id on truck (company_id);
+create index if not exists idx_trailer_company_id on trailer (company_id);
+create index if not exists idx_car_company_id on car (company_id);
+create index if not exists idx_driver_company_id on driver (company_id);
+create index if not exists idx_user_company_id on "user" (company_id);
@@ -0,0 +1,27 @@
+create table if not exists lookup_value
+(
+    id          uuid primary key not null,
+    entity_type varchar          not null,
+    name        varchar          not null,
+    created_by  uuid references "user" (id),
+    created_at  timestamp        not null default current_timestamp,
+    updated_at  timestamp,
+    deleted_at  timestamp
+);
+
+create unique index if not exists idx_lookup_value_entity_type_name on lookup_value (entity_type, lower(name)) where deleted_at is null;
+
+insert into lookup_value (id, entity_type, name)
+values ('3f1c9a52-6d2e-4b7a-9e41-0c8d5f2a7b13', 'driver_type', 'Company driver'),
+       ('8a4e2d61-1b7c-4f3e-a5d9-6e2b0c9f4a28', 'driver_type', 'Owner operator'),
+       ('c27b5e94-3a1d-4e8f-b6c2-9d4a7f1e0b35', 'driver_type', 'Lease operator'),
+       ('5d9f1a37-8c4b-4e2a-9f6d-2b7e0a3c8d41', 'drug_test_type', 'Pre-employment'),
+       ('e6a3c8b2-4f9d-4a1e-8b5c-7d2f0e9a6c57', 'drug_test_type', 'Random'),
+       ('1b8d4f6a-9e2c-4d7b-a3f5-0c6e8b2d9a64', 'drug_test_type', 'Post-accident'),
+       ('9c2e7a41-5b8f-4c3d-9e6a-4f1b0d7c2e78', 'fuel_card_status', 'Not issued'),
+       ('4a7f2c9e-1d6b-4e8a-b3c5-8e0f9a2d7b16', 'fuel_card_status', 'Issued'),
+       ('b5e1d8a3-7c2f-4b9e-8a4d-1f6c3e0b9a27', 'fuel_card_status', 'Blocked'),
+       ('6f3a9d2c-8e1b-4a7f-9c5e-3b0d8a6f1c49', 'road_test_status', 'Scheduled'),
+       ('d8c4b1e7-2a9f-4d6c-8e3b-5a1f7c0e2d93', 'road_test_status', 'Passed'),
+       ('2e9b6f4a-3c8d-4b1e-a7f2-9d5c0e8b4a16', 'road_test_status', 'Failed')
+on conflict (id) do nothing;
@@ -0,0 +1,21 @@
+alter table truck
+    add column if not exists driver_id          uuid references driver (id),
+    add column if not exists driver_assigned_at timestamp;
+
+alter table trailer
+    add column if not exists driver_id          uuid references driver (id),
+    add column if not exists driver_assigned_at timestamp;
+
+update truck t
+set driver_id          = da.driver_id,
+    driver_assigned_at = da.assigned_at
+from driver_assignment da
+where da.truck_id = t.id and da.unassigned_at is null;
+
+update trailer tr
+set driver_id          = da.driver_id,
+    driver_assigned_at = da.assigned_at
+from driver_assignment da
+where da.trailer_id = tr.id and da.unassigned_at is null;
+
+drop table if exists driver_assignment;
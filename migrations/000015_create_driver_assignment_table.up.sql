@@ -0,0 +1,50 @@
+create table if not exists driver_assignment
+(
+    id            uuid primary key not null,
+    driver_id     uuid             not null references driver (id),
+    truck_id      uuid references truck (id),
+    trailer_id    uuid references trailer (id),
+    assigned_by   uuid references "user" (id),
+    assigned_at   timestamp        not null default current_timestamp,
+    unassigned_at timestamp,
+    check ((truck_id is null) <> (trailer_id is null))
+);
+
+create unique index if not exists idx_driver_assignment_active_driver_truck on driver_assignment (driver_id)
+    where truck_id is not null and unassigned_at is null;
+create unique index if not exists idx_driver_assignment_active_driver_trailer on driver_assignment (driver_id)
+    where trailer_id is not null and unassigned_at is null;
+create unique index if not exists idx_driver_assignment_active_truck on driver_assignment (truck_id)
+    where unassigned_at is null;
+create unique index if not exists idx_driver_assignment_active_trailer on driver_assignment (trailer_id)
+    where unassigned_at is null;
+create index if not exists idx_driver_assignment_driver_id on driver_assignment (driver_id, assigned_at);
+
+-- only the latest vehicle of driver stays active, older ones are moved to history
+insert into driver_assignment (id, driver_id, truck_id, assigned_at, unassigned_at)
+select uuid_generate_v4(),
+       driver_id,
+       id,
+       coalesce(driver_assigned_at, created_at),
+       case when rn > 1 then current_timestamp end
+from (select t.*, row_number() over (partition by driver_id order by driver_assigned_at desc nulls last) as rn
+      from truck t
+      where driver_id is not null and deleted_at is null) t;
+
+insert into driver_assignment (id, driver_id, trailer_id, assigned_at, unassigned_at)
+select uuid_generate_v4(),
+       driver_id,
+       id,
+       coalesce(driver_assigned_at, created_at),
+       case when rn > 1 then current_timestamp end
+from (select tr.*, row_number() over (partition by driver_id order by driver_assigned_at desc nulls last) as rn
+      from trailer tr
+      where driver_id is not null and deleted_at is null) tr;
+
+alter table truck
+    drop column if exists driver_id,
+    drop column if exists driver_assigned_at;
+
+alter table trailer
+    drop column if exists driver_id,
+    drop column if exists driver_assigned_at;
@@ -0,0 +1,30 @@
+create table if not exists truck_inspection_comment
+(
+    id            uuid primary key not null default uuid_generate_v4(),
+    inspection_id uuid             not null references truck_inspection (id),
+    created_by    uuid references "user" (id),
+    text          text             not null,
+    created_at    timestamp        not null default current_timestamp
+);
+
+create table if not exists trailer_inspection_comment
+(
+    id            uuid primary key not null default uuid_generate_v4(),
+    inspection_id uuid             not null references trailer_inspection (id),
+    created_by    uuid references "user" (id),
+    text          text             not null,
+    created_at    timestamp        not null default current_timestamp
+);
+
+insert into truck_inspection_comment (id, inspection_id, created_by, text, created_at)
+select id, entity_id, created_by, text, created_at
+from comment
+where entity_type = 'truck_inspection' and deleted_at is null;
+
+insert into trailer_inspection_comment (id, inspection_id, created_by, text, created_at)
+select id, entity_id, created_by, text, created_at
+from comment
+where entity_type = 'trailer_inspection' and deleted_at is null;
+
+drop table if exists comment_mention;
+drop table if exists comment;
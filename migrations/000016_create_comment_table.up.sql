@@ -0,0 +1,36 @@
+create table if not exists comment
+(
+    id          uuid primary key not null,
+    entity_type varchar          not null,
+    entity_id   uuid             not null,
+    driver_id   uuid references driver (id),
+    text        text             not null,
+    created_by  uuid references "user" (id),
+    created_at  timestamp        not null default current_timestamp,
+    updated_at  timestamp,
+    deleted_at  timestamp
+);
+
+create index if not exists idx_comment_entity on comment (entity_type, entity_id, created_at) where deleted_at is null;
+
+create table if not exists comment_mention
+(
+    comment_id uuid not null references comment (id),
+    user_id    uuid not null references "user" (id),
+    primary key (comment_id, user_id)
+);
+
+create index if not exists idx_comment_mention_user_id on comment_mention (user_id);
+
+insert into comment (id, entity_type, entity_id, text, created_by, created_at)
+select id, 'truck_inspection', inspection_id, text, created_by, created_at
+from truck_inspection_comment
+on conflict (id) do nothing;
+
+insert into comment (id, entity_type, entity_id, text, created_by, created_at)
+select id, 'trailer_inspection', inspection_id, text, created_by, created_at
+from trailer_inspection_comment
+on conflict (id) do nothing;
+
+drop table if exists truck_inspection_comment;
+drop table if exists trailer_inspection_comment;
@@ -0,0 +1,15 @@
+create table if not exists session
+(
+    id               uuid primary key not null,
+    user_id          uuid             not null references "user" (id),
+    user_agent       varchar          not null default '',
+    ip               varchar          not null default '',
+    access_token_id  uuid             not null,
+    refresh_token_id uuid             not null,
+    created_at       timestamp        not null default current_timestamp,
+    last_seen_at     timestamp        not null default current_timestamp,
+    expires_at       timestamp        not null,
+    revoked_at       timestamp
+);
+
+create index if not exists idx_session_user_id on session (user_id, last_seen_at) where revoked_at is null;
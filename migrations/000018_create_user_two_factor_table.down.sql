@@ -0,0 +1,2 @@
+drop table if exists user_recovery_code;
+drop table if exists user_two_factor;
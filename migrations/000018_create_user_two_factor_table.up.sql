@@ -0,0 +1,18 @@
+create table if not exists user_two_factor
+(
+    user_id    uuid primary key not null references "user" (id),
+    secret     varchar          not null,
+    enabled_at timestamp,
+    created_at timestamp        not null default current_timestamp
+);
+
+create table if not exists user_recovery_code
+(
+    id         uuid primary key not null,
+    user_id    uuid             not null references "user" (id),
+    code_hash  varchar          not null,
+    used_at    timestamp,
+    created_at timestamp        not null default current_timestamp
+);
+
+create index if not exists idx_user_recovery_code_user_id on user_recovery_code (user_id) where used_at is null;
@@ -0,0 +1,7 @@
+drop index if exists idx_lookup_value_entity_type_name;
+drop index if exists idx_lookup_value_company_id;
+
+alter table lookup_value
+    drop column if exists company_id;
+
+create unique index if not exists idx_lookup_value_entity_type_name on lookup_value (entity_type, lower(name)) where deleted_at is null;
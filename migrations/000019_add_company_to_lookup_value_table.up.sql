@@ -0,0 +1,7 @@
+alter table lookup_value
+    add column if not exists company_id uuid references company (id);
+
+create index if not exists idx_lookup_value_company_id on lookup_value (company_id);
+
+drop index if exists idx_lookup_value_entity_type_name;
+create unique index if not exists idx_lookup_value_entity_type_name on lookup_value (entity_type, coalesce(company_id, '00000000-0000-0000-0000-000000000000'), lower(name)) where deleted_at is null;
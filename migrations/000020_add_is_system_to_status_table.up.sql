@@ -0,0 +1,10 @@
+alter table status
+    add column if not exists is_system boolean not null default false;
+
+-- aliases of these statuses are used by code, so they can not be renamed or deleted
+update status
+set is_system = true
+where deleted_at is null
+  and ((entity_type = 'car' and alias in ('available', 'booked'))
+    or (entity_type = 'step' and alias in ('to_do', 'completed'))
+    or (entity_type = 'driver' and alias in ('pending', 'in_process', 'active')));
@@ -8,14 +8,23 @@ import (
 	"github.com/abdivasiyev/project_template/pkg/translator"
 	customValidator "github.com/abdivasiyev/project_template/pkg/validator"
 	"github.com/go-playground/validator/v10"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 )
 
+// uniqueViolation is postgres error code of unique constraint violation
+const uniqueViolation = "23505"
+
 func ToCustomError(err error) error {
 	if errors.Is(err, sql.ErrNoRows) {
 		return models.ErrNotFound
 	}
 
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return models.ErrConflict
+	}
+
 	return err
 }
 
@@ -43,23 +52,34 @@ func ConvertErrorToErrorResponse(statusCode int, err error) models.ErrorResponse
 
 func convertCustomErrors(err error) (models.ErrorResponse, bool) {
 	var (
-		customValidationError customValidator.ValidationError
+		customValidationError  customValidator.ValidationError
+		customValidationErrors customValidator.ValidationErrors
 	)
 
-	if !errors.As(err, &customValidationError) {
-		return models.ErrorResponse{}, false
-	}
-
 	result := models.ErrorResponse{
 		ErrorCode:    http.StatusBadRequest,
 		ErrorMessage: http.StatusText(http.StatusBadRequest),
 	}
 
-	result.Validations = []models.ValidationResponse{
-		{
-			Field: customValidationError.Field(),
-			Error: customValidationError.Message(),
-		},
+	switch {
+	case errors.As(err, &customValidationErrors):
+		result.Validations = make([]models.ValidationResponse, len(customValidationErrors))
+
+		for i, validationErr := range customValidationErrors {
+			result.Validations[i] = models.ValidationResponse{
+				Field: validationErr.Field(),
+				Error: validationErr.Message(),
+			}
+		}
+	case errors.As(err, &customValidationError):
+		result.Validations = []models.ValidationResponse{
+			{
+				Field: customValidationError.Field(),
+				Error: customValidationError.Message(),
+			},
+		}
+	default:
+		return models.ErrorResponse{}, false
 	}
 
 	return result, true
@@ -14,3 +14,22 @@ func RandomNumber(n int) (string, error) {
 
 	return fmt.Sprintf("%04d", bigInt.Int64()), nil
 }
+
+const randomStringAlphabet = "abcdefghijkmnpqrstuvwxyz23456789"
+
+// RandomString returns cryptographically secure random string of given length,
+// similar looking characters are excluded from alphabet
+func RandomString(n int) (string, error) {
+	b := make([]byte, n)
+	max := big.NewInt(int64(len(randomStringAlphabet)))
+
+	for i := range b {
+		bigInt, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = randomStringAlphabet[bigInt.Int64()]
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,32 @@
+package helpers
+
+import (
+	"context"
+
+	"github.com/abdivasiyev/project_template/internal/models"
+)
+
+// noCompanyID is never used by companies, tenant with it has no access to rows of any company
+const noCompanyID = "00000000-0000-0000-0000-000000000000"
+
+// GetTenant returns tenant of authenticated request,
+// calls without tenant have no access to rows of any company
+func GetTenant(ctx context.Context) models.Tenant {
+	tenant, ok := ctx.Value(models.TenantKey).(models.Tenant)
+	if !ok {
+		return models.Tenant{CompanyID: noCompanyID}
+	}
+
+	return tenant
+}
+
+// WithTenant returns copy of context limited to given tenant
+func WithTenant(ctx context.Context, tenant models.Tenant) context.Context {
+	return context.WithValue(ctx, models.TenantKey, tenant)
+}
+
+// WithSystemTenant returns copy of context for trusted internal callers (login, background jobs),
+// which are not limited to any company
+func WithSystemTenant(ctx context.Context) context.Context {
+	return WithTenant(ctx, models.Tenant{All: true})
+}
@@ -9,3 +9,14 @@ func TimeToString(t time.Time, layout string, valid bool) string {
 
 	return t.Format(layout)
 }
+
+// ParseTime parses value with first matching layout
+func ParseTime(value string, layouts ...string) (t time.Time, err error) {
+	for _, layout := range layouts {
+		if t, err = time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	return t, err
+}
@@ -59,14 +59,34 @@ func jsonWithError(c *gin.Context, params Params) bool {
 		resp.ErrorCode = http.StatusBadRequest
 		resp.ErrorMessage = jwt.ErrExpiredToken.Error()
 		switchedErr = !switchedErr
+	case errors.Is(params.Err, jwt.ErrRevokedToken):
+		resp.ErrorCode = http.StatusUnauthorized
+		resp.ErrorMessage = jwt.ErrRevokedToken.Error()
+		switchedErr = !switchedErr
+	case errors.Is(params.Err, jwt.ErrReusedToken):
+		resp.ErrorCode = http.StatusUnauthorized
+		resp.ErrorMessage = jwt.ErrReusedToken.Error()
+		switchedErr = !switchedErr
 	case errors.Is(params.Err, io.EOF) || errors.Is(params.Err, io.ErrUnexpectedEOF):
 		resp.ErrorCode = http.StatusBadRequest
 		resp.ErrorMessage = http.StatusText(http.StatusBadRequest)
 		switchedErr = !switchedErr
+	case errors.Is(params.Err, models.ErrUnauthorized):
+		resp.ErrorCode = http.StatusUnauthorized
+		resp.ErrorMessage = models.ErrUnauthorized.Error()
+		switchedErr = !switchedErr
+	case errors.Is(params.Err, models.ErrConflict):
+		resp.ErrorCode = http.StatusConflict
+		resp.ErrorMessage = http.StatusText(http.StatusConflict)
+		switchedErr = !switchedErr
 	case errors.Is(params.Err, models.ErrForbidden):
 		resp.ErrorCode = http.StatusForbidden
 		resp.ErrorMessage = "you are not allowed to perform this action"
 		switchedErr = !switchedErr
+	case errors.Is(params.Err, models.ErrTooManyTries):
+		resp.ErrorCode = http.StatusTooManyRequests
+		resp.ErrorMessage = "too many failed attempts, try again later"
+		switchedErr = !switchedErr
 	}
 
 	if switchedErr {
@@ -1,50 +1,252 @@
 package security
 
 import (
+	"context"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/abdivasiyev/project_template/internal/models"
 	"github.com/abdivasiyev/project_template/pkg/security/jwt"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
 )
 
-func (p *handler) GenerateToken(user models.GetUserResponse) (string, string, error) {
+const (
+	accessTokenDuration  = 24 * time.Hour
+	refreshTokenDuration = 2 * 24 * time.Hour
+)
+
+func (p *handler) GenerateToken(ctx context.Context, user models.GetUserResponse) (string, string, error) {
+	return p.generateToken(ctx, user, uuid.New().String())
+}
+
+func (p *handler) RotateToken(ctx context.Context, refreshToken string, user models.GetUserResponse) (string, string, error) {
 	j, err := jwt.NewJwt(p.jwtSecret)
 
 	if err != nil {
 		return "", "", err
 	}
 
-	accessToken, err := j.CreateToken(user, 24*time.Hour, false)
+	payload, err := j.VerifyToken(refreshToken)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	if !payload.IsRefreshToken {
+		return "", "", jwt.ErrInvalidToken
+	}
+
+	if err = p.checkRevoked(ctx, payload); err != nil {
+		return "", "", err
+	}
+
+	// tokens issued before rotation was introduced start new family,
+	// presented token is revoked so it can not be exchanged again
+	if payload.Family == "" {
+		if err = p.revokePayload(ctx, payload); err != nil {
+			return "", "", errors.Wrap(err, "could not revoke refresh token")
+		}
+		return p.GenerateToken(ctx, user)
+	}
 
+	accessToken, refreshToken, refreshPayload, err := p.createTokens(user, payload.Family)
 	if err != nil {
 		return "", "", err
 	}
 
-	refreshToken, err := j.CreateToken(user, 2*24*time.Hour, true)
+	// only the latest refresh token of family can be used, it is swapped atomically,
+	// so the same token can not be exchanged twice by parallel requests
+	swapped, err := p.cache.CompareAndSet(ctx, familyKey(payload.Family), payload.Id, refreshPayload.Id, refreshTokenDuration)
+	if err != nil {
+		return "", "", errors.Wrap(err, "could not rotate token family")
+	}
+
+	if swapped {
+		return accessToken, refreshToken, nil
+	}
+
+	if _, err = p.cache.Get(ctx, familyKey(payload.Family)); err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return "", "", jwt.ErrInvalidToken
+		}
+		return "", "", errors.Wrap(err, "could not get token family")
+	}
+
+	// older token of family means token was stolen, so whole family is revoked
+	if err = p.RevokeFamily(ctx, payload.Family); err != nil {
+		return "", "", err
+	}
+
+	return "", "", jwt.ErrReusedToken
+}
 
+func (p *handler) generateToken(ctx context.Context, user models.GetUserResponse, family string) (string, string, error) {
+	accessToken, refreshToken, refreshPayload, err := p.createTokens(user, family)
 	if err != nil {
 		return "", "", err
 	}
 
+	if err = p.cache.Set(ctx, familyKey(family), refreshPayload.Id, refreshTokenDuration); err != nil {
+		return "", "", errors.Wrap(err, "could not save token family")
+	}
+
 	return accessToken, refreshToken, nil
 }
 
-func (p *handler) VerifyToken(token string, isRefreshToken bool) (models.GetUserResponse, error) {
+func (p *handler) createTokens(user models.GetUserResponse, family string) (string, string, *jwt.Payload, error) {
 	j, err := jwt.NewJwt(p.jwtSecret)
 
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	accessToken, _, err := j.CreateToken(user, accessTokenDuration, false, family)
+
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	refreshToken, refreshPayload, err := j.CreateToken(user, refreshTokenDuration, true, family)
+
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	return accessToken, refreshToken, refreshPayload, nil
+}
+
+func (p *handler) VerifyToken(ctx context.Context, token string, isRefreshToken bool) (models.GetUserResponse, error) {
+	payload, err := p.VerifyPayload(ctx, token, isRefreshToken)
+
 	if err != nil {
 		return models.GetUserResponse{}, err
 	}
 
+	return payload.User, nil
+}
+
+func (p *handler) VerifyPayload(ctx context.Context, token string, isRefreshToken bool) (*jwt.Payload, error) {
+	j, err := jwt.NewJwt(p.jwtSecret)
+
+	if err != nil {
+		return nil, err
+	}
+
 	payload, err := j.VerifyToken(token)
 
 	if err != nil {
-		return models.GetUserResponse{}, err
+		return nil, err
 	}
 
 	if payload.IsRefreshToken != isRefreshToken {
-		return models.GetUserResponse{}, jwt.ErrInvalidToken
+		return nil, jwt.ErrInvalidToken
 	}
 
-	return payload.User, nil
+	if err = p.checkRevoked(ctx, payload); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+func (p *handler) RevokeToken(ctx context.Context, token string) error {
+	j, err := jwt.NewJwt(p.jwtSecret)
+
+	if err != nil {
+		return err
+	}
+
+	payload, err := j.VerifyToken(token)
+
+	if err != nil {
+		return err
+	}
+
+	return p.revokePayload(ctx, payload)
+}
+
+func (p *handler) revokePayload(ctx context.Context, payload *jwt.Payload) error {
+	// keep revoked token id only while token itself is valid
+	duration := time.Until(time.Unix(payload.ExpiresAt, 0))
+	if duration <= 0 {
+		return nil
+	}
+
+	return p.cache.Set(ctx, revokedTokenKey(payload.Id), payload.User.ID, duration)
+}
+
+func (p *handler) RevokeUserTokens(ctx context.Context, userID string) error {
+	// all tokens issued before this moment are expired after refresh token lifetime,
+	// so there is no need to keep revocation time longer
+	return p.cache.Set(ctx, revokedUserKey(userID), time.Now().UTC().UnixNano(), refreshTokenDuration)
+}
+
+func (p *handler) RevokeFamily(ctx context.Context, family string) error {
+	if err := p.cache.Set(ctx, revokedFamilyKey(family), time.Now().UTC().UnixNano(), refreshTokenDuration); err != nil {
+		return errors.Wrap(err, "could not revoke token family")
+	}
+
+	if err := p.cache.Delete(ctx, familyKey(family)); err != nil {
+		return errors.Wrap(err, "could not delete token family")
+	}
+
+	return nil
+}
+
+func (p *handler) checkRevoked(ctx context.Context, payload *jwt.Payload) error {
+	_, err := p.cache.Get(ctx, revokedTokenKey(payload.Id))
+	if err == nil {
+		return jwt.ErrRevokedToken
+	}
+	if !errors.Is(err, models.ErrNotFound) {
+		return errors.Wrap(err, "could not check revoked token")
+	}
+
+	if payload.Family != "" {
+		_, err = p.cache.Get(ctx, revokedFamilyKey(payload.Family))
+		if err == nil {
+			return jwt.ErrRevokedToken
+		}
+		if !errors.Is(err, models.ErrNotFound) {
+			return errors.Wrap(err, "could not check revoked token family")
+		}
+	}
+
+	value, err := p.cache.Get(ctx, revokedUserKey(payload.User.ID))
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil
+		}
+		return errors.Wrap(err, "could not check revoked user tokens")
+	}
+
+	revokedAt, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "could not parse revocation time")
+	}
+
+	// revocation time is kept in nanoseconds, so tokens issued right after it in the same second stay valid
+	if !payload.IssuedAtTime().After(time.Unix(0, revokedAt)) {
+		return jwt.ErrRevokedToken
+	}
+
+	return nil
+}
+
+func revokedTokenKey(tokenID string) string {
+	return fmt.Sprintf("auth:revoked:%s", tokenID)
+}
+
+func revokedUserKey(userID string) string {
+	return fmt.Sprintf("auth:revoked:user:%s", userID)
+}
+
+func revokedFamilyKey(family string) string {
+	return fmt.Sprintf("auth:revoked:family:%s", family)
+}
+
+func familyKey(family string) string {
+	return fmt.Sprintf("auth:family:%s", family)
 }
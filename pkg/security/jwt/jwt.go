@@ -13,6 +13,8 @@ import (
 var (
 	ErrInvalidToken = errors.New("invalid jwt token")
 	ErrExpiredToken = errors.New("expired jwt token")
+	ErrRevokedToken = errors.New("revoked jwt token")
+	ErrReusedToken  = errors.New("reused jwt token")
 )
 
 type Jwt struct {
@@ -28,14 +30,19 @@ func NewJwt(secretKey string) (*Jwt, error) {
 	return &Jwt{secretKey}, nil
 }
 
-func (j *Jwt) CreateToken(user models.GetUserResponse, duration time.Duration, isRefreshToken bool) (string, error) {
-	payload, err := NewPayload(user, duration, isRefreshToken)
+func (j *Jwt) CreateToken(user models.GetUserResponse, duration time.Duration, isRefreshToken bool, family string) (string, *Payload, error) {
+	payload, err := NewPayload(user, duration, isRefreshToken, family)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	jwtToken := jwtGo.NewWithClaims(jwtGo.SigningMethodHS256, payload)
-	return jwtToken.SignedString([]byte(j.secretKey))
+	token, err := jwtToken.SignedString([]byte(j.secretKey))
+	if err != nil {
+		return "", nil, err
+	}
+
+	return token, payload, nil
 }
 
 func (j *Jwt) VerifyToken(token string) (*Payload, error) {
@@ -12,10 +12,12 @@ import (
 type Payload struct {
 	jwtGo.StandardClaims
 	IsRefreshToken bool                   `json:"is_refresh_token"`
+	Family         string                 `json:"family,omitempty"`
+	IssuedAtNano   int64                  `json:"iat_ns,omitempty"`
 	User           models.GetUserResponse `json:"user"`
 }
 
-func NewPayload(user models.GetUserResponse, duration time.Duration, isRefreshToken bool) (*Payload, error) {
+func NewPayload(user models.GetUserResponse, duration time.Duration, isRefreshToken bool, family string) (*Payload, error) {
 	issuedAt := time.Now().UTC()
 	expiresAt := issuedAt.Add(duration).Unix()
 
@@ -28,6 +30,8 @@ func NewPayload(user models.GetUserResponse, duration time.Duration, isRefreshTo
 			},
 		},
 		IsRefreshToken: isRefreshToken,
+		Family:         family,
+		IssuedAtNano:   issuedAt.UnixNano(),
 		StandardClaims: jwtGo.StandardClaims{
 			ExpiresAt: expiresAt,
 			Id:        uuid.New().String(),
@@ -36,6 +40,16 @@ func NewPayload(user models.GetUserResponse, duration time.Duration, isRefreshTo
 	}, nil
 }
 
+// IssuedAtTime returns issue time of token,
+// tokens issued before sub-second issue time was added are rounded to seconds
+func (p *Payload) IssuedAtTime() time.Time {
+	if p.IssuedAtNano != 0 {
+		return time.Unix(0, p.IssuedAtNano)
+	}
+
+	return time.Unix(p.IssuedAt, 0)
+}
+
 func (p *Payload) Valid() error {
 	if time.Unix(p.ExpiresAt, 0).Before(time.Now().UTC()) {
 		return ErrExpiredToken
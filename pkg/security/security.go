@@ -1,19 +1,42 @@
 package security
 
 import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
 	"github.com/abdivasiyev/project_template/config"
 	"github.com/abdivasiyev/project_template/internal/models"
+	"github.com/abdivasiyev/project_template/pkg/security/jwt"
+	"github.com/abdivasiyev/project_template/pkg/storage"
 	"go.uber.org/fx"
 )
 
 var Module = fx.Provide(New)
 
 type Handler interface {
-	GenerateToken(user models.GetUserResponse) (accessToken string, refreshToken string, err error)
-	VerifyToken(token string, isRefreshToken bool) (user models.GetUserResponse, err error)
+	GenerateToken(ctx context.Context, user models.GetUserResponse) (accessToken string, refreshToken string, err error)
+	// RotateToken issues new token pair in family of refresh token,
+	// reuse of already rotated refresh token revokes whole family
+	RotateToken(ctx context.Context, refreshToken string, user models.GetUserResponse) (accessToken string, newRefreshToken string, err error)
+	VerifyToken(ctx context.Context, token string, isRefreshToken bool) (user models.GetUserResponse, err error)
+	// VerifyPayload is same as VerifyToken, but returns whole token payload with token id and family
+	VerifyPayload(ctx context.Context, token string, isRefreshToken bool) (payload *jwt.Payload, err error)
+	// RevokeToken adds token to denylist until it expires
+	RevokeToken(ctx context.Context, token string) error
+	// RevokeUserTokens revokes all tokens of user issued before now
+	RevokeUserTokens(ctx context.Context, userID string) error
+	// RevokeFamily revokes all tokens issued within one login session
+	RevokeFamily(ctx context.Context, family string) error
 	GenerateHash(plainText string) (hashedText string, err error)
 	CompareHash(plainText string, hashedText string) (valid bool, err error)
 	Md5Sum(value any) (string, error)
+	// GenerateTotpSecret returns new TOTP secret with provisioning uri for authenticator applications
+	GenerateTotpSecret(account string) (secret string, uri string, err error)
+	// EncryptTotpSecret encrypts TOTP secret with application key before it is stored
+	EncryptTotpSecret(secret string) (encryptedSecret string, err error)
+	// ValidateTotp checks code against secret encrypted by EncryptTotpSecret
+	ValidateTotp(encryptedSecret string, code string) (valid bool, err error)
 }
 
 type handler struct {
@@ -23,14 +46,28 @@ type handler struct {
 	parallelism uint8
 	saltLength  uint32
 	keyLength   uint32
+	totpIssuer  string
+	totpKey     []byte
+	cache       storage.Cacher
 }
 
 type Params struct {
 	fx.In
 	Config config.Config
+	Cache  storage.Cacher
 }
 
-func New(params Params) Handler {
+const minTotpKeySize = 32
+
+func New(params Params) (Handler, error) {
+	configuredKey := params.Config.GetString(config.SecurityTotpKeyKey)
+	if len(configuredKey) < minTotpKeySize {
+		return nil, fmt.Errorf("invalid totp key size: must be at least %d characters", minTotpKeySize)
+	}
+
+	// configured value is turned into AES-256 key
+	totpKey := sha256.Sum256([]byte(configuredKey))
+
 	return &handler{
 		jwtSecret:   params.Config.GetString(config.JwtSecretKey),
 		memory:      params.Config.GetUInt32(config.SecurityMemoryKey),
@@ -38,5 +75,8 @@ func New(params Params) Handler {
 		parallelism: params.Config.GetUInt8(config.SecurityParallelismKey),
 		saltLength:  params.Config.GetUInt32(config.SecuritySaltLengthKey),
 		keyLength:   params.Config.GetUInt32(config.SecurityKeyLengthKey),
-	}
+		totpIssuer:  params.Config.GetString(config.SecurityTotpIssuerKey),
+		totpKey:     totpKey[:],
+		cache:       params.Cache,
+	}, nil
 }
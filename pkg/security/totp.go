@@ -0,0 +1,33 @@
+package security
+
+import (
+	"time"
+
+	"github.com/abdivasiyev/project_template/pkg/security/totp"
+	"github.com/pkg/errors"
+)
+
+// totpSkew allows codes of one previous and one next time step
+const totpSkew = 1
+
+func (p *handler) GenerateTotpSecret(account string) (string, string, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return "", "", err
+	}
+
+	return secret, totp.ProvisioningURI(p.totpIssuer, account, secret), nil
+}
+
+func (p *handler) EncryptTotpSecret(secret string) (string, error) {
+	return encrypt(p.totpKey, secret)
+}
+
+func (p *handler) ValidateTotp(encryptedSecret, code string) (bool, error) {
+	secret, err := decrypt(p.totpKey, encryptedSecret)
+	if err != nil {
+		return false, errors.Wrap(err, "could not decrypt totp secret")
+	}
+
+	return totp.Validate(secret, code, time.Now().UTC(), totpSkew)
+}
@@ -0,0 +1,78 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is SHA1 seed of RFC 6238 Appendix B, "12345678901234567890" in base32
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestHotpRFC6238(t *testing.T) {
+	key, err := encoding.DecodeString(rfc6238Secret)
+	if err != nil {
+		t.Fatalf("could not decode secret: %v", err)
+	}
+
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "94287082"},
+		{unix: 1111111109, code: "07081804"},
+		{unix: 1111111111, code: "14050471"},
+		{unix: 1234567890, code: "89005924"},
+		{unix: 2000000000, code: "69279037"},
+		{unix: 20000000000, code: "65353130"},
+	}
+
+	for _, tt := range tests {
+		if code := hotp(key, uint64(tt.unix/period), 8); code != tt.code {
+			t.Errorf("hotp at %d = %s, want %s", tt.unix, code, tt.code)
+		}
+
+		code, err := GenerateCode(rfc6238Secret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("could not generate code at %d: %v", tt.unix, err)
+		}
+
+		if want := tt.code[len(tt.code)-digits:]; code != want {
+			t.Errorf("GenerateCode at %d = %s, want %s", tt.unix, code, want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	code, err := GenerateCode(rfc6238Secret, now.Add(-period*time.Second))
+	if err != nil {
+		t.Fatalf("could not generate code: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		code  string
+		skew  int
+		valid bool
+	}{
+		{name: "previous step within skew", code: code, skew: 1, valid: true},
+		{name: "previous step without skew", code: code, skew: 0, valid: false},
+		{name: "wrong length", code: code[1:], skew: 1, valid: false},
+	}
+
+	for _, tt := range tests {
+		valid, err := Validate(rfc6238Secret, tt.code, now, tt.skew)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		if valid != tt.valid {
+			t.Errorf("%s: valid = %v, want %v", tt.name, valid, tt.valid)
+		}
+	}
+
+	if _, err = Validate("not base32!", code, now, 1); err != ErrInvalidSecret {
+		t.Errorf("invalid secret: err = %v, want %v", err, ErrInvalidSecret)
+	}
+}
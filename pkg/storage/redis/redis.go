@@ -16,6 +16,15 @@ import (
 
 var Module = fx.Provide(NewRedisCache)
 
+// compareAndSetScript replaces value of key only when it equals to expected one, missing key is never replaced
+var compareAndSetScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+	return 1
+end
+return 0
+`)
+
 type Params struct {
 	fx.In
 	Config config.Config
@@ -114,6 +123,49 @@ func (c *redisCache) Delete(ctx context.Context, keys ...string) error {
 	return nil
 }
 
+func (c *redisCache) GetDel(ctx context.Context, key string) (string, error) {
+	value, err := c.client.GetDel(ctx, key).Result()
+
+	if err != nil {
+		if err == redis.Nil {
+			c.log.Warn("key not found", zap.Any("key", key))
+			return "", models.ErrNotFound
+		}
+		c.log.Error("could not get and delete key", zap.Any("key", key), zap.Error(err))
+		return "", err
+	}
+
+	return value, nil
+}
+
+func (c *redisCache) Incr(ctx context.Context, key string, duration time.Duration) (int64, error) {
+	var incr *redis.IntCmd
+
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, duration)
+		return nil
+	})
+	if err != nil {
+		c.log.Error("could not increment value", zap.Any("key", key), zap.Error(err))
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
+
+func (c *redisCache) CompareAndSet(ctx context.Context, key string, oldValue, newValue any, duration time.Duration) (bool, error) {
+	c.log.Debug("compare and set value", zap.Any("key", key), zap.Any("value", newValue))
+
+	swapped, err := compareAndSetScript.Run(ctx, c.client, []string{key}, oldValue, newValue, duration.Milliseconds()).Int()
+	if err != nil {
+		c.log.Error("could not compare and set value", zap.Any("key", key), zap.Error(err))
+		return false, err
+	}
+
+	return swapped == 1, nil
+}
+
 func (c *redisCache) Close() error {
 	return c.client.Close()
 }
@@ -25,4 +25,10 @@ type Cacher interface {
 	GetObj(ctx context.Context, key string, value any) error
 	SetObj(ctx context.Context, key string, value any, duration time.Duration) error
 	Delete(ctx context.Context, keys ...string) error
+	// GetDel atomically returns value of key and deletes it, so only one caller gets the value
+	GetDel(ctx context.Context, key string) (string, error)
+	// Incr atomically increments counter of key and extends its lifetime to given duration
+	Incr(ctx context.Context, key string, duration time.Duration) (int64, error)
+	// CompareAndSet atomically replaces value of key only when current value equals to old one
+	CompareAndSet(ctx context.Context, key string, oldValue, newValue any, duration time.Duration) (bool, error)
 }
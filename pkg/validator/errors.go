@@ -1,6 +1,9 @@
 package validator
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type CustomError interface {
 	Field() string
@@ -30,3 +33,15 @@ func (v ValidationError) Field() string {
 func (v ValidationError) Message() string {
 	return v.message
 }
+
+// ValidationErrors holds several field errors which are returned together
+type ValidationErrors []ValidationError
+
+func (v ValidationErrors) Error() string {
+	messages := make([]string, 0, len(v))
+	for _, err := range v {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}